@@ -0,0 +1,246 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// ESPN athlete profile and game log, from the common v3 API on the site.web host
+//
+//Athlete: https://site.web.api.espn.com/apis/common/v3/sports/basketball/nba/athletes/:athleteId
+//GameLog: https://site.web.api.espn.com/apis/common/v3/sports/basketball/nba/athletes/:athleteId/gamelog?season=2020
+//
+// {"athlete":{"id":"3012","fullName":"Kyle Lowry",...,"team":{"id":"28","abbreviation":"TOR"},
+//	"college":{"id":"222","name":"Villanova"},"draft":{"displayText":"2006: Rd 1, Pk 24 (MEM)","round":1,"year":2006,
+//	"selection":24,"team":{"abbreviation":"MEM"}},"injuries":[{"status":"Out","date":"2020-01-10T23:56Z",
+//	"details":{"type":"Knee","side":"Left","detail":"Soreness","returnDate":"2020-01-20"}}],
+//	"statsSummary":{"displayName":"2019-20 Regular Season Stats","statistics":[{"name":"avgPoints","shortDisplayName":"PTS",
+//	"displayValue":"19.4","value":19.4},...]}}}
+//
+// {"labels":["MIN","FG",...,"PTS"],"names":["minutes","fieldGoalsMade-fieldGoalsAttempted",...,"points"],
+//	"events":{"401161559":{"id":"401161559","gameDate":"2019-10-23T00:00:00.000+00:00","atVs":"vs","gameResult":"W",
+//	"team":{"id":"28"},"opponent":{"id":"13"}}},
+//	"seasonTypes":[{"displayName":"2019-20 Regular Season","categories":[{"type":"event","events":[{"eventId":"401161559",
+//	"stats":["36","7-15",...,"22"]}]}]}]}
+
+import (
+	"go-moneyball/moneyball/ms"
+	"sort"
+	"time"
+)
+
+const (
+	//EspnWebBaseURL is the URL basis for the common API's, e.g. athletes
+	EspnWebBaseURL = "https://site.web.api.espn.com/"
+	//EspnCommonURLPrefix is the URL filepath prefix for calls to v3 of the common API's
+	EspnCommonURLPrefix = "apis/common/v3/sports/"
+)
+
+//AthletePath returns the common v3 path of an athlete of the league, resource is appended e.g. "gamelog"
+func (l LeagueSlug) AthletePath(athleteID string, resource string) string {
+	path := l.path(EspnCommonURLPrefix, "athletes/"+athleteID)
+	if resource != "" {
+		path = path + "/" + resource
+	}
+	return path
+}
+
+//AthleteProfile ...
+type AthleteProfile struct {
+	Athlete AthleteDetail `json:"athlete"`
+	League  LeagueSlug    `json:"-"` // set by the fetcher
+}
+
+//AthleteDetail ... the bio of the roster athlete plus team, college, draft, injuries and season stats
+type AthleteDetail struct {
+	RosterAthlete
+	Team         *RosterTeam          `json:"team,omitempty"`
+	College      *AthleteCollege      `json:"college,omitempty"`
+	Draft        *AthleteDraft        `json:"draft,omitempty"`
+	Injuries     []AthleteInjury      `json:"injuries,omitempty"`
+	StatsSummary *AthleteStatsSummary `json:"statsSummary,omitempty"`
+}
+
+//AthleteCollege ...
+type AthleteCollege struct {
+	ID   string `json:"id"`   // "222"
+	Name string `json:"name"` // "Villanova"
+}
+
+//AthleteDraft ...
+type AthleteDraft struct {
+	DisplayText string     `json:"displayText,omitempty"` // "2006: Rd 1, Pk 24 (MEM)"
+	Round       int        `json:"round"`                 // 1
+	Year        int        `json:"year"`                  // 2006
+	Selection   int        `json:"selection"`             // 24
+	Team        RosterTeam `json:"team"`
+}
+
+//AthleteInjury ...
+type AthleteInjury struct {
+	Status  string               `json:"status"` // "Out"
+	Date    *espnTime            `json:"date,omitempty"`
+	Details AthleteInjuryDetails `json:"details"`
+}
+
+//AthleteInjuryDetails ...
+type AthleteInjuryDetails struct {
+	Type       string `json:"type,omitempty"`       // "Knee"
+	Side       string `json:"side,omitempty"`       // "Left"
+	Detail     string `json:"detail,omitempty"`     // "Soreness"
+	ReturnDate string `json:"returnDate,omitempty"` // "2020-01-20"
+}
+
+//AthleteStatsSummary ...
+type AthleteStatsSummary struct {
+	DisplayName string             `json:"displayName"` // "2019-20 Regular Season Stats"
+	Statistics  []AthleteStatistic `json:"statistics,omitempty"`
+}
+
+//AthleteStatistic ...
+type AthleteStatistic struct {
+	Name             string  `json:"name"`                       // "avgPoints"
+	ShortDisplayName string  `json:"shortDisplayName,omitempty"` // "PTS"
+	DisplayValue     string  `json:"displayValue"`               // "19.4"
+	Value            float64 `json:"value"`                      // 19.4
+}
+
+//MarshalMS marshals the profile to a ms.Player
+func (ap *AthleteProfile) MarshalMS() (*ms.Player, error) {
+	a := ap.Athlete
+	p := a.marshalMSPlayer()
+	p.League = ap.League.MSLeague()
+	if a.Team != nil {
+		if canon := ms.TeamRegistryFor(ap.League.MSLeague()).ByESPNID(a.Team.ID); canon != nil {
+			tm := *canon
+			p.Team = &tm
+		} else {
+			p.Team = &ms.Team{TeamIDESPN: a.Team.ID, Abbreviation: a.Team.Abbreviation, Name: a.Team.DisplayName}
+		}
+	}
+	if a.College != nil {
+		p.College = a.College.Name
+	}
+	if a.Draft != nil {
+		p.Draft = &ms.PlayerDraft{Year: a.Draft.Year, Round: a.Draft.Round, Pick: a.Draft.Selection,
+			TeamAbbreviation: a.Draft.Team.Abbreviation, Description: a.Draft.DisplayText}
+	}
+	for _, inj := range a.Injuries {
+		injury := ms.PlayerInjury{Status: inj.Status, Type: inj.Details.Type, Side: inj.Details.Side, Detail: inj.Details.Detail}
+		if inj.Date != nil {
+			d := time.Time(*inj.Date)
+			injury.Date = &d
+		}
+		if rd, err := time.Parse("2006-01-02", inj.Details.ReturnDate); err == nil {
+			injury.ReturnDate = &rd
+		}
+		p.Injuries = append(p.Injuries, &injury)
+	}
+	if a.StatsSummary != nil {
+		split := ms.PlayerSplit{Name: a.StatsSummary.DisplayName}
+		stats := []*ms.Stat{}
+		for _, st := range a.StatsSummary.Statistics {
+			stats = append(stats, &ms.Stat{Key: st.ShortDisplayName, LongKey: st.Name, Value: st.Value})
+		}
+		split.Stats = validateStats("athlete "+a.ID+" "+split.Name, stats)
+		p.Splits = append(p.Splits, &split)
+	}
+	return p, nil
+}
+
+//AthleteGameLog ... an athlete's per game stat lines for a season, stats line up with Labels/Names
+type AthleteGameLog struct {
+	Labels      []string                `json:"labels,omitempty"` // ["MIN","FG",...]
+	Names       []string                `json:"names,omitempty"`  // ["minutes","fieldGoalsMade-fieldGoalsAttempted",...]
+	Events      map[string]GameLogEvent `json:"events,omitempty"` // by event id
+	SeasonTypes []GameLogSeasonType     `json:"seasonTypes,omitempty"`
+	AthleteID   string                  `json:"-"` // set by the fetcher
+	League      LeagueSlug              `json:"-"` // set by the fetcher
+}
+
+//GameLogEvent ...
+type GameLogEvent struct {
+	ID         string     `json:"id"`                   // "401161559"
+	GameDate   *time.Time `json:"gameDate,omitempty"`   // "2019-10-23T00:00:00.000+00:00"
+	AtVs       string     `json:"atVs,omitempty"`       // "vs" or "@"
+	GameResult string     `json:"gameResult,omitempty"` // "W"
+	Team       RosterTeam `json:"team"`
+	Opponent   RosterTeam `json:"opponent"`
+}
+
+//GameLogSeasonType ... e.g. the regular season, its categories are months
+type GameLogSeasonType struct {
+	DisplayName string            `json:"displayName"` // "2019-20 Regular Season"
+	Categories  []GameLogCategory `json:"categories,omitempty"`
+}
+
+//GameLogCategory ... events of type "event" hold game lines, "total" only totals
+type GameLogCategory struct {
+	Type   string         `json:"type"` // "event"
+	Events []GameLogEntry `json:"events,omitempty"`
+}
+
+//GameLogEntry ...
+type GameLogEntry struct {
+	EventID string   `json:"eventId"` // "401161559"
+	Stats   []string `json:"stats"`   // ["36","7-15",...]
+}
+
+//MarshalMS marshals the game log to a ms.GamePlayersStats per game, in game date order
+func (gl *AthleteGameLog) MarshalMS() ([]*ms.GamePlayersStats, error) {
+	games := []*ms.GamePlayersStats{}
+	dates := map[*ms.GamePlayersStats]time.Time{}
+	for _, st := range gl.SeasonTypes {
+		for _, category := range st.Categories {
+			for _, entry := range category.Events {
+				ps := ms.GamePlayersStats{PlayerID: gl.AthleteID, GameID: entry.EventID}
+				if ev, ok := gl.Events[entry.EventID]; ok {
+					ps.TeamID = ev.Team.ID
+					if ev.GameDate != nil {
+						dates[&ps] = *ev.GameDate
+					}
+				}
+				stats := []*ms.Stat{}
+				for i, v := range entry.Stats {
+					stat := ms.Stat{Value: statValue(v)}
+					if i < len(gl.Labels) {
+						stat.Key = gl.Labels[i]
+					}
+					if i < len(gl.Names) {
+						stat.LongKey = gl.Names[i]
+					}
+					stats = append(stats, &stat)
+				}
+				ps.Stats = validateStats("athlete "+gl.AthleteID+" game "+entry.EventID, stats)
+				games = append(games, &ps)
+			}
+		}
+	}
+	sort.SliceStable(games, func(i, j int) bool { return dates[games[i]].Before(dates[games[j]]) })
+	return games, nil
+}
@@ -0,0 +1,95 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"go-moneyball/moneyball/ms"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tAthlete = `{"athlete":{"id":"3012","fullName":"Kyle Lowry","displayName":"Kyle Lowry","shortName":"K. Lowry","weight":196.0,"height":72.0,
+"dateOfBirth":"1986-03-25T08:00Z","headshot":{"href":"https://a.espncdn.com/i/headshots/nba/players/full/3012.png","alt":"Kyle Lowry"},
+"jersey":"7","position":{"name":"Point Guard","abbreviation":"PG"},"experience":{"years":13},"active":true,
+"team":{"id":"28","abbreviation":"TOR","displayName":"Toronto Raptors"},"college":{"id":"222","name":"Villanova"},
+"draft":{"displayText":"2006: Rd 1, Pk 24 (MEM)","round":1,"year":2006,"selection":24,"team":{"id":"29","abbreviation":"MEM"}},
+"injuries":[{"status":"Out","date":"2020-01-10T23:56Z","details":{"type":"Knee","side":"Left","detail":"Soreness","returnDate":"2020-01-20"}}],
+"statsSummary":{"displayName":"2019-20 Regular Season Stats","statistics":[{"name":"avgPoints","shortDisplayName":"PTS","displayValue":"19.4","value":19.4}]}}}`
+
+const tGameLog = `{"labels":["MIN","FG","REB","XYZ","PTS"],"names":["minutes","fieldGoalsMade-fieldGoalsAttempted","totalRebounds","madeUpStat","points"],
+"events":{"401161559":{"id":"401161559","gameDate":"2019-10-23T00:00:00.000+00:00","atVs":"vs","gameResult":"W","team":{"id":"28"},"opponent":{"id":"13"}},
+"401161570":{"id":"401161570","gameDate":"2019-10-26T00:00:00.000+00:00","atVs":"@","gameResult":"W","team":{"id":"28"},"opponent":{"id":"2"}}},
+"seasonTypes":[{"displayName":"2019-20 Regular Season","categories":[{"type":"event","events":[
+{"eventId":"401161570","stats":["34","6-14","5","1","20"]},{"eventId":"401161559","stats":["36","7-15","4","1","22"]}]},{"type":"total"}]}]}`
+
+func TestAthleteProfileMarshalMS(t *testing.T) {
+	ap := AthleteProfile{League: NBA}
+	assert.Nil(t, json.Unmarshal([]byte(tAthlete), &ap))
+	p, err := ap.MarshalMS()
+	assert.Nil(t, err)
+	assert.Equal(t, "3012", p.IDESPN)
+	assert.Equal(t, 72, p.Height)
+	assert.Equal(t, "Villanova", p.College)
+	assert.Equal(t, &ms.PlayerDraft{Year: 2006, Round: 1, Pick: 24, TeamAbbreviation: "MEM", Description: "2006: Rd 1, Pk 24 (MEM)"}, p.Draft)
+	if assert.Equal(t, 1, len(p.Injuries)) {
+		assert.Equal(t, "Out", p.Injuries[0].Status)
+		assert.Equal(t, time.Date(2020, 1, 20, 0, 0, 0, 0, time.UTC), *p.Injuries[0].ReturnDate)
+	}
+	if assert.Equal(t, 1, len(p.Splits)) {
+		assert.Equal(t, 19.4, p.Splits[0].Stats[0].Value)
+		assert.Equal(t, "pointsPerGame", p.Splits[0].Stats[0].LongKey, "ESPN's avgPoints")
+	}
+	if assert.NotNil(t, p.Team) {
+		assert.Equal(t, "TOR", p.Team.Abbreviation)
+	}
+}
+
+func TestAthleteGameLogMarshalMS(t *testing.T) {
+	gl := AthleteGameLog{AthleteID: "3012"}
+	assert.Nil(t, json.Unmarshal([]byte(tGameLog), &gl))
+	games, err := gl.MarshalMS()
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(games)) {
+		assert.Equal(t, "401161559", games[0].GameID, "games are in date order")
+		assert.Equal(t, "28", games[0].TeamID)
+		assert.Equal(t, "3012", games[0].PlayerID)
+		assert.Equal(t, 22.0, games[0].Stat("points").Value)
+		assert.Equal(t, 6.0, games[1].Stat("FGM").Value, "made-attempted is split")
+		assert.Equal(t, 14.0, games[1].Stat("fieldGoalsAttempted").Value)
+		assert.Equal(t, 5.0, games[1].Stat("rebounds").Value, "ESPN's totalRebounds")
+		assert.Nil(t, games[1].Stat("madeUpStat"), "unknown stats are left out")
+		assert.Nil(t, games[1].Stat("XYZ"))
+	}
+	assert.Equal(t, "apis/common/v3/sports/basketball/wnba/athletes/3012/gamelog", WNBA.AthletePath("3012", "gamelog"))
+}
@@ -0,0 +1,240 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// the ESPN leagues we fetch, a LeagueSlug is the path segment after the SportSlug e.g.
+//	https://site.api.espn.com/apis/site/v2/sports/basketball/womens-college-basketball/scoreboard
+//	https://site.api.espn.com/apis/site/v2/sports/soccer/eng.1/scoreboard
+// ESPN names soccer leagues by country and tier ("eng.1", "ger.1"), any such slug is treated as soccer
+
+import (
+	"go-moneyball/moneyball/ms"
+	"strings"
+	"time"
+)
+
+//SportSlug ... ESPN's url name for a sport
+type SportSlug string
+
+const (
+	//Basketball ...
+	Basketball SportSlug = "basketball"
+	//Football is american football
+	Football SportSlug = "football"
+	//Hockey ...
+	Hockey SportSlug = "hockey"
+	//Baseball ...
+	Baseball SportSlug = "baseball"
+	//Soccer ...
+	Soccer SportSlug = "soccer"
+)
+
+//LeagueSlug ... ESPN's url name for a league
+type LeagueSlug string
+
+const (
+	//NBA ...
+	NBA LeagueSlug = "nba"
+	//WNBA ...
+	WNBA LeagueSlug = "wnba"
+	//MensCollege is NCAA Division I men's basketball
+	MensCollege LeagueSlug = "mens-college-basketball"
+	//WomensCollege is NCAA Division I women's basketball
+	WomensCollege LeagueSlug = "womens-college-basketball"
+	//NFL ...
+	NFL LeagueSlug = "nfl"
+	//CollegeFootball is NCAA Division I FBS football
+	CollegeFootball LeagueSlug = "college-football"
+	//NHL ...
+	NHL LeagueSlug = "nhl"
+	//MLB ...
+	MLB LeagueSlug = "mlb"
+	//MLS is Major League Soccer
+	MLS LeagueSlug = "usa.1"
+	//PremierLeague is the English Premier League
+	PremierLeague LeagueSlug = "eng.1"
+)
+
+//leagueDef ... what we know about a league beyond its slug
+type leagueDef struct {
+	sport    SportSlug
+	msLeague ms.League
+	aliases  []string   // other names LeagueFor accepts e.g. "ncaam"
+	rollover time.Month // month a new season starts, 0 for calendar year seasons
+	endYear  bool       // seasons spanning new year are named for the year they end
+}
+
+var leagueDefs = map[LeagueSlug]leagueDef{
+	NBA:             {Basketball, ms.LeagueNBA, nil, time.October, true},
+	WNBA:            {Basketball, ms.LeagueWNBA, nil, 0, false},
+	MensCollege:     {Basketball, ms.LeagueNCAAM, []string{"ncaam", "ncaab"}, time.October, true},
+	WomensCollege:   {Basketball, ms.LeagueNCAAW, []string{"ncaaw", "ncaawb"}, time.October, true},
+	NFL:             {Football, ms.LeagueNFL, nil, time.March, false},
+	CollegeFootball: {Football, ms.LeagueNCAAF, []string{"ncaaf"}, time.March, false},
+	NHL:             {Hockey, ms.LeagueNHL, nil, time.October, true},
+	MLB:             {Baseball, ms.LeagueMLB, nil, 0, false},
+	MLS:             {Soccer, ms.LeagueMLS, []string{"mls"}, 0, false},
+	PremierLeague:   {Soccer, ms.LeagueEPL, []string{"epl"}, time.August, false},
+}
+
+//Leagues lists the supported leagues
+var Leagues = []LeagueSlug{NBA, WNBA, MensCollege, WomensCollege, NFL, CollegeFootball, NHL, MLB, MLS, PremierLeague}
+
+//LeagueFor returns the slug for a league slug or abbreviation e.g. "mens-college-basketball" or "NCAAM", "" is the
+//NBA and unlisted soccer slugs e.g. "ger.1" are kept; false when the league is unknown e.g. "ncaa-m"
+func LeagueFor(s string) (LeagueSlug, bool) {
+	s = strings.ToLower(s)
+	if s == "" {
+		return NBA, true
+	}
+	for slug, def := range leagueDefs {
+		if s == string(slug) || s == strings.ToLower(string(def.msLeague)) {
+			return slug, true
+		}
+		for _, alias := range def.aliases {
+			if s == alias {
+				return slug, true
+			}
+		}
+	}
+	l := LeagueSlug(s)
+	return l, l.Known()
+}
+
+//def is what we know about the league, "" is the NBA and unlisted soccer leagues play the Premier League's season;
+//false when the league is unknown
+func (l LeagueSlug) def() (leagueDef, bool) {
+	if l == "" {
+		l = NBA
+	}
+	if def, ok := leagueDefs[l]; ok {
+		return def, true
+	}
+	if strings.Contains(string(l), ".") {
+		def := leagueDefs[PremierLeague]
+		def.msLeague = ms.League(strings.ToUpper(strings.Replace(string(l), ".", "", -1)))
+		def.aliases = nil
+		return def, true
+	}
+	return leagueDef{}, false
+}
+
+//Known is false for a league LeagueFor doesn't know, which the fetchers refuse rather than fetch as another league
+func (l LeagueSlug) Known() bool {
+	_, ok := l.def()
+	return ok
+}
+
+//Sport is the sport the league plays, unlisted leagues are soccer when named "country.tier" else basketball
+func (l LeagueSlug) Sport() SportSlug {
+	if def, ok := leagueDefs[l]; ok {
+		return def.sport
+	}
+	if strings.Contains(string(l), ".") {
+		return Soccer
+	}
+	return Basketball
+}
+
+//MSSport ...
+func (s SportSlug) MSSport() ms.Sport {
+	return ms.Sport(s)
+}
+
+//MSLeague is the ms.League events of the league are normalized to, unlisted soccer leagues become e.g. "GER1" and
+//unknown leagues keep their own name rather than be keyed as another league
+func (l LeagueSlug) MSLeague() ms.League {
+	if def, ok := l.def(); ok {
+		return def.msLeague
+	}
+	return ms.League(strings.ToUpper(string(l)))
+}
+
+//IsCollege ...
+func (l LeagueSlug) IsCollege() bool {
+	return l == MensCollege || l == WomensCollege || l == CollegeFootball
+}
+
+//PeriodFormat is how games of the league are divided, see ms.PeriodFormat
+func (l LeagueSlug) PeriodFormat() ms.PeriodFormat {
+	if _, ok := leagueDefs[l]; ok {
+		return l.MSLeague().PeriodFormat()
+	}
+	return l.Sport().MSSport().PeriodFormat()
+}
+
+//Periods is the number of regulation periods e.g. men's college basketball plays two halves, MLB nine innings
+func (l LeagueSlug) Periods() int {
+	return l.PeriodFormat().Regular
+}
+
+//SeasonYear is the ESPN season year in play at t, the NBA and NHL name seasons spanning new year for the year
+//they end (2019-20 is 2020), football and european soccer for the year they start, the rest (and unknown leagues)
+//play a calendar year
+func (l LeagueSlug) SeasonYear(t time.Time) int {
+	def, _ := l.def()
+	switch {
+	case def.rollover == 0:
+		return t.Year()
+	case def.endYear && t.Month() >= def.rollover:
+		return t.Year() + 1
+	case !def.endYear && t.Month() < def.rollover:
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+//MSSeason maps an ESPN season year and type to a ms.Season, which is named for the year the season starts
+func (l LeagueSlug) MSSeason(year int, seasonType int) ms.Season {
+	def, _ := l.def()
+	if def.endYear && year != 0 {
+		year--
+	}
+	return ms.Season{SeasonYear: year, SeasonStage: int(ms.StageFromESPN(seasonType))}
+}
+
+//path returns the url path for a resource of the league under prefix e.g. "apis/site/v2/sports/basketball/wnba/teams"
+func (l LeagueSlug) path(prefix string, resource string) string {
+	if l == "" {
+		l = NBA
+	}
+	return prefix + string(l.Sport()) + "/" + string(l) + "/" + resource
+}
+
+//SitePath returns the apis/site/v2 path for a resource of the league e.g. "teams", "summary"
+func (l LeagueSlug) SitePath(resource string) string {
+	return l.path(EspnURLPrefix, resource)
+}
+
+//StandingsPath returns the apis/v2 standings path of the league
+func (l LeagueSlug) StandingsPath() string {
+	return l.path(EspnStandingsURLPrefix, "standings")
+}
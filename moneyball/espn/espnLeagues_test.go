@@ -0,0 +1,159 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"go-moneyball/moneyball/ms"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeagueSlug(t *testing.T) {
+	assertLeague(t, MensCollege, "NCAAM")
+	assertLeague(t, WomensCollege, "womens-college-basketball")
+	assertLeague(t, WNBA, "wnba")
+	assertLeague(t, NBA, "")
+
+	for _, s := range []string{"nba ", "ncaa-m", "bogus"} {
+		_, ok := LeagueFor(s)
+		assert.False(t, ok, "%q is not the NBA", s)
+		assert.False(t, LeagueSlug(s).Known())
+	}
+	assert.Equal(t, ms.League("NCAA-M"), LeagueSlug("ncaa-m").MSLeague(), "an unknown league isn't keyed as the NBA")
+	assert.Equal(t, 2019, LeagueSlug("ncaa-m").SeasonYear(time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, LeagueSlug("ger.1").Known())
+
+	assert.Equal(t, ms.LeagueNCAAW, WomensCollege.MSLeague())
+	assert.Equal(t, 2, MensCollege.Periods())
+	assert.Equal(t, 4, WomensCollege.Periods())
+	assert.True(t, MensCollege.IsCollege())
+	assert.False(t, WNBA.IsCollege())
+
+	assert.Equal(t, "apis/site/v2/sports/basketball/wnba/teams", WNBA.SitePath("teams"))
+	assert.Equal(t, "apis/v2/sports/basketball/mens-college-basketball/standings", MensCollege.StandingsPath())
+	assert.Equal(t, "apis/site/v2/sports/basketball/nba/summary", LeagueSlug("").SitePath("summary"))
+}
+
+func TestLeagueSportPaths(t *testing.T) {
+	assert.Equal(t, Hockey, NHL.Sport())
+	assert.Equal(t, Soccer, LeagueSlug("ger.1").Sport())
+	assertLeague(t, LeagueSlug("ger.1"), "GER.1")
+	assert.Equal(t, ms.League("GER1"), LeagueSlug("ger.1").MSLeague())
+	assertLeague(t, PremierLeague, "EPL")
+	assertLeague(t, CollegeFootball, "ncaaf")
+
+	assert.Equal(t, "apis/site/v2/sports/football/nfl/scoreboard", NFL.SitePath("scoreboard"))
+	assert.Equal(t, "apis/site/v2/sports/soccer/eng.1/summary", PremierLeague.SitePath("summary"))
+	assert.Equal(t, "apis/v2/sports/hockey/nhl/standings", NHL.StandingsPath())
+	assert.Equal(t, 9, MLB.Periods())
+	assert.Equal(t, ms.PeriodHalf, LeagueSlug("ger.1").PeriodFormat().Unit)
+
+	jan := time.Date(2020, time.January, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 2020, NHL.SeasonYear(jan))
+	assert.Equal(t, 2019, NFL.SeasonYear(jan))
+	assert.Equal(t, 2019, PremierLeague.SeasonYear(jan))
+	assert.Equal(t, 2020, MLB.SeasonYear(jan))
+}
+
+func TestMarshalMSEventExtraTime(t *testing.T) {
+	e := Event{ID: "541234", Competitions: []Competition{{ID: "541234", Competitors: []Competitor{
+		{HomeAway: "home", Score: "2", Team: Team{ID: "359", Abbreviation: "ARS"},
+			Linescores: []Linescore{{Value: 1}, {Value: 0}, {Value: 1}, {Value: 0}}},
+		{HomeAway: "away", Score: "1", Team: Team{ID: "364", Abbreviation: "LIV"},
+			Linescores: []Linescore{{Value: 0}, {Value: 1}, {Value: 0}, {Value: 0}}},
+	}}}}
+	e.Status.Period = 4
+	ev, err := e.MarshalMSEvent(League{Slug: string(PremierLeague)})
+	assert.Nil(t, err)
+	assert.Equal(t, ms.LeagueEPL, ev.League)
+	assert.Equal(t, "ET2", ev.GameDetail.Period.Label)
+	assert.True(t, ev.GameDetail.Period.IsExtra)
+	ls := *ev.HomeTeam.LineScore
+	assert.Equal(t, ms.Score{Score: 1, Period: 3, Label: "ET1", IsExtra: true}, ls[2])
+}
+
+func TestMarshalMSEventOfficials(t *testing.T) {
+	s := Summary{League: NBA, GameInfo: SummaryGameInfo{Officials: []SummaryOfficial{
+		{FullName: "Scott Foster", Position: OfficialPosition{Name: "Referee"}, Order: 1}}}}
+	e := Event{ID: "401161559", Competitions: []Competition{{ID: "401161559", Competitors: []Competitor{
+		{HomeAway: "home", Score: "113", Team: Team{ID: "28", Abbreviation: "TOR"}},
+		{HomeAway: "away", Score: "104", Team: Team{ID: "2", Abbreviation: "BOS"}},
+	}}}}
+	e.Officials = s.MarshalMSOfficials()
+	ev, err := e.MarshalMSEvent(League{Slug: string(NBA)})
+	assert.Nil(t, err)
+	if assert.Len(t, ev.Officials, 1) {
+		assert.Equal(t, "scott-foster", ev.Officials[0].OfficialID)
+		assert.Equal(t, "Referee", ev.Officials[0].Position)
+	}
+}
+
+func TestMarshalMSEventRosters(t *testing.T) {
+	r := Roster{League: NBA, Season: SeasonShort{Year: 2020, Type: 2}, Team: RosterTeam{ID: "28", Abbreviation: "TOR"},
+		Athletes: []RosterAthlete{{ID: "3012", FullName: "Kyle Lowry"}}}
+	roster, err := r.MarshalMS()
+	assert.Nil(t, err)
+	e := Event{ID: "401161559", Competitions: []Competition{{ID: "401161559", Competitors: []Competitor{
+		{HomeAway: "home", Score: "113", Team: Team{ID: "28", Abbreviation: "TOR"}},
+		{HomeAway: "away", Score: "104", Team: Team{ID: "2", Abbreviation: "BOS"}},
+	}}}}
+	sb := ScoreBoard{Leagues: []League{{Slug: string(NBA)}}, Events: []Event{e}}
+	assert.Equal(t, []string{"28", "2"}, sb.TeamIDs())
+	sb.AttachRosters(roster)
+	msb, err := sb.MarshalMS()
+	assert.Nil(t, err)
+	ev := msb.Events[0]
+	if assert.NotNil(t, ev.HomeTeam.Roster) {
+		assert.Equal(t, "Kyle Lowry", ev.HomeTeam.Roster.Roster[0].FullName)
+	}
+	assert.Nil(t, ev.VisitTeam.Roster, "no roster fetched for BOS")
+}
+
+func TestMarshalMSCompetitorRank(t *testing.T) {
+	comp := Competitor{HomeAway: "home", Team: Team{ID: "2509", Abbreviation: "PUR", Name: "Boilermakers"},
+		CuratedRank: &CuratedRank{Current: 7}}
+	c, err := comp.marshalMSCompetitor(MensCollege)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, c.Rank)
+	assert.Equal(t, "PUR", c.Abbreviation)
+
+	comp.CuratedRank.Current = 99
+	c, _ = comp.marshalMSCompetitor(MensCollege)
+	assert.Equal(t, 0, c.Rank, "99 is ESPN's unranked")
+}
+
+func assertLeague(t *testing.T, expected LeagueSlug, s string) {
+	slug, ok := LeagueFor(s)
+	assert.True(t, ok, s)
+	assert.Equal(t, expected, slug, s)
+}
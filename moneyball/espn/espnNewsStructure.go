@@ -0,0 +1,147 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+//News: https://site.api.espn.com/apis/site/v2/sports/basketball/nba/news
+//
+// {"header":"NBA News","articles":[{"type":"HeadlineNews","headline":"Raptors' Lowry out with knee soreness",
+//	"description":"...","published":"2020-01-02T18:29:59Z","lastModified":"2020-01-02T18:40:41Z","byline":"...",
+//	"links":{"web":{"href":"https://www.espn.com/nba/story/_/id/28412345"}},
+//	"categories":[{"type":"league","description":"NBA","leagueId":46},{"type":"team","description":"Toronto Raptors","teamId":28},
+//	{"type":"athlete","description":"Kyle Lowry","athleteId":3012},{"type":"event","eventId":401161559},
+//	{"type":"topic","description":"Injuries"}]},...]}
+
+import (
+	"go-moneyball/moneyball/ms"
+	"log"
+	"strconv"
+	"time"
+)
+
+//News ...
+type News struct {
+	Header   string     `json:"header,omitempty"` // "NBA News"
+	Articles []Article  `json:"articles"`
+	League   LeagueSlug `json:"-"` // set by the fetcher
+}
+
+//Article ...
+type Article struct {
+	ID           int            `json:"id,omitempty"`   // 28412345
+	Type         string         `json:"type,omitempty"` // "HeadlineNews"
+	Headline     string         `json:"headline"`
+	Description  string         `json:"description,omitempty"`
+	Byline       string         `json:"byline,omitempty"`
+	Published    *time.Time     `json:"published,omitempty"`    // "2020-01-02T18:29:59Z"
+	LastModified *time.Time     `json:"lastModified,omitempty"` // "2020-01-02T18:40:41Z"
+	Links        ArticleLinks   `json:"links"`
+	Categories   []NewsCategory `json:"categories,omitempty"`
+}
+
+//ArticleLinks ...
+type ArticleLinks struct {
+	Web    *ArticleLink `json:"web,omitempty"`
+	Mobile *ArticleLink `json:"mobile,omitempty"`
+}
+
+//ArticleLink ...
+type ArticleLink struct {
+	HRef string `json:"href"`
+}
+
+//NewsCategory ... what an article is about, the id that is set depends on the type
+type NewsCategory struct {
+	Type        string `json:"type"`                  // "league", "team", "athlete", "event", "topic", "guid"
+	Description string `json:"description,omitempty"` // "Toronto Raptors"
+	LeagueID    int    `json:"leagueId,omitempty"`    // 46
+	TeamID      int    `json:"teamId,omitempty"`      // 28
+	AthleteID   int    `json:"athleteId,omitempty"`   // 3012
+	EventID     int    `json:"eventId,omitempty"`     // 401161559
+}
+
+//MarshalMS marshals the articles to ms.NewsItem, teams are resolved to their canonical id in the league's
+//team registry, players and games through the league's IdentityResolver.  A game not yet in the crosswalk keeps
+//its ESPN id, a player that matches more than one is left out and reported in Ambiguous
+func (n *News) MarshalMS() (*ms.NewsFeed, error) {
+	feed := ms.NewsFeed{League: n.League.MSLeague(), Items: []*ms.NewsItem{}}
+	registry := ms.TeamRegistryFor(feed.League)
+	resolver := ms.IdentityResolverFor(feed.League)
+	for _, a := range n.Articles {
+		item := ms.NewsItem{League: feed.League, Type: a.Type, Headline: a.Headline, Description: a.Description,
+			Byline: a.Byline, Published: a.Published, LastModified: a.LastModified}
+		if a.ID != 0 {
+			item.EntityID.ID = strconv.Itoa(a.ID)
+		}
+		if a.Links.Web != nil {
+			item.Link = a.Links.Web.HRef
+		}
+		for _, c := range a.Categories {
+			switch c.Type {
+			case "league":
+			case "team":
+				if c.TeamID == 0 {
+					continue
+				}
+				teamID := strconv.Itoa(c.TeamID)
+				if canon := registry.ByESPNID(teamID); canon != nil {
+					teamID = canon.ID
+				}
+				item.Teams = append(item.Teams, teamID)
+			case "athlete":
+				if c.AthleteID == 0 {
+					continue
+				}
+				p := ms.Player{IDESPN: strconv.Itoa(c.AthleteID), FullName: c.Description}
+				playerID, err := resolver.ResolvePlayer(ms.ProviderESPN, &p)
+				if err != nil {
+					log.Printf("article %d: athlete %s %s: %s\n", a.ID, p.IDESPN, p.FullName, err)
+					continue
+				}
+				item.Players = append(item.Players, playerID)
+			case "event":
+				if c.EventID == 0 {
+					continue
+				}
+				gameID := strconv.Itoa(c.EventID)
+				if canon := resolver.Lookup(ms.KindGame, ms.ProviderESPN, gameID); canon != "" {
+					gameID = canon
+				}
+				item.Games = append(item.Games, ms.GameID(gameID))
+			default:
+				if c.Description != "" {
+					item.Topics = append(item.Topics, c.Description)
+				}
+			}
+		}
+		feed.Items = append(feed.Items, &item)
+	}
+	return &feed, nil
+}
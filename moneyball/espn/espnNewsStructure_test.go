@@ -0,0 +1,84 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"go-moneyball/moneyball/ms"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tNews = `{"header":"NBA News","articles":[
+{"id":28412345,"type":"HeadlineNews","headline":"Raptors' Lowry out with knee soreness","published":"2020-01-02T18:29:59Z",
+ "links":{"web":{"href":"https://www.espn.com/nba/story/_/id/28412345"}},
+ "categories":[{"type":"league","description":"NBA","leagueId":46},{"type":"team","description":"Toronto Raptors","teamId":28},
+ {"type":"athlete","description":"Kyle Lowry","athleteId":3012},{"type":"event","eventId":401161559},{"type":"topic","description":"Injuries"}]},
+{"id":28400001,"type":"Story","headline":"Warriors waive guard","published":"2020-01-01T12:00:00Z","links":{},
+ "categories":[{"type":"team","description":"Golden State Warriors","teamId":9}]}]}`
+
+func TestNewsMarshalMS(t *testing.T) {
+	resolver := ms.IdentityResolverFor(ms.LeagueNBA)
+	resolver.Confirm(ms.KindPlayer, ms.ProviderESPN, "3012", "nba:200768")
+	resolver.Confirm(ms.KindGame, ms.ProviderESPN, "401161559", "2020-01-02:TOR:POR")
+	n := News{League: NBA}
+	assert.Nil(t, json.Unmarshal([]byte(tNews), &n))
+	feed, err := n.MarshalMS()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(feed.Items))
+
+	lowry := feed.Items[0]
+	assert.Equal(t, "28412345", lowry.EntityID.ID)
+	assert.Equal(t, ms.LeagueNBA, lowry.League)
+	assert.Equal(t, []string{"TOR"}, lowry.Teams)
+	assert.True(t, lowry.AboutPlayer("nba:200768"), "players are resolved through the crosswalk")
+	assert.Equal(t, []ms.GameID{"2020-01-02:TOR:POR"}, lowry.Games)
+	assert.Equal(t, []string{"Injuries"}, lowry.Topics)
+	assert.Equal(t, "https://www.espn.com/nba/story/_/id/28412345", lowry.Link)
+	assert.True(t, feed.Items[1].AboutTeam("GSW"))
+
+	since := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	fresh, latest := ms.NewsSince(feed.Items, since)
+	assert.Equal(t, 1, len(fresh), "the item published at since was already seen")
+	assert.Equal(t, *lowry.Published, latest)
+	fresh, latest = ms.NewsSince(feed.Items, latest)
+	assert.Equal(t, 0, len(fresh))
+	assert.Equal(t, *lowry.Published, latest)
+
+	n = News{League: NBA, Articles: []Article{{ID: 1, Headline: "Rookie called up", Categories: []NewsCategory{
+		{Type: "athlete", Description: "Rookie Guard", AthleteID: 999001}, {Type: "event", EventID: 999002}}}}}
+	feed, err = n.MarshalMS()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"espn:999001"}, feed.Items[0].Players, "a new player is keyed by its ESPN id")
+	assert.Equal(t, []ms.GameID{"999002"}, feed.Items[0].Games, "a game not yet in the crosswalk keeps its ESPN id")
+}
@@ -0,0 +1,141 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+//Roster: https://site.api.espn.com/apis/site/v2/sports/basketball/nba/teams/:team/roster
+//
+// {"season":{"year":2020,"type":2},"team":{"id":"28","abbreviation":"TOR",...},
+//	"athletes":[{"id":"3012","fullName":"Kyle Lowry","displayName":"Kyle Lowry","shortName":"K. Lowry","weight":196.0,
+//	"height":72.0,"dateOfBirth":"1986-03-25T08:00Z","headshot":{"href":"https://a.espncdn.com/i/headshots/nba/players/full/3012.png",
+//	"alt":"Kyle Lowry"},"jersey":"7","position":{"name":"Point Guard","abbreviation":"PG"},"experience":{"years":13},
+//	"active":true},...]}
+
+import (
+	"go-moneyball/moneyball/ms"
+	"time"
+)
+
+//Roster ...
+type Roster struct {
+	Season   SeasonShort     `json:"season"`
+	Team     RosterTeam      `json:"team"`
+	Athletes []RosterAthlete `json:"athletes"`
+	League   LeagueSlug      `json:"-"` // set by the fetcher
+}
+
+//RosterTeam ...
+type RosterTeam struct {
+	ID           string `json:"id"`           // "28"
+	Abbreviation string `json:"abbreviation"` // "TOR"
+	DisplayName  string `json:"displayName"`  // "Toronto Raptors"
+}
+
+//RosterHeadshot ...
+type RosterHeadshot struct {
+	HRef string `json:"href"` // "https://a.espncdn.com/i/headshots/nba/players/full/3012.png"
+	Alt  string `json:"alt"`  // "Kyle Lowry"
+}
+
+//RosterExperience ...
+type RosterExperience struct {
+	Years int `json:"years"` // 13
+}
+
+//RosterAthlete ... an athlete on a team roster
+type RosterAthlete struct {
+	ID          string           `json:"id" binding:"required"` // "3012"
+	FullName    string           `json:"fullName,omitempty"`    // "Kyle Lowry"
+	DisplayName string           `json:"displayName,omitempty"` // "Kyle Lowry"
+	ShortName   string           `json:"shortName,omitempty"`   // "K. Lowry"
+	Weight      float64          `json:"weight,omitempty"`      // 196.0 pounds
+	Height      float64          `json:"height,omitempty"`      // 72.0 inches
+	DateOfBirth *espnTime        `json:"dateOfBirth,omitempty"` // "1986-03-25T08:00Z"
+	Links       []Link           `json:"links,omitempty"`
+	Headshot    *RosterHeadshot  `json:"headshot,omitempty"`
+	Jersey      string           `json:"jersey,omitempty"` // "7"
+	Position    Position         `json:"position"`
+	Experience  RosterExperience `json:"experience"`
+	Active      bool             `json:"active"`
+}
+
+//MarshalMS marshals the roster to a ms.TeamSeasonRoster of ms.Player
+func (r *Roster) MarshalMS() (*ms.TeamSeasonRoster, error) {
+	roster := ms.TeamSeasonRoster{
+		Season:           r.League.MSSeason(r.Season.Year, r.Season.Type),
+		TeamAbbreviation: r.Team.Abbreviation,
+		Roster:           []*ms.Player{},
+	}
+	now := time.Now().UTC()
+	roster.AsOf = &now
+
+	var team *ms.Team
+	if canon := ms.TeamRegistryFor(r.League.MSLeague()).ByESPNID(r.Team.ID); canon != nil {
+		tm := *canon
+		team = &tm
+		roster.TeamAbbreviation = canon.Abbreviation
+	}
+	for _, a := range r.Athletes {
+		p := a.marshalMSPlayer()
+		p.League = r.League.MSLeague()
+		p.Team = team
+		roster.Roster = append(roster.Roster, p)
+	}
+	return &roster, nil
+}
+
+func (a *RosterAthlete) marshalMSPlayer() *ms.Player {
+	p := ms.Player{
+		IDESPN:      a.ID,
+		FullName:    a.FullName,
+		DisplayName: a.DisplayName,
+		ShortName:   a.ShortName,
+		Jersey:      a.Jersey,
+		Position:    &ms.Position{Name: a.Position.Name, Abbreviation: a.Position.Abbreviation},
+		Active:      a.Active,
+		Height:      int(a.Height),
+		Weight:      int(a.Weight),
+		Experience:  a.Experience.Years,
+		Links:       []ms.Link{},
+	}
+	p.EntityID.ID = a.ID
+	if a.DateOfBirth != nil {
+		bd := time.Time(*a.DateOfBirth)
+		p.BirthDate = &bd
+	}
+	if a.Headshot != nil {
+		p.Headshot = &ms.Link{HRef: a.Headshot.HRef, Alt: a.Headshot.Alt, Rel: []string{"headshot"}}
+	}
+	for _, link := range a.Links {
+		l, _ := marshalMSLink(&link)
+		p.Links = append(p.Links, *l)
+	}
+	return &p
+}
@@ -0,0 +1,80 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// golden file tests for the ESPN -> ms normalization, the ms.ScoreBoard marshalled from each example scoreboard
+// is compared to examples/json/golden/<example>.ms.json.  After an intended change to the mapping regenerate the
+// golden files with
+//	go test ./moneyball/espn -run TestMarshalMSGolden -update
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+var goldenExamples = []string{"espnevent.json", "espnbasketballevent.json", "espn122919-1941.json"}
+
+func TestMarshalMSGolden(t *testing.T) {
+	for _, example := range goldenExamples {
+		t.Run(example, func(t *testing.T) {
+			b, err := ioutil.ReadFile(filepath.Join("../../examples/json", example))
+			if !assert.Nil(t, err) {
+				return
+			}
+			sb := ScoreBoard{}
+			if !assert.Nil(t, json.Unmarshal(b, &sb)) {
+				return
+			}
+			msb, err := sb.MarshalMS()
+			assert.Nil(t, err)
+			assert.Equal(t, len(sb.Events), len(msb.Events))
+			got, err := json.MarshalIndent(msb, "", "  ")
+			assert.Nil(t, err)
+
+			golden := filepath.Join("../../examples/json/golden", strings.TrimSuffix(example, ".json")+".ms.json")
+			if *update {
+				assert.Nil(t, ioutil.WriteFile(golden, append(got, '\n'), 0644))
+			}
+			want, err := ioutil.ReadFile(golden)
+			if assert.Nil(t, err, "missing golden file, run with -update") {
+				assert.JSONEq(t, string(want), string(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// scoreboard query parameters, all optional:
+//
+//	dates=20191229 a single (US eastern) day, or dates=20191201-20191231 a range
+//	groups=50 a conference/group id, mostly for college leagues
+//	limit=300 the maximum number of events, ESPN returns a small page by default for ranges
+//
+//https://site.api.espn.com/apis/site/v2/sports/basketball/nba/scoreboard?dates=20191201-20191231&limit=300
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	//espnDateFormat is the dates= parameter format
+	espnDateFormat = "20060102"
+)
+
+//ScoreBoardQuery ... selects the scoreboard to fetch
+type ScoreBoardQuery struct {
+	League LeagueSlug // e.g. MensCollege, defaults to NBA
+	Dates  string     // "20191229" or "20191201-20191231", empty is today
+	Groups string     // e.g. "50"
+	Limit  int        // 0 leaves ESPN's default
+}
+
+//DateParam formats a day for the dates= parameter
+func DateParam(day time.Time) string {
+	return day.Format(espnDateFormat)
+}
+
+//DateRangeParam formats an inclusive range of days for the dates= parameter
+func DateRangeParam(from time.Time, to time.Time) string {
+	return from.Format(espnDateFormat) + "-" + to.Format(espnDateFormat)
+}
+
+//LeagueSlug returns the league of the query, or NBA
+func (q *ScoreBoardQuery) LeagueSlug() LeagueSlug {
+	if q == nil || q.League == "" {
+		return NBA
+	}
+	return q.League
+}
+
+//Path returns the scoreboard path and query string relative to EspnBaseURL
+func (q *ScoreBoardQuery) Path() string {
+	path := q.LeagueSlug().SitePath("scoreboard")
+	if q == nil {
+		return path
+	}
+	v := url.Values{}
+	if q.Dates != "" {
+		v.Set("dates", q.Dates)
+	}
+	if q.Groups != "" {
+		v.Set("groups", q.Groups)
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if len(v) > 0 {
+		path = path + "?" + v.Encode()
+	}
+	return path
+}
+
+//calendarDay turns a calendar timestamp into its day, ESPN calendar days start at midnight US pacific
+//("2019-09-30T07:00Z", "2019-12-25T08:00Z") and end a minute before ("2020-07-01T06:59Z")
+func calendarDay(t time.Time) time.Time {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		location = time.FixedZone("PST", -8*60*60)
+	}
+	p := t.In(location)
+	return time.Date(p.Year(), p.Month(), p.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+//GameDays enumerates the days of the league calendar that have games.  ESPN sends either a whitelist (the days
+//with games) or a blacklist (the days without games between CalendarStartDate and CalendarEndDate)
+func (l *League) GameDays() []time.Time {
+	days := []time.Time{}
+	if l.CalendarIsWhiteList {
+		for _, c := range l.Calendar {
+			days = append(days, calendarDay(time.Time(c)))
+		}
+	} else {
+		listed := map[time.Time]bool{}
+		for _, c := range l.Calendar {
+			listed[calendarDay(time.Time(c))] = true
+		}
+		end := calendarDay(time.Time(l.CalendarEndDate))
+		for d := calendarDay(time.Time(l.CalendarStartDate)); !d.After(end); d = d.AddDate(0, 0, 1) {
+			if !listed[d] {
+				days = append(days, d)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	unique := []time.Time{}
+	for i, d := range days {
+		if i == 0 || !d.Equal(days[i-1]) {
+			unique = append(unique, d)
+		}
+	}
+	return unique
+}
+
+//Merge appends the events of other to the scoreboard, keeping the first copy of an event seen twice
+func (s *ScoreBoard) Merge(other *ScoreBoard) {
+	if other == nil {
+		return
+	}
+	seen := map[string]bool{}
+	for _, e := range s.Events {
+		seen[e.ID] = true
+	}
+	if len(s.Leagues) == 0 {
+		s.Leagues = other.Leagues
+		s.Season = other.Season
+	}
+	for _, e := range other.Events {
+		if !seen[e.ID] {
+			s.Events = append(s.Events, e)
+			seen[e.ID] = true
+		}
+	}
+}
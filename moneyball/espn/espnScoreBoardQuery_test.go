@@ -0,0 +1,79 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sbFilename = "../../examples/json/espn122919-1941.json"
+
+func TestScoreBoardQueryPath(t *testing.T) {
+	var q *ScoreBoardQuery
+	assert.Equal(t, "apis/site/v2/sports/basketball/nba/scoreboard", q.Path())
+
+	from := time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2019, time.December, 31, 0, 0, 0, 0, time.UTC)
+	q = &ScoreBoardQuery{League: "mens-college-basketball", Dates: DateRangeParam(from, to), Groups: "50", Limit: 300}
+	assert.Equal(t, "apis/site/v2/sports/basketball/mens-college-basketball/scoreboard?dates=20191201-20191231&groups=50&limit=300",
+		q.Path())
+}
+
+func TestLeagueGameDays(t *testing.T) {
+	b, err := ioutil.ReadFile(sbFilename)
+	assert.Nil(t, err)
+	sb := ScoreBoard{}
+	assert.Nil(t, json.Unmarshal(b, &sb))
+
+	days := sb.Leagues[0].GameDays()
+	assert.Equal(t, 186, len(days))
+	assert.Equal(t, "20190930", DateParam(days[0]))
+	assert.Equal(t, "20200415", DateParam(days[len(days)-1]))
+
+	// a blacklist calendar lists the days without games
+	l := League{CalendarIsWhiteList: false,
+		CalendarStartDate: espnTime(time.Date(2019, time.December, 23, 8, 0, 0, 0, time.UTC)),
+		CalendarEndDate:   espnTime(time.Date(2019, time.December, 27, 7, 59, 0, 0, time.UTC)),
+		Calendar:          []espnTime{espnTime(time.Date(2019, time.December, 24, 8, 0, 0, 0, time.UTC))}}
+	days = l.GameDays()
+	assert.Equal(t, []string{"20191223", "20191225", "20191226"}, []string{DateParam(days[0]), DateParam(days[1]), DateParam(days[2])})
+	assert.Equal(t, 3, len(days))
+
+	merged := ScoreBoard{}
+	merged.Merge(&sb)
+	merged.Merge(&sb)
+	assert.Equal(t, len(sb.Events), len(merged.Events), "events seen twice are merged once")
+}
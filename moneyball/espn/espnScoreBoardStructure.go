@@ -67,6 +67,7 @@ import (
 	"fmt"
 	"go-moneyball/moneyball/ms"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -74,8 +75,8 @@ import (
 const (
 	//EspnBaseURL is the URL basis for calls to ESPN API's
 	EspnBaseURL = "https://site.api.espn.com/"
-	//EspnURLPrefix is the URL filepath prefix for calls to v2 of ESPN API's
-	EspnURLPrefix = "apis/site/v2/sports/basketball/"
+	//EspnURLPrefix is the URL filepath prefix for calls to v2 of ESPN API's, the sport and league follow
+	EspnURLPrefix = "apis/site/v2/sports/"
 )
 
 //ScoreBoard ...
@@ -131,17 +132,19 @@ type Date struct {
 
 //Event ...
 type Event struct {
-	Extracted    *time.Time    `json:"extract_time,omitempty"`
-	ExtractedSrc string        `json:"extract_src,omitempty"`
-	ID           string        `json:"id" binding:"required"`
-	UID          string        `json:"uid" binding:"required"`
-	Date         espnTime      `json:"date"`
-	Name         string        `json:"name"`
-	ShortName    string        `json:"shortName"`
-	Season       SeasonShort   `json:"season"`
-	Competitions []Competition `json:"competitions"`
-	Links        []Link        `json:"links"`
-	Status       GameStatus    `json:"status"`
+	Extracted    *time.Time             `json:"extract_time,omitempty"`
+	ExtractedSrc string                 `json:"extract_src,omitempty"`
+	ID           string                 `json:"id" binding:"required"`
+	UID          string                 `json:"uid" binding:"required"`
+	Date         espnTime               `json:"date"`
+	Name         string                 `json:"name"`
+	ShortName    string                 `json:"shortName"`
+	Season       SeasonShort            `json:"season"`
+	Competitions []Competition          `json:"competitions"`
+	Links        []Link                 `json:"links"`
+	Status       GameStatus             `json:"status"`
+	Officials    []*ms.GameOfficial     `json:"-"` // the crew, set from the game summary see Summary.MarshalMSOfficials
+	Rosters      []*ms.TeamSeasonRoster `json:"-"` // the competitors' rosters, see ScoreBoard.AttachRosters
 }
 
 //GameStatus ...
@@ -184,11 +187,11 @@ type Competition struct {
 	ID                    string          `json:"id" binding:"required"`
 	UID                   string          `json:"uid" binding:"required"`
 	Date                  espnTime        `json:"date"`
-	Attendance            int             `json:"Addendance"`
+	Attendance            int             `json:"attendance"`
 	Type                  CompetitionType `json:"type"`
 	TimeValid             bool            `json:"timeValid"`
 	NeutralSite           bool            `json:"neutralSite"`
-	ConferenceCompetition bool            `json:"conferenceCompeition"`
+	ConferenceCompetition bool            `json:"conferenceCompetition"`
 	Recent                bool            `json:"recent"`
 	Venue                 Venue           `json:"venue"`
 	Competitors           []Competitor    `json:"competitors"`
@@ -239,10 +242,21 @@ type Broadcast struct {
 }
 
 //Odd ...
+//"odds":[{"provider":{"id":"38","name":"Caesars","priority":1},"details":"BKN -5.0","overUnder":216.5,"spread":-5.0,
+//	"homeTeamOdds":{"favorite":true,"moneyLine":-210},"awayTeamOdds":{"favorite":false,"moneyLine":170}}]
 type Odd struct {
-	Provider  OddProvider `json:"provider"`
-	Details   string      `json:"details,omitempty"`
-	OverUnder float32     `json:"overUnder"`
+	Provider     OddProvider `json:"provider"`
+	Details      string      `json:"details,omitempty"`
+	OverUnder    float32     `json:"overUnder"`
+	Spread       float32     `json:"spread,omitempty"` // home team's spread, not on older feeds
+	HomeTeamOdds *TeamOdds   `json:"homeTeamOdds,omitempty"`
+	AwayTeamOdds *TeamOdds   `json:"awayTeamOdds,omitempty"`
+}
+
+//TeamOdds ...
+type TeamOdds struct {
+	Favorite  bool `json:"favorite"`
+	MoneyLine int  `json:"moneyLine,omitempty"` // -210
 }
 
 //OddProvider ...
@@ -259,18 +273,24 @@ type CompetitionType struct {
 
 //Competitor ...
 type Competitor struct {
-	ID         string       `json:"id" binding:"required"`
-	UID        string       `json:"uid" binding:"required"`
-	Type       string       `json:"type"` // examples "team"
-	Order      int          `json:"order"`
-	HomeAway   string       `json:"homeAway"` // example "home"
-	Winner     bool         `json:"winner"`   //?
-	Team       Team         `json:"team"`
-	Score      string       `json:"score"`
-	Linescores []Linescore  `json:"linescores"`
-	Statistics []Statistic  `json:"statistics"`
-	Records    []Record     `json:"records"`
-	Leaders    []StatLeader `json:"leaders"`
+	ID          string       `json:"id" binding:"required"`
+	UID         string       `json:"uid" binding:"required"`
+	Type        string       `json:"type"` // examples "team"
+	Order       int          `json:"order"`
+	HomeAway    string       `json:"homeAway"` // example "home"
+	Winner      bool         `json:"winner"`   //?
+	Team        Team         `json:"team"`
+	Score       string       `json:"score"`
+	Linescores  []Linescore  `json:"linescores"`
+	Statistics  []Statistic  `json:"statistics"`
+	Records     []Record     `json:"records"`
+	Leaders     []StatLeader `json:"leaders"`
+	CuratedRank *CuratedRank `json:"curatedRank,omitempty"` // college poll ranking
+}
+
+//CuratedRank ... "curatedRank":{"current":7}, ESPN uses 99 for unranked teams
+type CuratedRank struct {
+	Current int `json:"current"`
 }
 
 //Linescore ...
@@ -335,8 +355,10 @@ type TeamStatistic struct {
 
 // Item ...
 type Item struct {
-	Summary string           `json:"summary"` //"summary":"7-27",
-	Stats   []*TeamStatistic `json:"stats"`   //"stats":[
+	Description string           `json:"description,omitempty"` //"description":"Overall Record",
+	Type        string           `json:"type,omitempty"`        //"type":"total",
+	Summary     string           `json:"summary"`               //"summary":"7-27",
+	Stats       []*TeamStatistic `json:"stats"`                 //"stats":[
 }
 
 //Link ...
@@ -425,7 +447,79 @@ type Sport struct {
 
 //TeamSport ... array of teams for json depacking
 type TeamSport struct {
-	Sport []Sport `json:"sports"`
+	Sport  []Sport    `json:"sports"`
+	League LeagueSlug `json:"-"` // set by the fetcher, the feed doesn't say which registry its ids belong to
+}
+
+//RegisterTeams refreshes the registry from the /teams feed and returns the canonical teams
+func (ts *TeamSport) RegisterTeams(r *ms.TeamRegistry) ([]*ms.Team, error) {
+	canon := []*ms.Team{}
+	for _, sport := range ts.Sport {
+		for _, league := range sport.Leagues {
+			for _, at := range league.Teams {
+				team, err := marshalMSTeam(&at.Team)
+				if err != nil {
+					return canon, err
+				}
+				// register under ESPN's own abbreviation so it is kept as an alias of the canonical tricode
+				team.Abbreviation = at.Team.Abbreviation
+				canon = append(canon, r.Register(team))
+			}
+		}
+	}
+	return canon, nil
+}
+
+//MarshalMS marshalls every team of the /teams feed to ms.Team, each record item is kept as its own
+//TeamSeasonRecords for the season of the feed, ids are ESPN's, use RegisterTeams for the canonical teams
+func (ts *TeamSport) MarshalMS() ([]ms.Team, error) {
+	teams := []ms.Team{}
+	for _, sport := range ts.Sport {
+		for _, league := range sport.Leagues {
+			season := league.msSeason(ts.League, time.Now())
+			for _, at := range league.Teams {
+				team, err := marshalMSTeam(&at.Team)
+				if err != nil {
+					return teams, err
+				}
+				for _, r := range team.Records {
+					s := season
+					r.Season = &s
+				}
+				teams = append(teams, *team)
+			}
+		}
+	}
+	return teams, nil
+}
+
+//msSeason is the season of the league, feeds without one (e.g. /teams) fall back to the regular season at now
+func (l *League) msSeason(slug LeagueSlug, now time.Time) ms.Season {
+	if slug == "" {
+		slug, _ = LeagueFor(l.Slug)
+	}
+	if l.Season != nil && l.Season.Year != 0 {
+		return slug.MSSeason(l.Season.Year, l.Season.Type.Type)
+	}
+	return slug.MSSeason(slug.SeasonYear(now), 2)
+}
+
+//MSSeasonCalendar returns the calendar of the feed's season: the dates of its current stage and, for leagues
+//with a daily calendar, the game days
+func (l *League) MSSeasonCalendar(slug LeagueSlug) *ms.SeasonCalendar {
+	if slug == "" {
+		slug, _ = LeagueFor(l.Slug)
+	}
+	season := l.msSeason(slug, time.Now())
+	c := ms.SeasonCalendar{League: slug.MSLeague(), Season: season.SeasonYear}
+	if l.Season != nil && !time.Time(l.Season.StartDate).IsZero() {
+		c.Stages = append(c.Stages, ms.StageDates{Stage: season.Stage(),
+			Start: ms.StandingDate(time.Time(l.Season.StartDate)), End: ms.StandingDate(time.Time(l.Season.EndDate))})
+	}
+	if l.CalendarType == "day" {
+		c.AddGameDays(l.GameDays()...)
+	}
+	return &c
 }
 
 //MarshalMS marshalls espn.Scoreboard structures to ms.Scoreboard structures, can return partial results
@@ -433,63 +527,103 @@ type TeamSport struct {
 func (s *ScoreBoard) MarshalMS() (*ms.ScoreBoard, error) {
 	sb := ms.ScoreBoard{}
 	bs := []ms.Event{}
+	league := League{}
+	if len(s.Leagues) > 0 {
+		league = s.Leagues[0]
+	}
 	for _, event := range s.Events {
-		evented, err := (&event).MarshalMSEvent(s.Leagues[0])
-		bs = append(bs, *evented)
+		evented, err := (&event).MarshalMSEvent(league)
 		if err != nil {
 			sb.Events = bs
-			return &sb, err
+			return &sb, fmt.Errorf("event %s: %s", event.ID, err)
 		}
+		bs = append(bs, *evented)
 	}
 	sb.Events = bs
 	return &sb, nil
 }
 
+//TeamIDs lists the ESPN ids of the teams playing on the scoreboard once, e.g. to fetch their rosters
+func (s *ScoreBoard) TeamIDs() []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, e := range s.Events {
+		for _, c := range e.Competitions {
+			for _, comp := range c.Competitors {
+				if comp.Team.ID != "" && !seen[comp.Team.ID] {
+					seen[comp.Team.ID] = true
+					ids = append(ids, comp.Team.ID)
+				}
+			}
+		}
+	}
+	return ids
+}
+
+//Extract stamps the events with the time and source they were fetched from, events already stamped (e.g. by the
+//extract tool) keep theirs
+func (s *ScoreBoard) Extract(at time.Time, src string) {
+	for i := range s.Events {
+		if s.Events[i].Extracted == nil {
+			t := at
+			s.Events[i].Extracted = &t
+			s.Events[i].ExtractedSrc = src
+		}
+	}
+}
+
+//AttachRosters hands the team rosters to every event, MarshalMS gives each competitor the roster of its team as
+//the game-day roster
+func (s *ScoreBoard) AttachRosters(rosters ...*ms.TeamSeasonRoster) {
+	for i := range s.Events {
+		s.Events[i].Rosters = append(s.Events[i].Rosters, rosters...)
+	}
+}
+
 //MarshalMSEvent marshals espn.Event to ms.Event
 func (e *Event) MarshalMSEvent(l League) (*ms.Event, error) {
 	bs := ms.Event{}
-	/* Event
-	EntityID
-	GameID     GameID      `json:"gameId"`
-	League     League      `json:"league"`
-	Season     Season      `json:"season"`
-	HomeTeam   *Competitor `json:"homeTeam"`
-	VisitTeam  *Competitor `json:"visitTeam"`
-	Venue      *Venue      `json:"location,omitempty"`
-	Status     *GameStatus  `json:"status,omitempty"`
-	Links      *[]Link     `json:"link,omitempty"`
-	GameDetail *GameDetail `json:"gameDetail,omitempty"`
-	*/
 	eID := ms.EntityID{}
 	eID.Extracted = e.Extracted
 	eID.ExtractedSrc = e.ExtractedSrc
 	bs.EntityID = eID
 	bs.GameID = ms.GameID(e.ID)
-	bs.League = ms.League(l.Abbreviation)
-	bs.Season = ms.Season{SeasonYear: e.Season.Year, SeasonStage: e.Season.Type}
+	bs.Provider = ms.ProviderESPN
+	league, known := LeagueFor(l.Slug)
+	if l.Slug == "" {
+		league, known = LeagueFor(l.Abbreviation)
+	}
+	if !known {
+		log.Printf("event %s: unknown league %s is keyed under its own name\n", e.ID, league)
+	}
+	bs.League = league.MSLeague()
+	bs.Season = league.MSSeason(e.Season.Year, e.Season.Type)
 
-	if len(e.Competitions) > 1 {
+	if len(e.Competitions) != 1 {
 		// set error
 		return nil, fmt.Errorf("error: compeitions should be 1, found %d", len(e.Competitions))
 	}
 
+	var err error
 	for _, ref := range e.Competitions[0].Competitors {
 		switch ref.HomeAway {
 		case "home":
-			bs.HomeTeam, _ = ref.marshalMSCompetitor()
+			bs.HomeTeam, err = ref.marshalMSCompetitor(league)
 		case "away":
-			bs.VisitTeam, _ = ref.marshalMSCompetitor()
+			bs.VisitTeam, err = ref.marshalMSCompetitor(league)
 		default:
 			//throw error...
 			return nil, fmt.Errorf("error: compeition should be home or away... found %s", ref.HomeAway)
 		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if bs.HomeTeam == nil || bs.VisitTeam == nil {
+		return nil, fmt.Errorf("error: competition %s should have a home and an away competitor", e.Competitions[0].ID)
 	}
 
-	venue := e.Competitions[0].Venue
-	bs.Venue = &ms.Venue{EntityID: ms.EntityID{ID: "", Extracted: nil, ExtractedSrc: ""},
-		LocalID: venue.ID, FullName: venue.FullName,
-		Address:  marshalMSAddress(venue.Address),
-		Capacity: venue.Capacity, IsIndoor: venue.IsIndoor}
+	bs.Venue = marshalMSVenue(e.Competitions[0].Venue)
 	bs.Status, _ = marshalMSGameStatus(e.Status)
 
 	links := []ms.Link{}
@@ -499,78 +633,148 @@ func (e *Event) MarshalMSEvent(l League) (*ms.Event, error) {
 	}
 	bs.Links = &links
 
-	//TODO: GameDetail
-	/*
-		//GameDetail .. extra detail about the game including things like startTime...
-		type GameDetail struct {
-			StartTime           *time.Time  `json:"startTimeUTC,omitempty"`     //"startTimeUTC":"2019-10-01T00:00:00.000Z",
-			StartDateEastern    string      `json:"startDateEastern,omitempty"` //"startDateEastern":"20190930",
-			StartTimeEastern    string      `json:"startTimeEastern,omitempty"`
-			Period              *GamePeriod `json:"period,omitempty,omitempty"` // "period": {}
-			Attendance          string      `json:"attendance,omitempty"`       //"attendance":"18624",
-			GameDurationMinutes int         `json:"gameDuration,omitempty"`
-		} */
 	gd := ms.GameDetail{}
-	refTime := time.Time(e.Date)
-	log.Printf("timeRef %s\n", refTime)
-	gd.StartTime = &refTime
-	location, err := time.LoadLocation("America/New_York")
-	if err != nil {
-		// set error
+	if err := gd.SetStartTime(time.Time(e.Date), ms.VenueTimeZone(bs.Venue)); err != nil {
 		log.Printf("error: timezone conversion %#v\n", err)
 	}
-	est := refTime.In(location)
-	log.Printf("time: UTC %s, EST %s\n", refTime, est)
-
-	gd.StartDateEastern = est.Format("2006-01-02")
-	gd.StartTimeEastern = est.Format("15:04:05")
-	/*
-		type GamePeriod struct {
-		Current       int  `json:"current"`       //"current":4,
-		Type          int  `json:"type"`          //"type":0,
-		MaxRegular    int  `json:"maxRegular"`    //"maxRegular":4
-		IsHalftime    bool `json:"isHalftime"`    //`"isHalftime":false,
-		IsEndOfPeriod bool `json:"isEndOfPeriod"` //"isEndOfPeriod":false
-		}*/
-	//gd.Period = &ms.GamePeriod{0,0,4,false,false}
+	gd.Period = league.PeriodFormat().GamePeriod(e.Status.Period)
+	gd.Period.IsHalftime = e.Status.StatusType.Name == "STATUS_HALFTIME"
+	gd.Period.IsEndOfPeriod = e.Status.StatusType.Name == "STATUS_END_PERIOD"
 	gd.Attendance = e.Competitions[0].Attendance
-	//gd.GameDurationMinutes =
+	gd.NeutralSite = e.Competitions[0].NeutralSite
+	gd.ConferenceGame = e.Competitions[0].ConferenceCompetition
 	bs.GameDetail = &gd
 
-	/* Event
-	Extracted    *time.Time    `json:"extract_time,omitempty"`
-	ExtractedSrc string        `json:"extract_src,omitempty"`
-	ID           string        `json:"id" binding:"required"`
-	UID          string        `json:"uid" binding:"required"`
-	Date         espnTime      `json:"date"`
-	Name         string        `json:"name"`
-	ShortName    string        `json:"shortName"`
-	Season       SeasonShort   `json:"season"`
-	Competitions []Competition `json:"competitions"`
-	Links        []Link        `json:"links"`
-	Status       GameStatus    `json:"status"`
-	*/
+	// the lines are captured when the feed was extracted, see ScoreBoard.Extract
+	var captured time.Time
+	if e.Extracted != nil {
+		captured = *e.Extracted
+	}
+	bs.Lines = e.Competitions[0].marshalMSLines(bs.GameID, captured)
+	bs.Broadcasts = e.Competitions[0].marshalMSBroadcasts(bs.GameID)
+
+	if err := bs.AttachOfficials(e.Officials...); err != nil {
+		log.Printf("officials of game %s not registered: %s\n", e.ID, err)
+	}
+	bs.AttachRosters(e.Rosters...)
+	if _, err := ms.IdentityResolverFor(bs.League).ResolveGame(bs.Provider, &bs); err != nil {
+		log.Printf("game %s not in the crosswalk: %s\n", e.ID, err)
+	}
 	ms.MasterIdentity(&bs)
 	return &bs, nil
 }
 
+//marshalMSLines normalizes the odds of each provider, older feeds only give the details e.g. "BKN -5.0" from
+//which the home spread is derived.  Lines of a feed without an extract time have no CapturedAt
+func (c *Competition) marshalMSLines(gameID ms.GameID, captured time.Time) []*ms.BettingLine {
+	home := ""
+	for _, comp := range c.Competitors {
+		if comp.HomeAway == "home" {
+			home = comp.Team.Abbreviation
+		}
+	}
+	lines := []*ms.BettingLine{}
+	for _, odd := range c.Odds {
+		line := ms.BettingLine{GameID: gameID, Provider: odd.Provider.Name, ProviderPriority: odd.Provider.Priority,
+			Details: odd.Details, Total: float64(odd.OverUnder)}
+		if !captured.IsZero() {
+			at := captured
+			line.CapturedAt = &at
+		}
+		if fields := strings.Fields(odd.Details); len(fields) == 2 {
+			if points, err := strconv.ParseFloat(fields[1], 64); err == nil {
+				line.FavoriteAbbreviation = fields[0]
+				// the favorite gives the points, details always show the favorite's (negative) spread
+				line.HomeSpread = points
+				if !strings.EqualFold(fields[0], home) {
+					line.HomeSpread = -points
+				}
+			}
+		}
+		if odd.Spread != 0 {
+			line.HomeSpread = float64(odd.Spread)
+		}
+		if odd.HomeTeamOdds != nil {
+			line.HomeMoneyLine = odd.HomeTeamOdds.MoneyLine
+		}
+		if odd.AwayTeamOdds != nil {
+			line.VisitMoneyLine = odd.AwayTeamOdds.MoneyLine
+		}
+		lines = append(lines, &line)
+	}
+	return lines
+}
+
+//marshalMSBroadcasts prefers the geoBroadcasts, which say the media type and region, to the broadcasts
+func (c *Competition) marshalMSBroadcasts(gameID ms.GameID) []*ms.Broadcast {
+	broadcasts := []*ms.Broadcast{}
+	for _, gb := range c.GeoBroadcasts {
+		market := strings.ToLower(gb.Market.Type)
+		broadcasts = append(broadcasts, &ms.Broadcast{GameID: gameID, Network: gb.Media.ShortName, Market: market,
+			Type: gb.Type.ShortName, Region: gb.Region, Language: gb.Language, IsNational: market == "national"})
+	}
+	if len(broadcasts) > 0 {
+		return broadcasts
+	}
+	for _, b := range c.Broadcasts {
+		market := strings.ToLower(b.Market)
+		for _, name := range b.Names {
+			broadcasts = append(broadcasts, &ms.Broadcast{GameID: gameID, Network: name, Market: market, IsNational: market == "national"})
+		}
+	}
+	return broadcasts
+}
+
 func marshalMSAddress(a Address) *ms.Address {
 	addr := ms.Address{}
 	addr.City = a.City
 	addr.State = a.State
-	return nil
+	return &addr
 }
 
-func (comp *Competitor) marshalMSCompetitor() (*ms.Competitor, error) {
+func (comp *Competitor) marshalMSCompetitor(league LeagueSlug) (*ms.Competitor, error) {
 	c := ms.Competitor{}
 	t := (*comp).Team
 	c.Name = t.Name
 	c.Abbreviation = t.Abbreviation
-	linescores := []ms.Score{}
-	for _, lsc := range t.Linescores {
-		linescores = append(linescores, ms.Score{Score: lsc.Value})
+	teamID := t.ID
+	// ESPN abbreviations differ from NBA tricodes ("GS" vs "GSW"), the registry gives the canonical team
+	if team := ms.TeamRegistryFor(league.MSLeague()).ByESPNID(t.ID); team != nil {
+		tm := *team
+		c.Team = &tm
+		c.Abbreviation = team.Abbreviation
+		teamID = team.ID
+	}
+	if comp.Score != "" {
+		score, err := strconv.Atoi(comp.Score)
+		if err != nil {
+			return nil, fmt.Errorf("competitor %s: invalid score %s", comp.ID, comp.Score)
+		}
+		c.Score = score
+	}
+	// period scores are on the competitor, older feeds put them on the team
+	lscs := comp.Linescores
+	if len(lscs) == 0 {
+		lscs = t.Linescores
 	}
+	values := []float32{}
+	for _, lsc := range lscs {
+		values = append(values, lsc.Value)
+	}
+	linescores := league.PeriodFormat().LineScore(values)
 	c.LineScore = &linescores
+	c.Record = marshalMSRecord(comp.Records)
+	for _, sl := range comp.Leaders {
+		for _, l := range sl.Leaders {
+			c.Leaders = append(c.Leaders, &ms.GameLeader{TeamID: teamID, Category: sl.Name,
+				PlayerID: l.Athlete.ID, Name: l.Athlete.DisplayName, Value: l.DisplayValue})
+		}
+	}
+	stats := []*ms.Stat{}
+	for _, stat := range comp.Statistics {
+		stats = append(stats, &ms.Stat{Key: stat.Abbreviation, LongKey: stat.Name, Value: statValue(stat.DisplayValue)})
+	}
+	c.Statistics = validateStats("competitor "+t.ID, stats)
 	c.Location = (*comp).Team.Location
 	c.Color = (*comp).Team.Color
 	c.AlternateColor = (*comp).Team.AlternateColor
@@ -582,9 +786,29 @@ func (comp *Competitor) marshalMSCompetitor() (*ms.Competitor, error) {
 		links = append(links, *l)
 	}
 	c.Links = &links
+	if comp.CuratedRank != nil && comp.CuratedRank.Current < 99 {
+		c.Rank = comp.CuratedRank.Current
+	}
 	return &c, nil
 }
 
+//marshalMSRecord takes the win/loss from the "total" record ("11-20"), every record is kept as an item
+func marshalMSRecord(records []Record) ms.Record {
+	rec := ms.Record{Items: []ms.Item{}}
+	for _, r := range records {
+		item := ms.Item{Type: r.Type, Summary: r.Summary, Stats: []ms.ItemStat{}}
+		var win, loss int
+		if _, err := fmt.Sscanf(r.Summary, "%d-%d", &win, &loss); err == nil {
+			item.Stats = append(item.Stats, ms.ItemStat{Name: "wins", Value: float64(win)}, ms.ItemStat{Name: "losses", Value: float64(loss)})
+			if r.Type == "total" {
+				rec.Win, rec.Loss = win, loss
+			}
+		}
+		rec.Items = append(rec.Items, item)
+	}
+	return rec
+}
+
 func marshalMSGameStatus(gs GameStatus) (*ms.GameStatus, error) {
 	return &ms.GameStatus{Clock: gs.Clock, Period: gs.Period, State: gs.StatusType.State, Detail: gs.StatusType.Detail}, nil
 }
@@ -604,10 +828,22 @@ func marshalMSLink(l *Link) (*ms.Link, error) {
 	return &link, nil
 }
 
+//marshalMSVenue returns nil for the empty venue of feeds that don't carry one
+func marshalMSVenue(v Venue) *ms.Venue {
+	if v.ID == "" && v.FullName == "" {
+		return nil
+	}
+	venue := ms.Venue{EntityID: ms.EntityID{ID: "", Extracted: nil, ExtractedSrc: ""},
+		LocalID: v.ID, FullName: v.FullName,
+		Address:  marshalMSAddress(v.Address),
+		Capacity: v.Capacity, IsIndoor: v.IsIndoor}
+	venue.TimeZone = ms.VenueTimeZone(&venue)
+	return &venue
+}
+
 func marshalMSTeam(t *Team) (*ms.Team, error) {
 	team := ms.Team{}
-	//TODO: probably want to fetch the right team and start with latest fetch?
-	team.TeamIDESPN = t.UID
+	team.TeamIDESPN = t.ID
 	team.Abbreviation = t.Abbreviation
 	team.Name = t.Name
 	team.Location = t.Location
@@ -631,24 +867,24 @@ func marshalMSTeam(t *Team) (*ms.Team, error) {
 		team.Links = append(team.Links, lnk)
 	}
 
+	team.Venue = marshalMSVenue(t.Venue)
+
 	if team.Records == nil {
 		team.Records = []*ms.TeamSeasonRecords{}
 	}
-	tsr := ms.TeamSeasonRecords{Season: nil, Summary: "", Stats: nil}
-	//TODO: label the record
-	for _, item := range t.Record.Items { // Item has a Summary and a range of Items... Summary is description
-		tsr.Summary = item.Summary
-		tStats := []*ms.Stat{}
-		for _, stat := range item.Stats { // each TeamRecord
-			tStats = append(tStats, &ms.Stat{Key: stat.Name, LongKey: stat.Name, Value: stat.Value})
+	if t.Record != nil {
+		for _, item := range t.Record.Items { // one record per item e.g. total, home, road
+			tsr := ms.TeamSeasonRecords{Type: item.Type, Description: item.Description, Summary: item.Summary}
+			tStats := []*ms.Stat{}
+			for _, stat := range item.Stats { // each TeamRecord
+				tStats = append(tStats, &ms.Stat{Key: stat.Name, LongKey: stat.Name, Value: stat.Value})
+			}
+			tsr.Stats = validateStats("team "+t.ID+" "+item.Type+" record", tStats)
+			team.Records = append(team.Records, &tsr)
 		}
-		tsr.Stats = tStats
 	}
-	team.Records = append(team.Records, &tsr)
-	// TODO: build roster model
 	if team.Rosters == nil {
 		team.Rosters = []*ms.TeamSeasonRoster{}
 	}
-	// marshal the roster for the team / game
 	return &team, nil
 }
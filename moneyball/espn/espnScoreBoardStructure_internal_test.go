@@ -36,6 +36,7 @@ import (
 	"io/ioutil"
 	"log"
 	"testing"
+	"time"
 	"go-moneyball/moneyball/ms"
 
 	"github.com/stretchr/testify/assert"
@@ -63,3 +64,139 @@ func TestMarshalMSTeam(t *testing.T) {
 	//should have a certain number of teams
 	log.Printf("espnTeam->MSTeam worked\n")
 }
+
+func TestTeamSportMarshalMS(t *testing.T) {
+	b, err := ioutil.ReadFile(tFilename)
+	assert.Nil(t, err, fmt.Errorf("couldn't read file: %s", tFilename))
+	ts := TeamSport{League: NBA}
+	assert.Nil(t, json.Unmarshal(b, &ts))
+	// give a team two record items to check none is overwritten
+	raw := &ts.Sport[0].Leagues[0].Teams[0].Team
+	raw.Record.Items = append(raw.Record.Items, Item{Type: "home", Summary: "4-12"})
+	raw.Venue = Venue{ID: "1827", FullName: "State Farm Arena", Capacity: 16600, IsIndoor: true}
+
+	teams, err := ts.MarshalMS()
+	assert.Nil(t, err)
+	assert.Equal(t, len(ts.Sport[0].Leagues[0].Teams), len(teams))
+
+	first := teams[0]
+	if assert.Len(t, first.Records, 2) {
+		assert.Equal(t, raw.Record.Items[0].Summary, first.Records[0].Summary)
+		assert.Equal(t, "home", first.Records[1].Type)
+		assert.Equal(t, "4-12", first.Records[1].Summary)
+		assert.NotNil(t, first.Records[0].Season)
+		assert.Equal(t, 2, first.Records[1].Season.SeasonStage)
+		assert.False(t, first.Records[0].Season == first.Records[1].Season, "records should not share a season")
+	}
+	if assert.NotNil(t, first.Venue) {
+		assert.Equal(t, "State Farm Arena", first.Venue.FullName)
+	}
+	assert.Nil(t, teams[1].Venue, "no venue in the feed")
+}
+
+func TestLeagueMSSeason(t *testing.T) {
+	dec := time.Date(2019, time.December, 30, 0, 0, 0, 0, time.UTC)
+	l := League{}
+	assert.Equal(t, ms.Season{SeasonYear: 2019, SeasonStage: 2}, l.msSeason(NBA, dec), "ESPN's 2020 is the 2019-20 season")
+	assert.Equal(t, 2019, l.msSeason(WNBA, dec).SeasonYear)
+	assert.Equal(t, 2019, NBA.SeasonYear(time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)))
+	l.Season = &SeasonDef{Year: 2018, Type: SeasonType{Type: 3}}
+	assert.Equal(t, ms.Season{SeasonYear: 2017, SeasonStage: int(ms.StagePlayoffs)}, l.msSeason(NBA, dec))
+	assert.Equal(t, ms.Season{SeasonYear: 2019, SeasonStage: int(ms.StagePlayIn)}, PremierLeague.MSSeason(2019, 5))
+}
+
+func TestLeagueMSSeasonCalendar(t *testing.T) {
+	b, err := ioutil.ReadFile(sbFilename)
+	assert.Nil(t, err)
+	sb := ScoreBoard{}
+	assert.Nil(t, json.Unmarshal(b, &sb))
+
+	c := sb.Leagues[0].MSSeasonCalendar(NBA)
+	assert.Equal(t, ms.LeagueNBA, c.League)
+	assert.Equal(t, 2019, c.Season)
+	dec := time.Date(2019, time.December, 29, 20, 0, 0, 0, time.UTC)
+	stage, ok := c.StageOn(dec)
+	assert.True(t, ok)
+	assert.Equal(t, ms.StageRegular, stage)
+	assert.True(t, c.IsGameDay(dec))
+	assert.Equal(t, len(sb.Leagues[0].Calendar), len(c.GameDays))
+
+	// a blacklist calendar lists the days without games
+	l := League{CalendarType: "day", CalendarStartDate: espnTime(time.Date(2019, time.December, 23, 8, 0, 0, 0, time.UTC)),
+		CalendarEndDate: espnTime(time.Date(2019, time.December, 27, 8, 0, 0, 0, time.UTC)),
+		Calendar:        []espnTime{espnTime(time.Date(2019, time.December, 24, 8, 0, 0, 0, time.UTC))}}
+	c = l.MSSeasonCalendar(NBA)
+	assert.Equal(t, 4, len(c.GameDays))
+	assert.False(t, c.IsGameDay(time.Date(2019, time.December, 24, 20, 0, 0, 0, time.UTC)), "Christmas Eve is listed")
+	assert.True(t, c.IsGameDay(time.Date(2019, time.December, 25, 20, 0, 0, 0, time.UTC)))
+}
+
+func TestMarshalMSLinesAndBroadcasts(t *testing.T) {
+	b, err := ioutil.ReadFile(sbFilename)
+	assert.Nil(t, err, fmt.Errorf("couldn't read file: %s", sbFilename))
+	sb := ScoreBoard{}
+	assert.Nil(t, json.Unmarshal(b, &sb))
+
+	var comp *Competition
+	for i := range sb.Events {
+		if sb.Events[i].ID == "401161136" { // BKN @ MIN, "BKN -5.0"
+			comp = &sb.Events[i].Competitions[0]
+		}
+	}
+	if !assert.NotNil(t, comp) {
+		return
+	}
+	captured := time.Date(2019, 12, 29, 19, 41, 0, 0, time.UTC)
+	lines := comp.marshalMSLines("401161136", captured)
+	if assert.Equal(t, 1, len(lines)) {
+		assert.Equal(t, "Caesars", lines[0].Provider)
+		assert.Equal(t, "BKN", lines[0].FavoriteAbbreviation)
+		assert.Equal(t, 5.0, lines[0].HomeSpread, "the visiting Nets are favored so Minnesota gets 5")
+		assert.Equal(t, 216.5, lines[0].Total)
+		assert.Equal(t, captured, *lines[0].CapturedAt)
+	}
+	assert.Equal(t, lines[0], ms.ClosingLine(lines, "Caesars", captured.Add(time.Hour)))
+	assert.Nil(t, ms.ClosingLine(lines, "", captured.Add(-time.Hour)), "captured after tip-off")
+	assert.Nil(t, comp.marshalMSLines("401161136", time.Time{})[0].CapturedAt, "no extract time, no capture time")
+
+	// the capture time is the fetch time the scoreboard was stamped with, so marshalling is reproducible
+	sb.Extract(captured, "test")
+	for i := range sb.Events {
+		if sb.Events[i].ID == "401161136" {
+			e, err := sb.Events[i].MarshalMSEvent(sb.Leagues[0])
+			assert.Nil(t, err)
+			if assert.Equal(t, 1, len(e.Lines)) {
+				assert.Equal(t, captured, *e.Lines[0].CapturedAt)
+			}
+			assert.Equal(t, "test", sb.Events[i].ExtractedSrc)
+		}
+	}
+
+	broadcasts := comp.marshalMSBroadcasts("401161136")
+	if assert.Equal(t, 2, len(broadcasts)) {
+		assert.Equal(t, &ms.Broadcast{GameID: "401161136", Network: "FSN", Market: "home", Type: "TV", Region: "us", Language: "en"}, broadcasts[0])
+		assert.Equal(t, "YES", broadcasts[1].Network)
+	}
+
+	odds := Competition{Competitors: []Competitor{{HomeAway: "home", Team: Team{Abbreviation: "MIL"}}},
+		Odds: []Odd{{Provider: OddProvider{Name: "Caesars"}, Details: "MIL -9.0", Spread: -9.5,
+			HomeTeamOdds: &TeamOdds{Favorite: true, MoneyLine: -450}, AwayTeamOdds: &TeamOdds{MoneyLine: 350}}},
+		Broadcasts: []Broadcast{{Market: "national", Names: []string{"ESPN"}}}}
+	lines = odds.marshalMSLines("1", captured)
+	assert.Equal(t, -9.5, lines[0].HomeSpread, "the feed's spread wins over the details")
+	assert.Equal(t, -450, lines[0].HomeMoneyLine)
+	assert.Equal(t, 350, lines[0].VisitMoneyLine)
+	assert.True(t, odds.marshalMSBroadcasts("1")[0].IsNational)
+}
+
+func TestMarshalMSCompetitorLeaders(t *testing.T) {
+	comp := Competitor{HomeAway: "home", Team: Team{ID: "9", Abbreviation: "GS"},
+		Leaders: []StatLeader{{Name: "points", Leaders: []AthLeader{{DisplayValue: "36", Athlete: Athlete{ID: "3975", DisplayName: "Stephen Curry"}}}}}}
+	c, err := comp.marshalMSCompetitor(NBA)
+	assert.Nil(t, err)
+	assert.Equal(t, "GSW", c.Abbreviation)
+	if assert.Equal(t, 1, len(c.Leaders)) {
+		assert.Equal(t, "GSW", c.Leaders[0].TeamID, "the canonical team, not ESPN's id")
+		assert.Equal(t, "3975", c.Leaders[0].PlayerID)
+	}
+}
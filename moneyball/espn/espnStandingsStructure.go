@@ -0,0 +1,195 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// ESPN standings are served from the apis/v2 (not apis/site/v2) tree
+//
+//Standings: https://site.api.espn.com/apis/v2/sports/basketball/nba/standings
+//
+// "children":[{"name":"Eastern Conference","abbreviation":"East","standings":{"season":2020,"seasonType":2,
+//	"entries":[{"team":{"id":"15","abbreviation":"MIL","displayName":"Milwaukee Bucks"},
+//	"stats":[{"name":"wins","value":28.0},{"name":"losses","value":5.0},{"name":"winPercent","value":0.848},
+//	{"name":"gamesBehind","value":0.0},{"name":"streak","value":2.0,"displayValue":"W2"},{"name":"playoffSeed","value":1.0},
+//	{"name":"Home","type":"home","summary":"15-1"},{"name":"Road","type":"road","summary":"13-4"},
+//	{"name":"vs. Div.","type":"vsdiv","summary":"5-1"},{"name":"vs. Conf.","type":"vsconf","summary":"17-3"},
+//	{"name":"Last Ten Games","type":"lasttengames","summary":"8-2"},{"name":"clincher","displayValue":"x"}]}]}}]
+
+import (
+	"go-moneyball/moneyball/ms"
+	"strings"
+	"time"
+)
+
+const (
+	//EspnStandingsURLPrefix is the URL filepath prefix for standings, which are only on v2 of the non-site API's
+	EspnStandingsURLPrefix = "apis/v2/sports/"
+)
+
+//Standings ...
+type Standings struct {
+	Name     string           `json:"name,omitempty"` // "National Basketball Association"
+	Children []StandingsGroup `json:"children"`
+	League   LeagueSlug       `json:"-"` // set by the fetcher
+}
+
+//StandingsGroup ... a conference, or a division when standings are requested by division
+type StandingsGroup struct {
+	Name         string           `json:"name"`         // "Eastern Conference"
+	Abbreviation string           `json:"abbreviation"` // "East"
+	Children     []StandingsGroup `json:"children,omitempty"`
+	Standings    StandingsTable   `json:"standings"`
+}
+
+//StandingsTable ...
+type StandingsTable struct {
+	Season     int              `json:"season"`     // 2020
+	SeasonType int              `json:"seasonType"` // 2
+	Entries    []StandingsEntry `json:"entries"`
+}
+
+//StandingsEntry ... one team in the table
+type StandingsEntry struct {
+	Team  StandingsTeam   `json:"team"`
+	Stats []StandingsStat `json:"stats"`
+}
+
+//StandingsTeam ...
+type StandingsTeam struct {
+	ID           string `json:"id"`           // "15"
+	Abbreviation string `json:"abbreviation"` // "MIL"
+	DisplayName  string `json:"displayName"`  // "Milwaukee Bucks"
+}
+
+//StandingsStat ...
+type StandingsStat struct {
+	Name         string  `json:"name"`                   // "wins", "Home"
+	Type         string  `json:"type,omitempty"`         // "home"
+	Value        float64 `json:"value,omitempty"`        // 28.0
+	DisplayValue string  `json:"displayValue,omitempty"` // "W2"
+	Summary      string  `json:"summary,omitempty"`      // "15-1"
+}
+
+//MarshalMS marshals the standings to a ms.StandingsSnapshot, ESPN doesn't date its standings so asOf dates
+//the snapshot and when zero today is used
+func (s *Standings) MarshalMS(asOf time.Time) (*ms.StandingsSnapshot, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	asOf = ms.StandingDate(asOf)
+	ss := ms.StandingsSnapshot{League: s.League.MSLeague(), AsOf: asOf}
+	for _, conference := range s.Children {
+		if len(conference.Children) == 0 {
+			ss.Standings = append(ss.Standings, conference.marshalMSStandings(s.League, asOf, conference.Abbreviation, "")...)
+			continue
+		}
+		for _, division := range conference.Children {
+			ss.Standings = append(ss.Standings, division.marshalMSStandings(s.League, asOf, conference.Abbreviation, division.Name)...)
+		}
+	}
+	return &ss, nil
+}
+
+func (g *StandingsGroup) marshalMSStandings(slug LeagueSlug, asOf time.Time, conference string, division string) []*ms.Standing {
+	standings := []*ms.Standing{}
+	league := slug.MSLeague()
+	season := slug.MSSeason(g.Standings.Season, g.Standings.SeasonType)
+	for _, entry := range g.Standings.Entries {
+		st := entry.marshalMSStanding(league, asOf, season)
+		st.Conference = conference
+		if st.Division == "" {
+			st.Division = division
+		}
+		standings = append(standings, st)
+	}
+	return standings
+}
+
+func (e *StandingsEntry) marshalMSStanding(league ms.League, asOf time.Time, season ms.Season) *ms.Standing {
+	st := ms.Standing{
+		TeamIDESPN:   e.Team.ID,
+		Abbreviation: e.Team.Abbreviation,
+		League:       league,
+		Season:       season,
+		AsOf:         asOf,
+	}
+	// ESPN abbreviations differ from NBA tricodes ("GS" vs "GSW") and conference groups have no division, the
+	// registry gives the canonical team so both providers write the same standing
+	if team := ms.TeamRegistryFor(league).Resolve(&ms.Team{TeamIDESPN: e.Team.ID, Abbreviation: e.Team.Abbreviation}); team != nil {
+		st.TeamIDNBA = team.TeamIDNBA
+		st.Abbreviation = team.Abbreviation
+		st.Division = team.Division
+	}
+	for _, stat := range e.Stats {
+		kind := strings.ToLower(stat.Type)
+		if kind == "" {
+			kind = strings.ToLower(stat.Name)
+		}
+		switch kind {
+		case "wins":
+			st.Wins = int(stat.Value)
+		case "losses":
+			st.Losses = int(stat.Value)
+		case "winpercent":
+			st.WinPct = stat.Value
+		case "gamesbehind":
+			st.GamesBack = stat.Value
+		case "streak":
+			st.Streak = int(stat.Value)
+		case "playoffseed":
+			st.ConferenceRank = int(stat.Value)
+		case "home":
+			st.Home, _ = ms.ParseStandingSplit(stat.Summary)
+		case "road", "away":
+			st.Away, _ = ms.ParseStandingSplit(stat.Summary)
+		case "vsconf":
+			st.ConferenceRecord, _ = ms.ParseStandingSplit(stat.Summary)
+		case "vsdiv":
+			st.DivisionRecord, _ = ms.ParseStandingSplit(stat.Summary)
+		case "lasttengames":
+			st.LastTen, _ = ms.ParseStandingSplit(stat.Summary)
+		case "clincher":
+			// ESPN: "x" clinched playoffs, "y" division, "z" conference, "*" best record, "e" eliminated
+			st.ClinchCode = stat.DisplayValue
+			switch strings.ToLower(stat.DisplayValue) {
+			case "x":
+				st.ClinchedPlayoffs = true
+			case "y":
+				st.ClinchedPlayoffs, st.ClinchedDivision = true, true
+			case "z", "*":
+				st.ClinchedPlayoffs, st.ClinchedDivision, st.ClinchedConference = true, true, true
+			case "e", "o":
+				st.Eliminated = true
+			}
+		}
+	}
+	st.ID = ms.StandingID(asOf, st.Abbreviation)
+	return &st
+}
@@ -0,0 +1,93 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tStandings = `{"name":"National Basketball Association","children":[
+{"name":"Eastern Conference","abbreviation":"East","standings":{"season":2020,"seasonType":2,"entries":[
+ {"team":{"id":"15","abbreviation":"MIL","displayName":"Milwaukee Bucks"},"stats":[{"name":"wins","value":28.0},
+ {"name":"losses","value":5.0},{"name":"winPercent","value":0.848},{"name":"gamesBehind","value":0.0},
+ {"name":"streak","value":2.0,"displayValue":"W2"},{"name":"playoffSeed","value":1.0},
+ {"name":"Home","type":"home","summary":"15-1"},{"name":"vs. Conf.","type":"vsconf","summary":"17-3"},
+ {"name":"clincher","displayValue":"x"}]}]}},
+{"name":"Western Conference","abbreviation":"West","standings":{"season":2020,"seasonType":2,"entries":[
+ {"team":{"id":"9","abbreviation":"GS","displayName":"Golden State Warriors"},"stats":[{"name":"wins","value":9.0},
+ {"name":"losses","value":29.0},{"name":"gamesBehind","value":21.5},{"name":"streak","value":-3.0,"displayValue":"L3"},
+ {"name":"clincher","displayValue":"e"}]}]}}]}`
+
+func TestStandingsMarshalMS(t *testing.T) {
+	s := Standings{League: NBA}
+	assert.Nil(t, json.Unmarshal([]byte(tStandings), &s))
+	asOf := time.Date(2020, 1, 6, 15, 4, 5, 0, time.UTC)
+	ss, err := s.MarshalMS(asOf)
+	assert.Nil(t, err)
+	assert.Equal(t, "2020-01-06", ss.AsOf.Format("2006-01-02"))
+	assert.Equal(t, 2, len(ss.Standings))
+
+	mil := ss.Team("MIL")
+	assert.NotNil(t, mil)
+	assert.Equal(t, "2020-01-06.MIL", mil.ID)
+	assert.Equal(t, "1610612749", mil.TeamIDNBA)
+	assert.Equal(t, "15", mil.TeamIDESPN)
+	assert.Equal(t, "East", mil.Conference)
+	assert.Equal(t, "Central", mil.Division, "from the team registry")
+	assert.Equal(t, 28, mil.Wins)
+	assert.Equal(t, "15-1", mil.Home.Summary())
+	assert.True(t, mil.ClinchedPlayoffs)
+	assert.Equal(t, 2019, mil.Season.SeasonYear, "named for the year the season starts")
+
+	gsw := ss.Team("GSW")
+	assert.NotNil(t, gsw, "ESPN's GS is keyed on the canonical tricode")
+	assert.Equal(t, "2020-01-06.GSW", gsw.ID)
+	assert.Equal(t, "1610612744", gsw.TeamIDNBA)
+	assert.Equal(t, "Pacific", gsw.Division)
+	assert.Equal(t, -3, gsw.Streak)
+	assert.True(t, gsw.Eliminated)
+}
+
+func TestStandingsMarshalMSUnknownTeam(t *testing.T) {
+	s := Standings{League: NBA, Children: []StandingsGroup{{Name: "Eastern Conference", Abbreviation: "East",
+		Standings: StandingsTable{Season: 2020, SeasonType: 2, Entries: []StandingsEntry{
+			{Team: StandingsTeam{ID: "999", Abbreviation: "XYZ"}}}}}}}
+	ss, err := s.MarshalMS(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	st := ss.Team("XYZ")
+	assert.NotNil(t, st, "a team the registry doesn't know keeps the ESPN abbreviation")
+	assert.Equal(t, "", st.TeamIDNBA)
+	assert.Equal(t, "", st.Division)
+}
@@ -0,0 +1,550 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// ESPN game summary, the per game detail behind a scoreboard event
+//
+//Summary: https://site.api.espn.com/apis/site/v2/sports/basketball/nba/summary?event=:eventId
+//
+// "plays":[{"id":"4011615594","sequenceNumber":"4","type":{"id":"615","text":"Jumpball"},
+//	"text":"Marc Gasol vs. Anthony Davis (Kyle Lowry gains possession)","awayScore":0,"homeScore":0,
+//	"period":{"number":1,"displayValue":"1st Quarter"},"clock":{"displayValue":"12:00"},
+//	"scoringPlay":false,"scoreValue":0,"team":{"id":"28"},"participants":[{"athlete":{"id":"3206"}},...],
+//	"wallclock":"2019-10-23T00:12:20Z","shootingPlay":false,"coordinate":{"x":25,"y":0}}, ...]
+//
+// "boxscore":{"teams":[{"team":{"id":"13"},"homeAway":"away","statistics":[{"name":"fieldGoalsMade-fieldGoalsAttempted",
+//	"displayValue":"42-88","label":"FG"},...]}],
+//	"players":[{"team":{"id":"13"},"statistics":[{"names":["MIN","FG",...],"keys":["minutes","fieldGoalsMade-fieldGoalsAttempted",...],
+//	"athletes":[{"athlete":{"id":"6583","displayName":"Anthony Davis"},"starter":true,"didNotPlay":false,"stats":["37","8-21",...]}]}]}]}
+// "winprobability":[{"homeWinPercentage":0.552,"tiePercentage":0.0,"playId":"4011615594"},...]
+// "gameInfo":{"attendance":19800,"officials":[{"fullName":"Scott Foster","position":{"name":"Referee"},"order":1},...]}
+
+import (
+	"fmt"
+	"log"
+	"go-moneyball/moneyball/ms"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var freeThrowOf = regexp.MustCompile(`(\d) of (\d)`)
+
+//Summary ...
+type Summary struct {
+	Header         SummaryHeader        `json:"header"`
+	Boxscore       SummaryBoxscore      `json:"boxscore"`
+	Leaders        []SummaryTeamLeaders `json:"leaders,omitempty"`
+	Plays          []Play               `json:"plays,omitempty"`
+	WinProbability []WinProbability     `json:"winprobability,omitempty"`
+	GameInfo       SummaryGameInfo      `json:"gameInfo"`
+	League         LeagueSlug           `json:"-"` // set by the fetcher
+}
+
+//SummaryHeader ...
+type SummaryHeader struct {
+	ID           string               `json:"id"`  // "401161559" the event id
+	UID          string               `json:"uid"` // "s:40~l:46~e:401161559"
+	Competitions []SummaryCompetition `json:"competitions,omitempty"`
+}
+
+//SummaryCompetition ...
+type SummaryCompetition struct {
+	ID          string              `json:"id"` // "401161559"
+	Competitors []SummaryCompetitor `json:"competitors,omitempty"`
+}
+
+//SummaryCompetitor ...
+type SummaryCompetitor struct {
+	ID       string `json:"id"`              // "28" the team id
+	HomeAway string `json:"homeAway"`        // "home"
+	Score    string `json:"score,omitempty"` // "113"
+}
+
+//SummaryBoxscore ...
+type SummaryBoxscore struct {
+	Teams   []BoxscoreTeam        `json:"teams,omitempty"`
+	Players []BoxscoreTeamPlayers `json:"players,omitempty"`
+}
+
+//BoxscoreTeam ... team totals
+type BoxscoreTeam struct {
+	Team       Team                `json:"team"`
+	HomeAway   string              `json:"homeAway,omitempty"` // "away"
+	Statistics []BoxscoreStatistic `json:"statistics,omitempty"`
+}
+
+//BoxscoreStatistic ...
+type BoxscoreStatistic struct {
+	Name         string `json:"name"`            // "fieldGoalsMade-fieldGoalsAttempted"
+	DisplayValue string `json:"displayValue"`    // "42-88"
+	Label        string `json:"label,omitempty"` // "FG"
+}
+
+//BoxscoreTeamPlayers ... the player lines of a team
+type BoxscoreTeamPlayers struct {
+	Team       Team                  `json:"team"`
+	Statistics []BoxscorePlayerGroup `json:"statistics,omitempty"`
+}
+
+//BoxscorePlayerGroup ... a stat table, the stats of each athlete line up with Names/Keys
+type BoxscorePlayerGroup struct {
+	Names    []string          `json:"names,omitempty"`  // ["MIN","FG","3PT",...]
+	Keys     []string          `json:"keys,omitempty"`   // ["minutes","fieldGoalsMade-fieldGoalsAttempted",...]
+	Labels   []string          `json:"labels,omitempty"` // same as names on most feeds
+	Athletes []BoxscoreAthlete `json:"athletes,omitempty"`
+	Totals   []string          `json:"totals,omitempty"`
+}
+
+//BoxscoreAthlete ... a player line
+type BoxscoreAthlete struct {
+	Active     bool           `json:"active"`
+	Athlete    SummaryAthlete `json:"athlete"`
+	Starter    bool           `json:"starter"`
+	DidNotPlay bool           `json:"didNotPlay"`
+	Reason     string         `json:"reason,omitempty"` // "COACH'S DECISION"
+	Ejected    bool           `json:"ejected"`
+	Stats      []string       `json:"stats,omitempty"` // ["37","8-21",...]
+}
+
+//SummaryAthlete ... the summary's athlete, unlike Athlete the headshot is an object
+type SummaryAthlete struct {
+	ID          string   `json:"id"`                    // "6583"
+	DisplayName string   `json:"displayName,omitempty"` // "Anthony Davis"
+	ShortName   string   `json:"shortName,omitempty"`   // "A. Davis"
+	Jersey      string   `json:"jersey,omitempty"`      // "3"
+	Position    Position `json:"position"`
+}
+
+//SummaryTeamLeaders ... a team's game leaders by category
+type SummaryTeamLeaders struct {
+	Team    Team                    `json:"team"`
+	Leaders []SummaryLeaderCategory `json:"leaders,omitempty"`
+}
+
+//SummaryLeaderCategory ...
+type SummaryLeaderCategory struct {
+	Name        string          `json:"name"`                  // "points"
+	DisplayName string          `json:"displayName,omitempty"` // "Points"
+	Leaders     []SummaryLeader `json:"leaders,omitempty"`
+}
+
+//SummaryLeader ...
+type SummaryLeader struct {
+	DisplayValue string         `json:"displayValue"` // "36"
+	Athlete      SummaryAthlete `json:"athlete"`
+}
+
+//WinProbability ... the home team's win probability after a play
+type WinProbability struct {
+	HomeWinPercentage float64 `json:"homeWinPercentage"` // 0.552
+	TiePercentage     float64 `json:"tiePercentage"`     // 0.0
+	PlayID            string  `json:"playId"`            // "4011615594"
+}
+
+//SummaryGameInfo ...
+type SummaryGameInfo struct {
+	Attendance int               `json:"attendance,omitempty"` // 19800
+	Officials  []SummaryOfficial `json:"officials,omitempty"`
+}
+
+//SummaryOfficial ...
+type SummaryOfficial struct {
+	FullName    string           `json:"fullName"`              // "Scott Foster"
+	DisplayName string           `json:"displayName,omitempty"` // "Scott Foster"
+	Position    OfficialPosition `json:"position"`
+	Order       int              `json:"order"` // 1
+}
+
+//OfficialPosition ...
+type OfficialPosition struct {
+	ID          string `json:"id,omitempty"`          // "4"
+	Name        string `json:"name"`                  // "Referee"
+	DisplayName string `json:"displayName,omitempty"` // "Referee"
+}
+
+//Teams returns the home and away team ids of the game
+func (s *Summary) Teams() (string, string) {
+	home, away := "", ""
+	for _, c := range s.Header.Competitions {
+		for _, comp := range c.Competitors {
+			switch comp.HomeAway {
+			case "home":
+				home = comp.ID
+			case "away":
+				away = comp.ID
+			}
+		}
+	}
+	return home, away
+}
+
+//PlayType ...
+type PlayType struct {
+	ID   string `json:"id"`   // "615"
+	Text string `json:"text"` // "Jumpball"
+}
+
+//PlayPeriod ...
+type PlayPeriod struct {
+	Number       int    `json:"number"`       // 1
+	DisplayValue string `json:"displayValue"` // "1st Quarter"
+}
+
+//PlayClock ...
+type PlayClock struct {
+	DisplayValue string `json:"displayValue"` // "11:44"
+}
+
+//PlayTeam ...
+type PlayTeam struct {
+	ID string `json:"id"` // "28"
+}
+
+//PlayAthlete ...
+type PlayAthlete struct {
+	ID string `json:"id"` // "3206"
+}
+
+//PlayParticipant ...
+type PlayParticipant struct {
+	Athlete PlayAthlete `json:"athlete"`
+}
+
+//PlayCoordinate ...
+type PlayCoordinate struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+//Play ... one row of the summary play-by-play
+type Play struct {
+	ID             string            `json:"id"`             // "4011615594"
+	SequenceNumber string            `json:"sequenceNumber"` // "4"
+	Type           PlayType          `json:"type"`
+	Text           string            `json:"text"`
+	AwayScore      int               `json:"awayScore"`
+	HomeScore      int               `json:"homeScore"`
+	Period         PlayPeriod        `json:"period"`
+	Clock          PlayClock         `json:"clock"`
+	ScoringPlay    bool              `json:"scoringPlay"`
+	ScoreValue     int               `json:"scoreValue"`
+	Team           *PlayTeam         `json:"team,omitempty"`
+	Participants   []PlayParticipant `json:"participants,omitempty"`
+	Wallclock      *time.Time        `json:"wallclock,omitempty"` // "2019-10-23T00:12:20Z"
+	ShootingPlay   bool              `json:"shootingPlay"`
+	Coordinate     *PlayCoordinate   `json:"coordinate,omitempty"`
+}
+
+//MarshalMSPlayEvents marshals the summary plays to the canonical ms.PlayEvent stream
+func (s *Summary) MarshalMSPlayEvents() ([]*ms.PlayEvent, error) {
+	events := []*ms.PlayEvent{}
+	for i := range s.Plays {
+		ev, err := s.Plays[i].marshalMSPlayEvent()
+		if err != nil {
+			return events, err
+		}
+		ev.GameID = ms.GameID(s.Header.ID)
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+func (p *Play) marshalMSPlayEvent() (*ms.PlayEvent, error) {
+	ev := ms.PlayEvent{}
+	seq, err := strconv.Atoi(p.SequenceNumber)
+	if err != nil && p.SequenceNumber != "" {
+		return nil, fmt.Errorf("play %s: invalid sequenceNumber %s", p.ID, p.SequenceNumber)
+	}
+	ev.Sequence = seq
+	ev.Period = p.Period.Number
+	ev.Clock = p.Clock.DisplayValue
+	seconds, err := ms.ParseGameClock(p.Clock.DisplayValue)
+	if err != nil {
+		return nil, fmt.Errorf("play %s: %s", p.ID, err)
+	}
+	ev.ClockSeconds = seconds
+	ev.WallClock = p.Wallclock
+	if p.Team != nil {
+		ev.TeamID = p.Team.ID
+	}
+	ev.SubType = p.Type.Text
+	ev.Description = p.Text
+	ev.HomeScore = p.HomeScore
+	ev.VisitScore = p.AwayScore
+
+	text := strings.ToLower(p.Type.Text)
+	switch {
+	case strings.HasPrefix(text, "free throw"):
+		ev.Type = ms.PlayFreeThrow
+		ev.ShotValue = 1
+		ev.ShotResult = p.shotResult()
+		ev.FreeThrow = &ms.FreeThrowAttempt{Number: 1, Of: 1}
+		if m := freeThrowOf.FindStringSubmatch(p.Type.Text); m != nil {
+			ev.FreeThrow.Number, _ = strconv.Atoi(m[1])
+			ev.FreeThrow.Of, _ = strconv.Atoi(m[2])
+		}
+		p.addPlayers(&ev, ms.RoleShooter)
+	case p.ShootingPlay:
+		ev.Type = ms.PlayShot
+		ev.ShotValue = 2
+		if p.ScoringPlay && p.ScoreValue > 0 {
+			ev.ShotValue = p.ScoreValue
+		} else if strings.Contains(strings.ToLower(p.Text), "three point") {
+			ev.ShotValue = 3
+		}
+		ev.ShotResult = p.shotResult()
+		if ev.ShotResult == ms.ShotMade {
+			p.addPlayers(&ev, ms.RoleShooter, ms.RoleAssist)
+		} else {
+			p.addPlayers(&ev, ms.RoleShooter, ms.RoleBlock)
+		}
+		if p.Coordinate != nil {
+			ev.ShotLocation = &ms.ShotLocation{X: p.Coordinate.X, Y: p.Coordinate.Y}
+		}
+	case strings.Contains(text, "rebound"):
+		ev.Type = ms.PlayRebound
+		p.addPlayers(&ev, ms.RoleRebounder)
+	case strings.Contains(text, "turnover"):
+		ev.Type = ms.PlayTurnover
+		p.addPlayers(&ev, ms.RoleTurnover, ms.RoleSteal)
+	case strings.Contains(text, "foul"):
+		ev.Type = ms.PlayFoul
+		p.addPlayers(&ev, ms.RoleFouler, ms.RoleFouled)
+	case strings.Contains(text, "violation"):
+		ev.Type = ms.PlayViolation
+		p.addPlayers(&ev, ms.RolePrimary)
+	case text == "substitution":
+		// ESPN lists the player entering first: "X enters the game for Y"
+		ev.Type = ms.PlaySubstitution
+		p.addPlayers(&ev, ms.RoleSubIn, ms.RoleSubOut)
+	case strings.Contains(text, "timeout"):
+		ev.Type = ms.PlayTimeout
+	case text == "jumpball":
+		ev.Type = ms.PlayJumpBall
+		p.addPlayers(&ev, ms.RoleJumper, ms.RoleJumper, ms.RolePossession)
+	case strings.Contains(text, "ejection"):
+		ev.Type = ms.PlayEjection
+		p.addPlayers(&ev, ms.RolePrimary)
+	case strings.HasPrefix(text, "end period"), strings.HasPrefix(text, "end game"):
+		ev.Type = ms.PlayPeriodEnd
+	case strings.HasPrefix(text, "start period"):
+		ev.Type = ms.PlayPeriodStart
+	default:
+		ev.Type = ms.PlayOther
+	}
+	return &ev, nil
+}
+
+//shotResult infers made/missed, ESPN only marks scoring plays and uses "misses" in the text
+func (p *Play) shotResult() ms.ShotResult {
+	if p.ScoringPlay || (!strings.Contains(strings.ToLower(p.Text), "miss") && p.ScoreValue > 0) {
+		return ms.ShotMade
+	}
+	return ms.ShotMissed
+}
+
+//addPlayers assigns roles to participants in the order ESPN lists them
+func (p *Play) addPlayers(ev *ms.PlayEvent, roles ...string) {
+	for i, role := range roles {
+		if i >= len(p.Participants) || p.Participants[i].Athlete.ID == "" {
+			return
+		}
+		pl := &ms.PlayEventPlayer{PlayerID: p.Participants[i].Athlete.ID, Role: role}
+		if i == 0 && p.Team != nil {
+			pl.TeamID = p.Team.ID
+		}
+		ev.Players = append(ev.Players, pl)
+	}
+}
+
+//MarshalMSStints reconstructs the game's stints (see ms.BuildStints) from the summary plays
+func (s *Summary) MarshalMSStints() (*ms.StintTable, error) {
+	events, err := s.MarshalMSPlayEvents()
+	if err != nil {
+		return nil, err
+	}
+	home, away := s.Teams()
+	stints, err := ms.BuildStints(events, home, away)
+	if err != nil {
+		return nil, err
+	}
+	return &ms.StintTable{GameID: ms.GameID(s.Header.ID), League: s.League.MSLeague(), Stints: stints}, nil
+}
+
+//MarshalMS normalizes the summary to a ms.GameSummary: player lines, team totals, leaders, officials,
+//play events and win probability, can return partial results when a play can't be marshalled
+func (s *Summary) MarshalMS() (*ms.GameSummary, error) {
+	gameID := s.Header.ID
+	gs := ms.GameSummary{GameID: ms.GameID(gameID), League: s.League.MSLeague()}
+	gs.HomeTeamID, gs.VisitTeamID = s.Teams()
+
+	for _, tp := range s.Boxscore.Players {
+		for _, group := range tp.Statistics {
+			keys := group.Names
+			if len(keys) == 0 {
+				keys = group.Labels
+			}
+			for _, a := range group.Athletes {
+				ps := ms.GamePlayersStats{PlayerID: a.Athlete.ID, TeamID: tp.Team.ID, GameID: gameID,
+					Starter: a.Starter, DidNotPlay: a.DidNotPlay, Reason: a.Reason}
+				stats := []*ms.Stat{}
+				for i, v := range a.Stats {
+					st := ms.Stat{Value: statValue(v)}
+					if i < len(keys) {
+						st.Key = keys[i]
+					}
+					if i < len(group.Keys) {
+						st.LongKey = group.Keys[i]
+					}
+					stats = append(stats, &st)
+				}
+				ps.Stats = validateStats("player "+a.Athlete.ID+" of game "+gameID, stats)
+				gs.Players = append(gs.Players, &ps)
+			}
+		}
+	}
+
+	for _, bt := range s.Boxscore.Teams {
+		ts := ms.GameTeamStats{TeamID: bt.Team.ID, GameID: gameID, HomeAway: bt.HomeAway, Stats: []*ms.Stat{}}
+		if ts.HomeAway == "" {
+			switch bt.Team.ID {
+			case gs.HomeTeamID:
+				ts.HomeAway = "home"
+			case gs.VisitTeamID:
+				ts.HomeAway = "away"
+			}
+		}
+		stats := []*ms.Stat{}
+		for _, stat := range bt.Statistics {
+			stats = append(stats, &ms.Stat{Key: stat.Label, LongKey: stat.Name, Value: statValue(stat.DisplayValue)})
+		}
+		ts.Stats = validateStats("team "+bt.Team.ID+" of game "+gameID, stats)
+		gs.Teams = append(gs.Teams, &ts)
+	}
+
+	for _, tl := range s.Leaders {
+		for _, category := range tl.Leaders {
+			for _, l := range category.Leaders {
+				gs.Leaders = append(gs.Leaders, &ms.GameLeader{TeamID: tl.Team.ID, Category: category.Name,
+					PlayerID: l.Athlete.ID, Name: l.Athlete.DisplayName, Value: l.DisplayValue})
+			}
+		}
+	}
+
+	gs.Officials = s.MarshalMSOfficials()
+
+	plays, err := s.MarshalMSPlayEvents()
+	gs.Plays = plays
+	sequence := map[string]int{}
+	for i := range s.Plays {
+		if i < len(plays) {
+			sequence[s.Plays[i].ID] = plays[i].Sequence
+		}
+	}
+	for _, wp := range s.WinProbability {
+		gs.WinProbability = append(gs.WinProbability, &ms.WinProbability{PlayID: wp.PlayID, Sequence: sequence[wp.PlayID],
+			HomeWinPercentage: wp.HomeWinPercentage, TiePercentage: wp.TiePercentage})
+	}
+	return &gs, err
+}
+
+//MarshalMSOfficials returns the crew of the game, the officials are resolved when they are attached to the
+//event e.g. by setting Event.Officials before MarshalMSEvent
+func (s *Summary) MarshalMSOfficials() []*ms.GameOfficial {
+	officials := []*ms.GameOfficial{}
+	for _, o := range s.GameInfo.Officials {
+		name := o.FullName
+		if name == "" {
+			name = o.DisplayName
+		}
+		officials = append(officials, &ms.GameOfficial{Name: name, Position: o.Position.Name, Order: o.Order})
+	}
+	return officials
+}
+
+//MarshalMSTeamGameStats normalizes the box score totals of both teams, points come from the header as the box
+//score leaves them out.  Scoreboard competitor statistics are season totals so game totals only come from here
+func (s *Summary) MarshalMSTeamGameStats() []*ms.TeamGameStats {
+	league := s.League.MSLeague()
+	scores := map[string]string{}
+	for _, c := range s.Header.Competitions {
+		for _, comp := range c.Competitors {
+			scores[comp.ID] = comp.Score
+		}
+	}
+	home, away := s.Teams()
+	stats := []*ms.TeamGameStats{}
+	for _, bt := range s.Boxscore.Teams {
+		ts := ms.NewTeamGameStats(league, ms.GameID(s.Header.ID), bt.Team.ID, ms.ProviderESPN)
+		ts.Abbreviation = bt.Team.Abbreviation
+		if team := ms.TeamRegistryFor(league).ByESPNID(bt.Team.ID); team != nil {
+			ts.Abbreviation = team.Abbreviation
+		}
+		ts.HomeAway = bt.HomeAway
+		switch {
+		case ts.HomeAway != "":
+		case bt.Team.ID == home:
+			ts.HomeAway = "home"
+		case bt.Team.ID == away:
+			ts.HomeAway = "away"
+		}
+		ts.Set("points", scores[bt.Team.ID])
+		box := []*ms.Stat{}
+		for _, stat := range bt.Statistics {
+			box = append(box, &ms.Stat{Key: stat.Label, LongKey: stat.Name, Value: statValue(stat.DisplayValue)})
+		}
+		for _, st := range validateStats("team "+bt.Team.ID+" of game "+s.Header.ID, box) {
+			ts.Add(st)
+		}
+		stats = append(stats, ts)
+	}
+	return stats
+}
+
+//validateStats normalizes the ESPN stats of a record through the stat vocabulary, the stats it rejects are logged
+//and left out
+func validateStats(record string, stats []*ms.Stat) []*ms.Stat {
+	valid, err := ms.DefaultStatVocabulary().Validate(ms.ProviderESPN, record, stats)
+	if err != nil {
+		log.Printf("%s\n", err)
+	}
+	return valid
+}
+
+//statValue keeps numbers as numbers, made-attempted pairs "8-21" and anything else stay as ESPN displays them
+func statValue(v string) interface{} {
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return f
+	}
+	return v
+}
@@ -0,0 +1,151 @@
+package espn
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go-moneyball/moneyball/ms"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tSummaryPlays = `{"header":{"id":"401161559","uid":"s:40~l:46~e:401161559"},"plays":[
+{"id":"4011615594","sequenceNumber":"4","type":{"id":"615","text":"Jumpball"},"text":"Marc Gasol vs. Anthony Davis (Kyle Lowry gains possession)",
+ "awayScore":0,"homeScore":0,"period":{"number":1,"displayValue":"1st Quarter"},"clock":{"displayValue":"12:00"},"scoringPlay":false,"scoreValue":0,
+ "team":{"id":"28"},"participants":[{"athlete":{"id":"3206"}},{"athlete":{"id":"6583"}},{"athlete":{"id":"3012"}}],"wallclock":"2019-10-23T00:12:20Z","shootingPlay":false},
+{"id":"4011615597","sequenceNumber":"7","type":{"id":"92","text":"Jump Shot"},"text":"Pascal Siakam makes 24-foot three point jumper (Kyle Lowry assists)",
+ "awayScore":0,"homeScore":3,"period":{"number":1,"displayValue":"1st Quarter"},"clock":{"displayValue":"11:44"},"scoringPlay":true,"scoreValue":3,
+ "team":{"id":"28"},"participants":[{"athlete":{"id":"3149673"}},{"athlete":{"id":"3012"}}],"wallclock":"2019-10-23T00:12:41Z","shootingPlay":true,"coordinate":{"x":2,"y":10}},
+{"id":"40116155912","sequenceNumber":"12","type":{"id":"584","text":"Substitution"},"text":"Danny Green enters the game for Tony Bradley",
+ "awayScore":0,"homeScore":3,"period":{"number":1,"displayValue":"1st Quarter"},"clock":{"displayValue":"10:58"},"scoringPlay":false,"scoreValue":0,
+ "team":{"id":"13"},"participants":[{"athlete":{"id":"2990984"}},{"athlete":{"id":"4066648"}}],"shootingPlay":false}]}`
+
+func TestSummaryMarshalMSPlayEvents(t *testing.T) {
+	s := Summary{}
+	assert.Nil(t, json.Unmarshal([]byte(tSummaryPlays), &s))
+	events, err := s.MarshalMSPlayEvents()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(events))
+
+	assert.Equal(t, ms.PlayJumpBall, events[0].Type)
+	assert.Equal(t, ms.GameID("401161559"), events[0].GameID)
+	assert.Equal(t, "3012", events[0].Player(ms.RolePossession).PlayerID)
+	assert.NotNil(t, events[0].WallClock)
+
+	shot := events[1]
+	assert.Equal(t, ms.PlayShot, shot.Type)
+	assert.Equal(t, ms.ShotMade, shot.ShotResult)
+	assert.Equal(t, 3, shot.ShotValue)
+	assert.Equal(t, 704.0, shot.ClockSeconds)
+	assert.Equal(t, "3012", shot.Player(ms.RoleAssist).PlayerID)
+	assert.Equal(t, &ms.ShotLocation{X: 2, Y: 10}, shot.ShotLocation)
+
+	sub := events[2]
+	assert.Equal(t, ms.PlaySubstitution, sub.Type)
+	assert.Equal(t, "2990984", sub.Player(ms.RoleSubIn).PlayerID)
+	assert.Equal(t, "4066648", sub.Player(ms.RoleSubOut).PlayerID)
+}
+
+const tSummaryBox = `{"header":{"id":"401161559","competitions":[{"id":"401161559","competitors":[
+ {"id":"28","homeAway":"home"},{"id":"13","homeAway":"away"}]}]},
+"boxscore":{"teams":[{"team":{"id":"13","abbreviation":"LAL"},"statistics":[
+  {"name":"fieldGoalsMade-fieldGoalsAttempted","displayValue":"42-88","label":"FG"},{"name":"fieldGoalPct","displayValue":"47.7","label":"Field Goal %"},
+  {"name":"totalRebounds","displayValue":"45","label":"Rebounds"},{"name":"madeUpStat","displayValue":"3","label":"XYZ"}]},
+ {"team":{"id":"28","abbreviation":"TOR"},"statistics":[{"name":"fieldGoalsMade-fieldGoalsAttempted","displayValue":"40-91","label":"FG"}]}],
+ "players":[{"team":{"id":"13"},"statistics":[{"names":["MIN","FG","PTS"],"keys":["minutes","fieldGoalsMade-fieldGoalsAttempted","points"],
+  "athletes":[{"active":true,"athlete":{"id":"6583","displayName":"Anthony Davis","headshot":{"href":"https://a.espncdn.com/i/headshots/nba/players/full/6583.png"}},
+   "starter":true,"didNotPlay":false,"ejected":false,"stats":["37","8-21","25"]},
+   {"active":false,"athlete":{"id":"4066648","displayName":"Talen Horton-Tucker"},"starter":false,"didNotPlay":true,"reason":"COACH'S DECISION","stats":[]}]}]}]},
+"leaders":[{"team":{"id":"28"},"leaders":[{"name":"points","displayName":"Points","leaders":[{"displayValue":"36","athlete":{"id":"3012","displayName":"Kyle Lowry"}}]}]}],
+"plays":[{"id":"4011615594","sequenceNumber":"4","type":{"id":"615","text":"Jumpball"},"text":"Marc Gasol vs. Anthony Davis",
+ "period":{"number":1},"clock":{"displayValue":"12:00"},"team":{"id":"28"}}],
+"winprobability":[{"homeWinPercentage":0.552,"tiePercentage":0.0,"playId":"4011615594"}],
+"gameInfo":{"attendance":19800,"officials":[{"fullName":"Scott Foster","displayName":"Scott Foster","position":{"name":"Referee","id":"4"},"order":1}]}}`
+
+func TestSummaryMarshalMS(t *testing.T) {
+	s := Summary{League: NBA}
+	assert.Nil(t, json.Unmarshal([]byte(tSummaryBox), &s))
+	gs, err := s.MarshalMS()
+	assert.Nil(t, err)
+	assert.Equal(t, ms.LeagueNBA, gs.League)
+	assert.Equal(t, "28", gs.HomeTeamID)
+	assert.Equal(t, "13", gs.VisitTeamID)
+
+	ad := gs.Player("6583")
+	if assert.NotNil(t, ad) {
+		assert.True(t, ad.Starter)
+		assert.Equal(t, "13", ad.TeamID)
+		assert.Equal(t, 25.0, ad.Stat("points").Value)
+		assert.Equal(t, 8.0, ad.Stat("FGM").Value, "made-attempted is split")
+		assert.Equal(t, 21.0, ad.Stat("fieldGoalsAttempted").Value)
+	}
+	dnp := gs.Player("4066648")
+	if assert.NotNil(t, dnp) {
+		assert.True(t, dnp.DidNotPlay)
+		assert.Equal(t, "COACH'S DECISION", dnp.Reason)
+	}
+
+	lal := gs.Team("13")
+	if assert.NotNil(t, lal) {
+		assert.Equal(t, "away", lal.HomeAway)
+		assert.Equal(t, 47.7, lal.Stat("fieldGoalPct").Value)
+		assert.Equal(t, 45.0, lal.Stat("rebounds").Value, "ESPN's totalRebounds")
+		assert.Nil(t, lal.Stat("madeUpStat"), "unknown stats are left out")
+	}
+	assert.Equal(t, "home", gs.Team("28").HomeAway)
+
+	assert.Equal(t, 1, len(gs.Leaders))
+	assert.Equal(t, "3012", gs.Leaders[0].PlayerID)
+	assert.Equal(t, []*ms.GameOfficial{{Name: "Scott Foster", Position: "Referee", Order: 1}}, gs.Officials, "resolved by the caller")
+	assert.Equal(t, 1, len(gs.Plays))
+	assert.Equal(t, []*ms.WinProbability{{PlayID: "4011615594", Sequence: 4, HomeWinPercentage: 0.552}}, gs.WinProbability)
+}
+
+func TestSummaryMarshalMSTeamGameStats(t *testing.T) {
+	s := Summary{League: NBA}
+	assert.Nil(t, json.Unmarshal([]byte(tSummaryBox), &s))
+	s.Header.Competitions[0].Competitors[1].Score = "112"
+	stats := s.MarshalMSTeamGameStats()
+	if assert.Len(t, stats, 2) {
+		lal := stats[0]
+		assert.Equal(t, ms.GameID("401161559"), lal.GameID)
+		assert.Equal(t, ms.ProviderESPN, lal.Source)
+		assert.Equal(t, "away", lal.HomeAway)
+		assert.Equal(t, 112, lal.Points)
+		assert.Equal(t, 42, lal.FieldGoalsMade)
+		assert.Equal(t, 88, lal.FieldGoalsAttempted)
+		assert.Empty(t, lal.Other, "the percentage is derived")
+		assert.Equal(t, "home", stats[1].HomeAway)
+		assert.Equal(t, 91, stats[1].FieldGoalsAttempted)
+	}
+}
@@ -62,22 +62,42 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 //All Teams: http://site.api.espn.com/apis/site/v2/sports/basketball/mens-college-basketball/teams
 //Specific Team: http://site.api.espn.com/apis/site/v2/sports/basketball/mens-college-basketball/teams/:team
 //
-//ScoreBoardService will, for a http client, return a ScoreBoard JSON object
+//ScoreBoardService will, for a http client, return a ScoreBoard JSON object, every ESPN service takes the
+//espn.LeagueSlug of the league to fetch
 //
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"go-moneyball/moneyball/espn"
+	"go-moneyball/moneyball/ms"
 	"net/url"
 	"os"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/iterator"
 )
 
 //ESPNBoxScoreService provides a fetcher for ESPN's scoreboard API that will pull the latest scoreboard (todays games & results)
 func (s *ScoreService) ESPNBoxScoreService(ctx context.Context) (*(espn.ScoreBoard), *Response, error) {
+	return s.ESPNScoreBoardService(ctx, nil)
+}
 
+//ESPNScoreBoardService will, for a http client, return the scoreboard selected by q (league, dates, groups, limit),
+//a nil query is today's NBA scoreboard
+//		https://site.api.espn.com/apis/site/v2/sports/basketball/nba/scoreboard?dates=20191229
+func (s *ScoreService) ESPNScoreBoardService(ctx context.Context, q *espn.ScoreBoardQuery) (*espn.ScoreBoard, *Response, error) {
+	if err := knownLeague(q.LeagueSlug()); err != nil {
+		return nil, nil, err
+	}
 	s.client.BaseURL, _ = url.Parse(espn.EspnBaseURL)
-	req, err := s.client.NewRequest("GET", espn.EspnURLPrefix+"nba/scoreboard", nil)
+	req, err := s.client.NewRequest("GET", q.Path(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	//to support gzip encoding uncomment... should probably default to true
 	//req.Header.Add("Accept-Encoding", "gzip")
@@ -91,18 +111,94 @@ func (s *ScoreService) ESPNBoxScoreService(ctx context.Context) (*(espn.ScoreBoa
 	sb := &espn.ScoreBoard{}
 	resp, err := s.client.Do(ctx, req, sb, false)
 	if err != nil {
-		log.Fatalf("Error on new request: %s\n", err)
+		log.Printf("Error on new request: %s\n", err)
 		return nil, resp, err
 	}
+	sb.Extract(time.Now().UTC(), req.URL.String())
 	return sb, resp, err
 }
 
-//ESPNTeamsService will, for a http client, return a ScoreBoard JSON object
-//
-func (s *StatsService) ESPNTeamsService(ctx context.Context) (*espn.TeamSport, *Response, error) {
+//knownLeague rejects a league espn.LeagueFor doesn't know rather than fetch it from a path ESPN doesn't serve
+func knownLeague(league espn.LeagueSlug) error {
+	if !league.Known() {
+		return fmt.Errorf("unknown ESPN league %q", league)
+	}
+	return nil
+}
 
+//ScoreBoardIterator walks the scoreboards of a list of days, one request per day
+type ScoreBoardIterator struct {
+	ctx     context.Context
+	service *ScoreService
+	query   espn.ScoreBoardQuery
+	days    []time.Time
+	next    int
+}
+
+//ESPNScoreBoards returns an iterator over the scoreboard of each day, q supplies the league, groups and limit
+func (s *ScoreService) ESPNScoreBoards(ctx context.Context, q *espn.ScoreBoardQuery, days []time.Time) *ScoreBoardIterator {
+	it := &ScoreBoardIterator{ctx: ctx, service: s, days: days}
+	if q != nil {
+		it.query = *q
+	}
+	return it
+}
+
+//ESPNSeasonScoreBoards fetches the scoreboard for the season's year to read the league calendar, and returns an
+//iterator over every game day of the season
+func (s *ScoreService) ESPNSeasonScoreBoards(ctx context.Context, q *espn.ScoreBoardQuery) (*ScoreBoardIterator, error) {
+	sb, _, err := s.ESPNScoreBoardService(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(sb.Leagues) == 0 {
+		return nil, fmt.Errorf("scoreboard for %s has no league calendar", q.LeagueSlug())
+	}
+	return s.ESPNScoreBoards(ctx, q, sb.Leagues[0].GameDays()), nil
+}
+
+//Next returns the scoreboard of the next day, or iterator.Done when all days have been fetched
+func (it *ScoreBoardIterator) Next() (*espn.ScoreBoard, error) {
+	if it.next >= len(it.days) {
+		return nil, iterator.Done
+	}
+	day := it.days[it.next]
+	it.next++
+	q := it.query
+	q.Dates = espn.DateParam(day)
+	sb, _, err := it.service.ESPNScoreBoardService(it.ctx, &q)
+	if err != nil {
+		return nil, fmt.Errorf("scoreboard %s: %s", q.Dates, err)
+	}
+	return sb, nil
+}
+
+//All drains the iterator into one merged scoreboard, stopping at the first error
+func (it *ScoreBoardIterator) All() (*espn.ScoreBoard, error) {
+	merged := &espn.ScoreBoard{}
+	for {
+		sb, err := it.Next()
+		if err == iterator.Done {
+			return merged, nil
+		}
+		if err != nil {
+			return merged, err
+		}
+		merged.Merge(sb)
+	}
+}
+
+//ESPNTeamsService will, for a http client, return the teams of a league
+//		https://site.api.espn.com/apis/site/v2/sports/basketball/wnba/teams
+func (s *StatsService) ESPNTeamsService(ctx context.Context, league espn.LeagueSlug) (*espn.TeamSport, *Response, error) {
+	if err := knownLeague(league); err != nil {
+		return nil, nil, err
+	}
 	s.client.BaseURL, _ = url.Parse(espn.EspnBaseURL)
-	req, err := s.client.NewRequest("GET", espn.EspnURLPrefix+"nba/teams", nil)
+	req, err := s.client.NewRequest("GET", league.SitePath("teams"), nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	//to support gzip encoding uncomment... should probably default to true
 	//req.Header.Add("Accept-Encoding", "gzip")
@@ -112,11 +208,226 @@ func (s *StatsService) ESPNTeamsService(ctx context.Context) (*espn.TeamSport, *
 	if exists {
 		req.Header.Set("User-Agent", agent)
 	}
-	teams := &espn.TeamSport{}
+	teams := &espn.TeamSport{League: league}
 	resp, err := s.client.Do(ctx, req, teams, false)
 	if err != nil {
-		log.Fatalf("Error on new request: %s\n", err)
+		log.Printf("Error on new request: %s\n", err)
 		return nil, resp, err
 	}
 	return teams, resp, err
 }
+
+//ESPNPlayByPlayService will, for a http client, return the game summary (including plays) for an ESPN event id of a league
+//		https://site.api.espn.com/apis/site/v2/sports/basketball/nba/summary?event=401161559
+func (s *ScoreService) ESPNPlayByPlayService(ctx context.Context, league espn.LeagueSlug, eventID string) (*espn.Summary, *Response, error) {
+	return s.ESPNGameSummary(ctx, league, eventID)
+}
+
+//ESPNGameSummary will, for a http client, return the game summary of an ESPN event id of a league: box score,
+//leaders, plays, win probability and game info (officials)
+//		https://site.api.espn.com/apis/site/v2/sports/basketball/nba/summary?event=401161559
+func (s *ScoreService) ESPNGameSummary(ctx context.Context, league espn.LeagueSlug, eventID string) (*espn.Summary, *Response, error) {
+	if err := knownLeague(league); err != nil {
+		return nil, nil, err
+	}
+	s.client.BaseURL, _ = url.Parse(espn.EspnBaseURL)
+	req, err := s.client.NewRequest("GET", league.SitePath("summary")+"?event="+url.QueryEscape(eventID), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agent, exists := os.LookupEnv("ESPN_USERAGENT")
+	if exists {
+		req.Header.Set("User-Agent", agent)
+	}
+
+	sum := &espn.Summary{League: league}
+	resp, err := s.client.Do(ctx, req, sum, false)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return sum, resp, err
+}
+
+//ESPNStandingsService will, for a http client, return the league standings by conference
+//		https://site.api.espn.com/apis/v2/sports/basketball/nba/standings
+func (s *StatsService) ESPNStandingsService(ctx context.Context, league espn.LeagueSlug) (*espn.Standings, *Response, error) {
+	if err := knownLeague(league); err != nil {
+		return nil, nil, err
+	}
+	s.client.BaseURL, _ = url.Parse(espn.EspnBaseURL)
+	req, err := s.client.NewRequest("GET", league.StandingsPath(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agent, exists := os.LookupEnv("ESPN_USERAGENT")
+	if exists {
+		req.Header.Set("User-Agent", agent)
+	}
+
+	standings := &espn.Standings{League: league}
+	resp, err := s.client.Do(ctx, req, standings, false)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return standings, resp, err
+}
+
+//ESPNTeamRosterService will, for a http client, return the roster of a team by ESPN id or abbreviation e.g. "28" or "tor"
+//		https://site.api.espn.com/apis/site/v2/sports/basketball/nba/teams/28/roster
+func (s *StatsService) ESPNTeamRosterService(ctx context.Context, league espn.LeagueSlug, team string) (*espn.Roster, *Response, error) {
+	if err := knownLeague(league); err != nil {
+		return nil, nil, err
+	}
+	s.client.BaseURL, _ = url.Parse(espn.EspnBaseURL)
+	req, err := s.client.NewRequest("GET", league.SitePath("teams/"+url.PathEscape(team)+"/roster"), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agent, exists := os.LookupEnv("ESPN_USERAGENT")
+	if exists {
+		req.Header.Set("User-Agent", agent)
+	}
+
+	roster := &espn.Roster{League: league}
+	resp, err := s.client.Do(ctx, req, roster, false)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return roster, resp, err
+}
+
+//ESPNScoreBoardRosters fetches the roster of each team on the scoreboard and attaches them to its events (see
+//espn.ScoreBoard.AttachRosters), a team whose roster can't be fetched plays without one and is reported in the error
+func (s *StatsService) ESPNScoreBoardRosters(ctx context.Context, league espn.LeagueSlug, sb *espn.ScoreBoard) ([]*ms.TeamSeasonRoster, error) {
+	rosters := []*ms.TeamSeasonRoster{}
+	errs := []error{}
+	for _, id := range sb.TeamIDs() {
+		r, _, err := s.ESPNTeamRosterService(ctx, league, id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("team %s roster: %s", id, err))
+			continue
+		}
+		roster, err := r.MarshalMS()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("team %s roster: %s", id, err))
+			continue
+		}
+		rosters = append(rosters, roster)
+	}
+	sb.AttachRosters(rosters...)
+	return rosters, errors.Join(errs...)
+}
+
+//ESPNAthlete will, for a http client, return the profile (bio, draft, college, injuries, season stats) of an athlete of a league
+//		https://site.web.api.espn.com/apis/common/v3/sports/basketball/nba/athletes/3012
+func (s *PlayerService) ESPNAthlete(ctx context.Context, league espn.LeagueSlug, athleteID string) (*espn.AthleteProfile, *Response, error) {
+	if err := knownLeague(league); err != nil {
+		return nil, nil, err
+	}
+	s.client.BaseURL, _ = url.Parse(espn.EspnWebBaseURL)
+	req, err := s.client.NewRequest("GET", league.AthletePath(url.PathEscape(athleteID), ""), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agent, exists := os.LookupEnv("ESPN_USERAGENT")
+	if exists {
+		req.Header.Set("User-Agent", agent)
+	}
+
+	profile := &espn.AthleteProfile{League: league}
+	resp, err := s.client.Do(ctx, req, profile, false)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return profile, resp, err
+}
+
+//ESPNAthleteGameLog will, for a http client, return the per game stat lines of an athlete of a league for a season
+//year e.g. "2020" for 2019-20, an empty season is the current season
+//		https://site.web.api.espn.com/apis/common/v3/sports/basketball/nba/athletes/3012/gamelog?season=2020
+func (s *PlayerService) ESPNAthleteGameLog(ctx context.Context, league espn.LeagueSlug, athleteID string, season string) (*espn.AthleteGameLog, *Response, error) {
+	if err := knownLeague(league); err != nil {
+		return nil, nil, err
+	}
+	s.client.BaseURL, _ = url.Parse(espn.EspnWebBaseURL)
+	path := league.AthletePath(url.PathEscape(athleteID), "gamelog")
+	if season != "" {
+		path = path + "?season=" + url.QueryEscape(season)
+	}
+	req, err := s.client.NewRequest("GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agent, exists := os.LookupEnv("ESPN_USERAGENT")
+	if exists {
+		req.Header.Set("User-Agent", agent)
+	}
+
+	gameLog := &espn.AthleteGameLog{AthleteID: athleteID, League: league}
+	resp, err := s.client.Do(ctx, req, gameLog, false)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return gameLog, resp, err
+}
+
+//ESPNNewsService will, for a http client, return the latest news articles of a league, limit 0 leaves ESPN's default
+//		https://site.api.espn.com/apis/site/v2/sports/basketball/nba/news?limit=50
+func (s *StatsService) ESPNNewsService(ctx context.Context, league espn.LeagueSlug, limit int) (*espn.News, *Response, error) {
+	if err := knownLeague(league); err != nil {
+		return nil, nil, err
+	}
+	s.client.BaseURL, _ = url.Parse(espn.EspnBaseURL)
+	path := league.SitePath("news")
+	if limit > 0 {
+		path = path + "?limit=" + strconv.Itoa(limit)
+	}
+	req, err := s.client.NewRequest("GET", path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agent, exists := os.LookupEnv("ESPN_USERAGENT")
+	if exists {
+		req.Header.Set("User-Agent", agent)
+	}
+
+	news := &espn.News{League: league}
+	resp, err := s.client.Do(ctx, req, news, false)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return news, resp, err
+}
+
+//ESPNNewsSince fetches the latest limit articles of a league and returns the items published after since (the last
+//published time seen by the caller) with the new last-seen time to pass on the next call.  When every article
+//fetched is newer than since older ones may have been missed, which is logged, poll more often or raise the limit
+func (s *StatsService) ESPNNewsSince(ctx context.Context, league espn.LeagueSlug, since time.Time, limit int) (*ms.NewsFeed, time.Time, error) {
+	news, _, err := s.ESPNNewsService(ctx, league, limit)
+	if err != nil {
+		return nil, since, err
+	}
+	feed, err := news.MarshalMS()
+	if err != nil {
+		return nil, since, err
+	}
+	fetched := len(feed.Items)
+	var latest time.Time
+	feed.Items, latest = ms.NewsSince(feed.Items, since)
+	if !since.IsZero() && fetched > 0 && len(feed.Items) == fetched {
+		log.Printf("%s news: all %d articles are newer than %s, older ones may have been missed\n", league, fetched, since)
+	}
+	return feed, latest, nil
+}
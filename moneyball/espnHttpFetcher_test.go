@@ -33,6 +33,7 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 import (
 	"context"
 	"fmt"
+	"go-moneyball/moneyball/espn"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -65,8 +66,17 @@ func TestESPNScoreBoardMarshalService(t *testing.T) {
 func TestESPNTeamService(t *testing.T) {
 	client := NewClient(nil)
 	ctx := context.Background()
-	teams, _, err := client.Schedule.client.Stats.ESPNTeamsService(ctx)
+	teams, _, err := client.Schedule.client.Stats.ESPNTeamsService(ctx, espn.NBA)
 	assert.Nil(t, err, err)
 	assert.NotZero(t, len(teams.Sport[0].Leagues[0].Teams) > 0, "espnTeams should be a positive length response")
 	//fmt.Printf("TeamsService: %d teams for date retrieved %#v\n", len(teams.Sport[0].Leagues[0].Teams), teams.Sport[0].Leagues[0].Teams)
 }
+
+func TestESPNUnknownLeague(t *testing.T) {
+	client := NewClient(nil)
+	ctx := context.Background()
+	_, _, err := client.Stats.ESPNStandingsService(ctx, espn.LeagueSlug("ncaa-m"))
+	assert.NotNil(t, err, "an unknown league is refused, not fetched as the NBA")
+	_, _, err = client.Score.ESPNScoreBoardService(ctx, &espn.ScoreBoardQuery{League: espn.LeagueSlug("nba ")})
+	assert.NotNil(t, err)
+}
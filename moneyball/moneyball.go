@@ -37,6 +37,7 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 import (
 	"context"
 	"fmt"
+	"go-moneyball/moneyball/espn"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -86,6 +87,9 @@ func main() {
 			if err != nil {
 				fmt.Printf("BoxScoreService: Error %s\n", err)
 			} else {
+				if _, err := client.Stats.NBAGameRosters(ctx, temp); err != nil {
+					fmt.Printf("NBAGameRosters: Error %s\n", err)
+				}
 				// replace existing game with the detailed box.
 				fmt.Printf("-orig_game %s\n", game.GameURLCode)
 				(*schedule)[i] = *temp
@@ -117,15 +121,18 @@ func main() {
 	if err != nil {
 		fmt.Printf("ScoreBoardService: Error: %s\n", err)
 	}
+	if _, err := client.Stats.ESPNScoreBoardRosters(ctx, espn.NBA, scoreboard); err != nil {
+		fmt.Printf("ESPNScoreBoardRosters: Error: %s\n", err)
+	}
 	sb, _ := scoreboard.MarshalMS()
 	//spew.Printf("espn.ScoreBoard: %v\n\n", scoreboard)
 	spew.Printf("ms.ScoreBoard: %v\n\n", sb)
 
-	teams, _, err := client.Stats.ESPNTeamsService(ctx)
+	registry, err := client.Stats.RefreshNBATeamRegistry(ctx, "2019")
 	if err != nil {
 		fmt.Printf("TeamsService: Error %s\n", err)
 	}
-	_ = teams
+	fmt.Printf("TeamRegistry: %d teams\n", len(registry.Teams()))
 	//fmt.Printf("TeamsService: %d teams for date retrieved\n", len(teams.Sport[0].Leagues[0].Teams))
 
 }
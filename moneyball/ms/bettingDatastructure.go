@@ -0,0 +1,111 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// betting lines and broadcasts are children of an Event, keyed by GameID.  A line is captured each time a
+// scoreboard is fetched so the lines table keeps the line history of a game, the closing line is the last
+// line captured before tip-off
+
+import (
+	"time"
+)
+
+//BettingLine ... a provider's line for a game at the time it was captured
+type BettingLine struct {
+	GameID               GameID     `json:"gameId"`
+	Provider             string     `json:"provider"`                   // "Caesars"
+	ProviderPriority     int        `json:"providerPriority,omitempty"` // 1 is the provider shown first
+	Details              string     `json:"details,omitempty"`          // "BKN -5.0"
+	FavoriteAbbreviation string     `json:"favorite,omitempty"`         // "BKN", empty for a pick'em
+	HomeSpread           float64    `json:"homeSpread"`                 // points given by the home team, -5.0 when the home team is favored by 5
+	Total                float64    `json:"total,omitempty"`            // over/under 216.5
+	HomeMoneyLine        int        `json:"homeMoneyLine,omitempty"`    // -210
+	VisitMoneyLine       int        `json:"visitMoneyLine,omitempty"`   // 170
+	CapturedAt           *time.Time `json:"capturedAt,omitempty"`
+}
+
+//Broadcast ... a network carrying a game
+type Broadcast struct {
+	GameID     GameID `json:"gameId"`
+	Network    string `json:"network"`            // "ESPN", "FSDT"
+	Market     string `json:"market,omitempty"`   // "national", "home", "away"
+	Type       string `json:"type,omitempty"`     // "TV", "Radio", "Streaming"
+	Region     string `json:"region,omitempty"`   // "us"
+	Language   string `json:"language,omitempty"` // "en"
+	IsNational bool   `json:"isNational"`
+}
+
+//ClosingLine returns the last line of provider captured before start, or nil.  An empty provider takes the
+//line of any provider
+func ClosingLine(lines []*BettingLine, provider string, start time.Time) *BettingLine {
+	var closing *BettingLine
+	for _, l := range lines {
+		if l.CapturedAt == nil || (provider != "" && l.Provider != provider) || l.CapturedAt.After(start) {
+			continue
+		}
+		if closing == nil || l.CapturedAt.After(*closing.CapturedAt) {
+			closing = l
+		}
+	}
+	return closing
+}
+
+//BettingLineTable ... the lines child table of a league's events
+type BettingLineTable struct {
+	League League
+	Lines  []*BettingLine
+}
+
+//BroadcastTable ... the broadcasts child table of a league's events
+type BroadcastTable struct {
+	League     League
+	Broadcasts []*Broadcast
+}
+
+//BettingLines collects the lines of the scoreboard's events for the lines child table
+func (sb *ScoreBoard) BettingLines() *BettingLineTable {
+	t := BettingLineTable{Lines: []*BettingLine{}}
+	for _, e := range sb.Events {
+		t.League = e.League
+		t.Lines = append(t.Lines, e.Lines...)
+	}
+	return &t
+}
+
+//Broadcasts collects the broadcasts of the scoreboard's events for the broadcasts child table
+func (sb *ScoreBoard) Broadcasts() *BroadcastTable {
+	t := BroadcastTable{Broadcasts: []*Broadcast{}}
+	for _, e := range sb.Events {
+		t.League = e.League
+		t.Broadcasts = append(t.Broadcasts, e.Broadcasts...)
+	}
+	return &t
+}
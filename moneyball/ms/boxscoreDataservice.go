@@ -37,6 +37,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/olivere/ndjson"
@@ -99,6 +100,35 @@ func importJSONTruncate(projectID *string, datasetID *string, tableID *string, g
 	return nil
 }
 
+// importJSONAppend loads newline-delimeted JSON data in Cloud Storage and appends it to the existing table,
+// used for tables that keep history such as standings snapshots
+func importJSONAppend(projectID *string, datasetID *string, tableID *string, gscReference string) error {
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, *projectID)
+	if err != nil {
+		return fmt.Errorf("bigquery.NewClient: %v", err)
+	}
+	rRef := bigquery.NewGCSReference(gscReference)
+	rRef.SourceFormat = bigquery.JSON
+	rRef.AutoDetect = true
+	loader := client.Dataset(*datasetID).Table(*tableID).LoaderFrom(rRef)
+	loader.WriteDisposition = bigquery.WriteAppend
+
+	job, err := loader.Run(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.Err() != nil {
+		return fmt.Errorf("job completed with error: %v", status.Err())
+	}
+	return nil
+}
+
 func createDataset(projectID string, datasetID string) (bool, error) {
 	ctx := context.Background()
 	client, err := bigquery.NewClient(ctx, projectID)
@@ -171,6 +201,160 @@ func (sb *ScoreBoard) marshalNBJSON(b *bytes.Buffer) error {
 	return nil
 }
 
+func (st *StintTable) tableName() string {
+	return string("stints" + st.League)
+}
+
+func (st *StintTable) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(st.Stints); i++ {
+		if err := r.Encode(st.Stints[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *StandingsSnapshot) tableName() string {
+	return string("standings" + ss.League)
+}
+
+func (ss *StandingsSnapshot) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(ss.Standings); i++ {
+		if err := r.Encode(ss.Standings[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (nf *NewsFeed) tableName() string {
+	return string("news" + nf.League)
+}
+
+func (nf *NewsFeed) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(nf.Items); i++ {
+		if err := r.Encode(nf.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *BettingLineTable) tableName() string {
+	return string("lines" + t.League)
+}
+
+func (t *BettingLineTable) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(t.Lines); i++ {
+		if err := r.Encode(t.Lines[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *BroadcastTable) tableName() string {
+	return string("broadcasts" + t.League)
+}
+
+func (t *BroadcastTable) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(t.Broadcasts); i++ {
+		if err := r.Encode(t.Broadcasts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *DataQualityTable) tableName() string {
+	return string("dataquality" + t.League)
+}
+
+func (t *DataQualityTable) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(t.Issues); i++ {
+		if err := r.Encode(t.Issues[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TeamGameStatsTable) tableName() string {
+	return string("teamgamestats" + t.League)
+}
+
+func (t *TeamGameStatsTable) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(t.Stats); i++ {
+		if err := r.Encode(t.Stats[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *SeriesTable) tableName() string {
+	return string("series" + t.League)
+}
+
+func (t *SeriesTable) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(t.Series); i++ {
+		if err := r.Encode(t.Series[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *OfficiatingLog) tableName() string {
+	return string("officiating" + l.League)
+}
+
+func (l *OfficiatingLog) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(l.Assignments); i++ {
+		if err := r.Encode(l.Assignments[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *KeyChangeTable) tableName() string {
+	return string("rekey" + t.League)
+}
+
+func (t *KeyChangeTable) marshalNBJSON(b *bytes.Buffer) error {
+	r := ndjson.NewWriter(b)
+	for i := 0; i < len(t.Changes); i++ {
+		if err := r.Encode(t.Changes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *IdentityResolver) tableName() string {
+	return string("crosswalk" + r.League)
+}
+
+func (r *IdentityResolver) marshalNBJSON(b *bytes.Buffer) error {
+	w := ndjson.NewWriter(b)
+	for _, entry := range r.Crosswalk() {
+		if err := w.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func writeFile(filename string, b *bytes.Buffer) {
 	//OPEN FILE TO APPEND CERT INFORMATION INTO
 	f, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
@@ -186,6 +370,19 @@ func writeFile(filename string, b *bytes.Buffer) {
 //InsertRow 1 row into named project and dataset.  note that BigQuery supports
 //Newline Delimited JSON (ndjson) so we need to determine if we have a singleton or an array
 func InsertRow(projectID string, datasetID string, s *ScoreBoard) error {
+	return InsertTable(projectID, datasetID, s)
+}
+
+//endKeyBatch forgets the keys handed out for the table being loaded, logging the collisions they had
+func endKeyBatch() {
+	for _, c := range DefaultKeyer().Reset() {
+		log.Printf("%s key %s collision: %s\n", c.Kind, c.Key, strings.Join(c.Identities, " and "))
+	}
+}
+
+//InsertTable writes any NBJson table (e.g. *ScoreBoard, *StintTable) into the named project and dataset
+func InsertTable(projectID string, datasetID string, s NBJson) error {
+	defer endKeyBatch()
 	ctx := context.Background()
 	client, err := bigquery.NewClient(ctx, projectID)
 	defer client.Close()
@@ -199,6 +396,12 @@ func InsertRow(projectID string, datasetID string, s *ScoreBoard) error {
 		//log.Panicf("row Preparation failure: %v", err)
 		return err
 	}
+	if err := checkTableSchema(projectID, datasetID, tableName); err != nil {
+		return err
+	}
+	if err := DefaultSchemaRegistry().Stamp(TableKind(tableName), &b); err != nil {
+		return err
+	}
 	// dump buffer to file, we can then use the file to load BigQuery?
 	Write(&b, &projectID, "monumental-boxes-nba", "synthetic", nil)
 	// now load the written file to the bigquery tablespace
@@ -214,6 +417,65 @@ func InsertRow(projectID string, datasetID string, s *ScoreBoard) error {
 	return nil
 }
 
+//AppendTable appends any NBJson table (e.g. *StandingsSnapshot) to the named project and dataset, keeping
+//the rows already loaded
+func AppendTable(projectID string, datasetID string, s NBJson) error {
+	defer endKeyBatch()
+	var b bytes.Buffer
+	tableName := s.tableName()
+	if err := s.marshalNBJSON(&b); err != nil {
+		return err
+	}
+	if err := checkTableSchema(projectID, datasetID, tableName); err != nil {
+		return err
+	}
+	if err := DefaultSchemaRegistry().Stamp(TableKind(tableName), &b); err != nil {
+		return err
+	}
+	if err := Write(&b, &projectID, "monumental-boxes-nba", "synthetic", nil); err != nil {
+		return err
+	}
+	return importJSONAppend(&projectID, &datasetID, &tableName, "gs://monumental-boxes-nba/synthetic")
+}
+
+//CheckTableSchema diffs the schema of the rows of an NBJson table against the live table, nil when the table
+//doesn't exist yet
+func CheckTableSchema(projectID string, datasetID string, tableName string) ([]*SchemaDiff, error) {
+	ts, err := DefaultSchemaRegistry().Schema(TableKind(tableName))
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery.NewClient: %v", err)
+	}
+	defer client.Close()
+	meta, err := client.Dataset(datasetID).Table(tableName).Metadata(ctx)
+	if err != nil {
+		if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == 404 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return CompareSchema(StructSchema(ts.Row), meta.Schema), nil
+}
+
+// checkTableSchema refuses a load that would break the live table, columns the struct adds are logged
+func checkTableSchema(projectID string, datasetID string, tableName string) error {
+	diffs, err := CheckTableSchema(projectID, datasetID, tableName)
+	if err != nil {
+		return err
+	}
+	if breaking := BreakingChanges(diffs); len(breaking) > 0 {
+		return fmt.Errorf("%w %s: %v", ErrSchemaIncompatible, tableName, breaking)
+	}
+	for _, d := range diffs {
+		log.Printf("schema %s: %s\n", tableName, d)
+	}
+	return nil
+}
+
 // DeleteDataset ... demonstrates the deletion of an empty dataset.
 func deleteDataset(projectID, datasetID string) error {
 	ctx := context.Background()
@@ -274,31 +536,44 @@ func (v *Venue) MasterEntity() (string, error) {
 }
 
 //MasterEntity returns a string that is used as the master identity (UID/key) for table/data structure
+// element in this case Player it is the canonical id from the crosswalk of the player's league e.g. "nba:200768"
 func (p *Player) MasterEntity() (string, error) {
-	return "", nil
+	if p.EntityID.ID != "" {
+		return p.EntityID.ID, nil
+	}
+	if p.League == "" {
+		return "", fmt.Errorf("player %s has no league to resolve in", p.FullName)
+	}
+	provider := ProviderNBA
+	if p.IDNBA == "" {
+		provider = ProviderESPN
+	}
+	return IdentityResolverFor(p.League).ResolvePlayer(provider, p)
 }
 
 func main() {
 	var bsc = ScoreBoard{
 		[]Event{
 			Event{EntityID{"2019-12-28.WSH.DET", nil, ""}, "2019-12-28.WSH.DET", "NBA", Season{2019, 1},
-				&Competitor{EntityID{"DET-NBA-2019", nil, ""}, "Detroit Pistons", "DET", nil, Record{0, 1, []Item{}}, 0, &[]Score{}, "Detroit", "0x0000", "0xffff", true, false, nil},
-				&Competitor{EntityID{"WAS-NBA-2019", nil, ""}, "Washington Wizards", "WAS", nil, Record{1, 0, []Item{}}, 0, &[]Score{}, "Washington", "0E3764", "e31837", true, false, nil},
-				&Venue{EntityID{}, "", "Little Caesars Arena", &Address{}, 10000, true},
+				&Competitor{EntityID{"DET-NBA-2019", nil, ""}, "Detroit Pistons", "DET", nil, Record{0, 1, []Item{}}, 0, &[]Score{}, "Detroit", "0x0000", "0xffff", true, false, nil, nil, 0, nil, nil, nil},
+				&Competitor{EntityID{"WAS-NBA-2019", nil, ""}, "Washington Wizards", "WAS", nil, Record{1, 0, []Item{}}, 0, &[]Score{}, "Washington", "0E3764", "e31837", true, false, nil, nil, 0, nil, nil, nil},
+				&Venue{EntityID{}, "", "Little Caesars Arena", &Address{}, 10000, true, "America/Detroit"},
 				&GameStatus{0.0, 0, "Final", "Thu, December 28th at 7:00 PM EST"},
 				&[]Link{
 					Link{"http://www.espn.com/nba/team/roster/_/name/det/detroit-pistons",
 						[]string{"roster"}, "roster", nil, false},
 				},
 				&GameDetail{},
+				nil, nil, nil, "",
 			},
 			Event{EntityID{"2017-02-03.TOR.BOS", nil, ""}, "2017-02-03.TOR.BOS", "NBA", Season{2017, 1},
-				&Competitor{EntityID{"TOR-NBA-2017", nil, ""}, "Toronto Raptors", "TOR", nil, Record{1, 0, []Item{}}, 109, &[]Score{}, "Toronto", "0x0000", "0xffff", true, false, nil},
-				&Competitor{EntityID{"BOS-NBA-2017", nil, ""}, "Boston Celtics", "BOS", nil, Record{0, 1, []Item{}}, 104, &[]Score{}, "Boston", "0x0000", "0xffff", true, false, nil},
-				&Venue{EntityID{}, "", "TD Garden", &Address{}, 10000, true},
+				&Competitor{EntityID{"TOR-NBA-2017", nil, ""}, "Toronto Raptors", "TOR", nil, Record{1, 0, []Item{}}, 109, &[]Score{}, "Toronto", "0x0000", "0xffff", true, false, nil, nil, 0, nil, nil, nil},
+				&Competitor{EntityID{"BOS-NBA-2017", nil, ""}, "Boston Celtics", "BOS", nil, Record{0, 1, []Item{}}, 104, &[]Score{}, "Boston", "0x0000", "0xffff", true, false, nil, nil, 0, nil, nil, nil},
+				&Venue{EntityID{}, "", "TD Garden", &Address{}, 10000, true, "America/New_York"},
 				&GameStatus{0.0, 0, "Final", "Thu, February 3rd at 7:00 PM EST"},
 				&[]Link{},
 				&GameDetail{},
+				nil, nil, nil, "",
 			},
 		},
 	}
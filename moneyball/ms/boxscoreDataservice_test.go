@@ -42,10 +42,10 @@ import (
 const ()
 
 var timeN = time.Now()
-var team1 = Team{EntityID{"WAS", &timeN, "sampleData"}, "1610612745", "", "WAS", "Washington Wizards", "Washintgon", nil, nil, nil, nil}
-var team2 = Team{EntityID{"DET", &timeN, "sampleData"}, "", "26", "DET", "Detroit Pistons", "Detroit", nil, nil, nil, nil}
-var team3 = Team{EntityID{"TOR", &timeN, "sampleData"}, "", "25", "TOR", "Toronto Raptors", "Toronto", nil, nil, nil, nil}
-var team4 = Team{EntityID{"BOS", &timeN, "sampleData"}, "", "17", "BOS", "Boston Celtics", "Boston", nil, nil, nil, nil}
+var team1 = Team{EntityID{"WAS", &timeN, "sampleData"}, "1610612745", "", "WAS", "Washington Wizards", "Washintgon", "", "", nil, nil, nil, nil, nil}
+var team2 = Team{EntityID{"DET", &timeN, "sampleData"}, "", "26", "DET", "Detroit Pistons", "Detroit", "", "", nil, nil, nil, nil, nil}
+var team3 = Team{EntityID{"TOR", &timeN, "sampleData"}, "", "25", "TOR", "Toronto Raptors", "Toronto", "", "", nil, nil, nil, nil, nil}
+var team4 = Team{EntityID{"BOS", &timeN, "sampleData"}, "", "17", "BOS", "Boston Celtics", "Boston", "", "", nil, nil, nil, nil, nil}
 
 //type Team struct {
 //Records []*TeamSeasonRecords `json:"records"`
@@ -53,28 +53,30 @@ var team4 = Team{EntityID{"BOS", &timeN, "sampleData"}, "", "17", "BOS", "Boston
 var bss = ScoreBoard{
 	[]Event{
 		Event{EntityID{"2019-12-28.WSH.DET", nil, ""}, "2019-12-28.WSH.DET", "NBA", Season{2019, 1},
-			&Competitor{EntityID{"DET-NBA-2019", nil, ""}, "Detroit Pistons", "DET", &team2, Record{0, 1, []Item{}}, 0, &[]Score{}, "Detroit", "0x0000", "0xffff", true, false, nil},
-			&Competitor{EntityID{"WAS-NBA-2019", nil, ""}, "Washington Wizards", "WAS", &team1, Record{1, 0, []Item{}}, 0, &[]Score{}, "Washington", "0E3764", "e31837", true, false, nil},
-			&Venue{EntityID{}, "", "Little Caesars Arena", &Address{}, 10000, true},
+			&Competitor{EntityID{"DET-NBA-2019", nil, ""}, "Detroit Pistons", "DET", &team2, Record{0, 1, []Item{}}, 0, &[]Score{}, "Detroit", "0x0000", "0xffff", true, false, nil, nil, 0, nil, nil, nil},
+			&Competitor{EntityID{"WAS-NBA-2019", nil, ""}, "Washington Wizards", "WAS", &team1, Record{1, 0, []Item{}}, 0, &[]Score{}, "Washington", "0E3764", "e31837", true, false, nil, nil, 0, nil, nil, nil},
+			&Venue{EntityID{}, "", "Little Caesars Arena", &Address{}, 10000, true, "America/Detroit"},
 			&GameStatus{0.0, 0, "Final", "Thu, December 28th at 7:00 PM EST"},
 			&[]Link{
 				Link{"http://www.espn.com/nba/team/roster/_/name/det/detroit-pistons",
 					[]string{"roster"}, "roster", nil, false},
 			},
 			&GameDetail{},
+			nil, nil, nil, "",
 		},
 		Event{EntityID{"2017-02-03.TOR.BOS", nil, ""}, "2017-02-03.TOR.BOS", "NBA", Season{2017, 1},
-			&Competitor{EntityID{"TOR-NBA-2017", nil, ""}, "Toronto Raptors", "TOR", &team3, Record{1, 0, []Item{}}, 109, &[]Score{}, "Toronto", "0x0000", "0xffff", true, false, nil},
-			&Competitor{EntityID{"BOS-NBA-2017", nil, ""}, "Boston Celtics", "BOS", &team4, Record{0, 1, []Item{}}, 104, &[]Score{}, "Boston", "0x0000", "0xffff", true, false, nil},
-			&Venue{EntityID{}, "", "TD Garden", &Address{}, 10000, true},
+			&Competitor{EntityID{"TOR-NBA-2017", nil, ""}, "Toronto Raptors", "TOR", &team3, Record{1, 0, []Item{}}, 109, &[]Score{}, "Toronto", "0x0000", "0xffff", true, false, nil, nil, 0, nil, nil, nil},
+			&Competitor{EntityID{"BOS-NBA-2017", nil, ""}, "Boston Celtics", "BOS", &team4, Record{0, 1, []Item{}}, 104, &[]Score{}, "Boston", "0x0000", "0xffff", true, false, nil, nil, 0, nil, nil, nil},
+			&Venue{EntityID{}, "", "TD Garden", &Address{}, 10000, true, "America/New_York"},
 			&GameStatus{0.0, 0, "Final", "Thu, February 3rd at 7:00 PM EST"},
 			&[]Link{},
 			&GameDetail{},
+			nil, nil, nil, "",
 		},
 	},
 }
 
-var ven = Venue{EntityID{}, "", "Little Caesars Arena", &Address{Street: "2645 Woodward Avenue", City: "Detroit", State: "MI", Country: "US", GeoLoc: ""}, 20332, true}
+var ven = Venue{EntityID{}, "", "Little Caesars Arena", &Address{Street: "2645 Woodward Avenue", City: "Detroit", State: "MI", Country: "US", GeoLoc: ""}, 20332, true, "America/Detroit"}
 
 //Testing routine for entity mastering - need to figure out how to cary
 //google credentials else it will always fail.
@@ -43,32 +43,64 @@ type GameID string
 //League ...
 type League string
 
-//Season ...
+// leagues we normalize events into
+const (
+	//LeagueNBA ...
+	LeagueNBA League = "NBA"
+	//LeagueWNBA ...
+	LeagueWNBA League = "WNBA"
+	//LeagueNCAAM is men's college basketball
+	LeagueNCAAM League = "NCAAM"
+	//LeagueNCAAW is women's college basketball
+	LeagueNCAAW League = "NCAAW"
+	//LeagueNFL ...
+	LeagueNFL League = "NFL"
+	//LeagueNCAAF is college football
+	LeagueNCAAF League = "NCAAF"
+	//LeagueNHL ...
+	LeagueNHL League = "NHL"
+	//LeagueMLB ...
+	LeagueMLB League = "MLB"
+	//LeagueMLS is Major League Soccer
+	LeagueMLS League = "MLS"
+	//LeagueEPL is the English Premier League
+	LeagueEPL League = "EPL"
+)
+
+//Season ... see SeasonCalendar
 type Season struct {
-	SeasonYear  int `json:"seasonYear,omitempty"`
-	SeasonStage int `json:"seasonStageId,omitempty"`
+	SeasonYear  int `json:"seasonYear,omitempty"`    // year the season starts, 2019 for 2019-20
+	SeasonStage int `json:"seasonStageId,omitempty"` // a Stage, 2 is the regular season
 }
 
 //Competitor ...
 type Competitor struct {
 	EntityID
-	Name           string   `json:"name,omitempty"`
-	Abbreviation   string   `json:"abbreviation"`
-	Team           *Team    `json:"team"`
-	Record         Record   `json:"record,omitempty"`
-	Score          int      `json:"score"`
-	LineScore      *[]Score `json:"linescore,omitempty"` //"linescore":[{"score":"30"},{"score":"32"},{"score":"23"},{"score":"19"}]},
-	Location       string   `json:"location"`
-	Color          string   `json:"color"`
-	AlternateColor string   `json:"alternateColor"`
-	IsActive       bool     `json:"isActive"`
-	IsAllStar      bool     `json:"isAllStar"`
-	Links          *[]Link  `json:"logos"`
+	Name           string            `json:"name,omitempty"`
+	Abbreviation   string            `json:"abbreviation"`
+	Team           *Team             `json:"team"`
+	Record         Record            `json:"record,omitempty"`
+	Score          int               `json:"score"`
+	LineScore      *[]Score          `json:"linescore,omitempty"` //"linescore":[{"score":"30"},{"score":"32"},{"score":"23"},{"score":"19"}]},
+	Location       string            `json:"location"`
+	Color          string            `json:"color"`
+	AlternateColor string            `json:"alternateColor"`
+	IsActive       bool              `json:"isActive"`
+	IsAllStar      bool              `json:"isAllStar"`
+	Links          *[]Link           `json:"logos"`
+	Roster         *TeamSeasonRoster `json:"roster,omitempty"`     // game-day roster, see AttachRoster
+	Rank           int               `json:"rank,omitempty"`       // poll ranking for college teams, 0 when unranked
+	Leaders        []*GameLeader     `json:"leaders,omitempty"`    // e.g. the points leader of the team
+	Statistics     []*Stat           `json:"statistics,omitempty"` // team stats as given with the event e.g. season rebounds
+	GameStats      *TeamGameStats    `json:"gameStats,omitempty"`  // the team's totals for this game
 }
 
 //Score ... used in linescore to show period score for a team/competitor
 type Score struct {
-	Score float32 `json:"score,omitempty"`
+	Score   float32 `json:"score,omitempty"`
+	Period  int     `json:"period,omitempty"`  // 1 based, quarter/half/period/inning of the league's PeriodFormat
+	Label   string  `json:"label,omitempty"`   // "Q1", "H2", "OT", "10"
+	IsExtra bool    `json:"isExtra,omitempty"` // overtime, extra innings or extra time
 }
 
 //Record ... win/loss record for team
@@ -80,11 +112,15 @@ type Record struct {
 
 //Item is a stats element that includes a summary plus a name/value pair
 type Item struct {
-	Summary string `json:"summary"`
-	Stats   []struct {
-		Name  string  `json:"name"`
-		Value float64 `json:"value"`
-	} `json:"stats"`
+	Type    string     `json:"type,omitempty"` // "total", "home", "road"
+	Summary string     `json:"summary"`
+	Stats   []ItemStat `json:"stats"`
+}
+
+//ItemStat ...
+type ItemStat struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
 }
 
 //Venue is the data around the sports venu
@@ -95,6 +131,7 @@ type Venue struct {
 	Address  *Address `json:"address,omitempty"`
 	Capacity int      `json:"capacity"`
 	IsIndoor bool     `json:"indoor"`
+	TimeZone string   `json:"timeZone,omitempty"` // IANA zone "America/Chicago", see VenueTimeZone
 }
 
 //Address is the street address of the venue
@@ -139,35 +176,46 @@ type EntityID struct {
 
 //Event ...
 type Event struct {
-	EntityID               //EntityID.ID in form of "YYYY-MM-DD.AWY.HOM" "2017-02-03.TOR.BOS" where date is EST...
-	GameID     GameID      `json:"gameId"`
-	League     League      `json:"league"`
-	Season     Season      `json:"season"`
-	HomeTeam   *Competitor `json:"homeTeam"`
-	VisitTeam  *Competitor `json:"visitTeam"`
-	Venue      *Venue      `json:"location,omitempty"`
-	Status     *GameStatus `json:"status,omitempty"`
-	Links      *[]Link     `json:"link,omitempty"`
-	GameDetail *GameDetail `json:"gameDetail,omitempty"`
+	EntityID                   //EntityID.ID in form of "YYYY-MM-DD.AWY.HOM" "2017-02-03.TOR.BOS" where date is EST...
+	GameID     GameID          `json:"gameId"`
+	League     League          `json:"league"`
+	Season     Season          `json:"season"`
+	HomeTeam   *Competitor     `json:"homeTeam"`
+	VisitTeam  *Competitor     `json:"visitTeam"`
+	Venue      *Venue          `json:"location,omitempty"`
+	Status     *GameStatus     `json:"status,omitempty"`
+	Links      *[]Link         `json:"link,omitempty"`
+	GameDetail *GameDetail     `json:"gameDetail,omitempty"`
+	Lines      []*BettingLine  `json:"-"`                   // persisted in the lines child table, see ScoreBoard.BettingLines
+	Broadcasts []*Broadcast    `json:"-"`                   // persisted in the broadcasts child table, see ScoreBoard.Broadcasts
+	Officials  []*GameOfficial `json:"officials,omitempty"` // the crew, see AttachOfficials
+	Provider   Provider        `json:"-"`                   // the feed the event was marshalled from, "" for a merged event
 }
 
 //GameDetail .. extra detail about the game including things like startTime...
 type GameDetail struct {
 	StartTime           *time.Time  `json:"startTimeUTC,omitempty"`     //"startTimeUTC":"2019-10-01T00:00:00.000Z",
-	StartDateEastern    string      `json:"startDateEastern,omitempty"` //"startDateEastern":"20190930",
-	StartTimeEastern    string      `json:"startTimeEastern,omitempty"`
-	Period              *GamePeriod `json:"period,omitempty"`     // "period": {}
-	Attendance          int         `json:"attendance,omitempty"` //"attendance":"18624",
+	StartDateEastern    string      `json:"startDateEastern,omitempty"` //"startDateEastern":"2019-09-30", GameDayFormat
+	StartTimeEastern    string      `json:"startTimeEastern,omitempty"` //"startTimeEastern":"20:00:00", see SetStartTime
+	TimeZone            string      `json:"timeZone,omitempty"`         // the venue's IANA zone "America/Chicago"
+	StartTimeLocal      string      `json:"startTimeLocal,omitempty"`   // tip-off at the venue "2019-10-24T19:00:00-05:00"
+	Period              *GamePeriod `json:"period,omitempty"`           // "period": {}
+	Attendance          int         `json:"attendance,omitempty"`       //"attendance":"18624",
 	GameDurationMinutes int         `json:"gameDuration,omitempty"`
+	NeutralSite         bool        `json:"neutralSite,omitempty"`    // played at neither team's home e.g. college tournaments
+	ConferenceGame      bool        `json:"conferenceGame,omitempty"` // both teams from the same conference
 }
 
 //GamePeriod provides a structure that holds information about the period/quarter/half... that can be used to show game progession
 type GamePeriod struct {
-	Current       int  `json:"current"`       //"current":4,
-	Type          int  `json:"type"`          //"type":0,
-	MaxRegular    int  `json:"maxRegular"`    //"maxRegular":4
-	IsHalftime    bool `json:"isHalftime"`    //`"isHalftime":false,
-	IsEndOfPeriod bool `json:"isEndOfPeriod"` //"isEndOfPeriod":false
+	Current       int        `json:"current"`           //"current":4,
+	Type          int        `json:"type"`              //"type":0,
+	MaxRegular    int        `json:"maxRegular"`        //"maxRegular":4
+	IsHalftime    bool       `json:"isHalftime"`        //`"isHalftime":false,
+	IsEndOfPeriod bool       `json:"isEndOfPeriod"`     //"isEndOfPeriod":false
+	Unit          PeriodUnit `json:"unit,omitempty"`    // "quarter", "half", "period", "inning"
+	Label         string     `json:"label,omitempty"`   // label of the current period e.g. "Q3", "2OT", "ET1"
+	IsExtra       bool       `json:"isExtra,omitempty"` // the current period is past regulation
 }
 
 //ScoreBoard ... holding structure for a set of BoxScores
@@ -175,41 +223,64 @@ type ScoreBoard struct {
 	Events []Event `json:"events"`
 }
 
+//keyEntity keys the competitor and its team in the event v, see the KindCompetitor and KindTeam schemes
 func (c *Competitor) keyEntity(v interface{}) error {
 	log.Printf("Mastering %#v with %#v", c, v)
-	switch v.(type) {
-	case *Event: //"2020-01-02:WAS:DEN" where Visit:Home is arrangement
-		a, _ := v.(*Event)
-		if a == nil {
-			return errors.New("nul pointer in Event, keyInvalid")
-		}
-		key := (*c).Abbreviation + ":" + (*a).EntityID.ID
-		c.EntityID.ID = key
-		if c.Team == nil {
-			return errors.New("nul pointer in Team, keyInvalid")
-		}
-		err := c.Team.keyEntity(*a)
-		if err != nil {
-			//TODO: copy up GameRoster
-		}
+	a, ok := v.(*Event) //"TOR:2020-01-02:WAS:TOR"
+	if !ok || a == nil {
+		return errors.New("competitor needs its Event to be keyed")
+	}
+	key, err := DefaultKeyer().Key(KindCompetitor, c, a)
+	if err != nil && err != ErrKeyCollision {
 		return err
 	}
-	return errors.New("unknown event")
+	c.EntityID.ID = key
+	if c.Team == nil {
+		return errors.New("nul pointer in Team, keyInvalid")
+	}
+	// key a copy, the team may be the canonical team of a TeamRegistry
+	tm := *c.Team
+	c.Team = &tm
+	return c.Team.keyEntity(a)
 }
 
+//keyEntity keys the team for the season of the event v, "WAS:2019:2"
 func (t *Team) keyEntity(v interface{}) error {
-	log.Printf("Mastering %#v with %#v", t, v)
-	switch v.(type) {
-	case *Event: //"2020-01-02:WAS:DEN" where Visit:Home is arrangement
-		a, _ := v.(*Event)
-		if a == nil {
-			return errors.New("nul pointer in Event, keyInvalid")
-		}
-		key := t.Abbreviation + ":" + string((*a).Season.SeasonYear) + ":" + string((*a).Season.SeasonStage)
-		t.EntityID.ID = key // TeamID Abbrevioation + SeasonYear + SeasonStage = "WAS:2019:2"
+	a, ok := v.(*Event)
+	if !ok || a == nil {
+		return errors.New("team needs an Event to be keyed for its season")
+	}
+	key, err := DefaultKeyer().Key(KindTeam, t, a)
+	if err != nil {
+		return err
 	}
+	t.EntityID.ID = key // TeamID Abbrevioation + SeasonYear + SeasonStage = "WAS:2019:2"
 	return nil
+}
 
+//CanonicalKey is the key of the game whichever feed it came from "2019-12-25:BOS:TOR", the US eastern game day
+//then the canonical visit and home abbreviations.  NBA v2 competitors come without an abbreviation and ESPN's
+//differ e.g. "GS", so teams are resolved through the league's TeamRegistry
+func (e *Event) CanonicalKey() (string, error) {
+	if e.HomeTeam == nil || e.VisitTeam == nil {
+		return "", fmt.Errorf("game %s needs a home and visit team to be keyed", e.GameID)
+	}
+	day, err := e.GameDay()
+	if err != nil {
+		return "", err
+	}
+	registry := TeamRegistryFor(e.League)
+	abbreviation := func(c *Competitor) string {
+		t := c.Team
+		if t == nil {
+			t = &Team{Abbreviation: c.Abbreviation}
+		}
+		if canon := registry.Resolve(t); canon != nil {
+			return canon.ID
+		}
+		return c.Abbreviation
+	}
+	return GameKey(day, abbreviation(e.VisitTeam), abbreviation(e.HomeTeam)), nil
 }
 
 // MasterIdentity will provide a basic "soure->target" mapping of different data sets against a
@@ -220,21 +291,32 @@ func MasterIdentity(v interface{}) (string, error) {
 	switch v.(type) {
 	case *Event: //"2020-01-02:WAS:DEN" where Visit:Home is arrangement
 		a, _ := v.(*Event)
-
-		key := (*a).GameDetail.StartDateEastern
-		key = key + ":" + (*a).VisitTeam.Abbreviation + ":" + (*a).HomeTeam.Abbreviation
+		key, err := DefaultKeyer().Key(KindGame, a, nil)
+		if err != nil && err != ErrKeyCollision {
+			return "", err
+		}
 		(*a).EntityID.ID = key
-		_ = (*a).HomeTeam.keyEntity(*a)
-		_ = (*a).VisitTeam.keyEntity(*a)
-		return key, nil
+		_ = (*a).HomeTeam.keyEntity(a)
+		_ = (*a).VisitTeam.keyEntity(a)
+		return key, err
 	case *Venue: //GeoCode from google maps
 		a, _ := v.(*Venue)
-		key, err := GetGeoCodeAddress(a)
+		key, err := DefaultKeyer().Key(KindVenue, a, nil)
+		if err != nil && err != ErrKeyCollision {
+			return "", err
+		}
+		(*a).EntityID.ID = key
+		return key, err
+	case *Player: //"nba:200768"
+		a, _ := v.(*Player)
+		key, err := DefaultKeyer().Key(KindPlayer, a, nil)
 		if err != nil {
 			return "", err
 		}
 		(*a).EntityID.ID = key
 		return key, nil
+	case *GameOfficial, *Official: //"scott-foster"
+		return DefaultKeyer().Key(KindOfficial, v, nil)
 	default:
 		return "", fmt.Errorf("Master for Entity %T not found", v)
 	}
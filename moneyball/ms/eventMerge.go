@@ -0,0 +1,468 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// the same game pulled from NBA and ESPN arrives as two partial Events, MergeEvents combines the events that
+// share a CanonicalKey (game day plus both teams) into one.  Each field is taken from the first provider in
+// its precedence list that has it set (attendance from NBA, odds from ESPN...), the provider that won each
+// field is kept in MergeResult.Sources, and disagreements between finished games (final score, linescore)
+// are reported as DataQualityIssues rather than silently picked over
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//SourceRegistry marks fields filled from our own registries rather than a provider feed
+const SourceRegistry Provider = "registry"
+
+//MergeSource ... an event as a provider reported it
+type MergeSource struct {
+	Provider Provider
+	Event    *Event
+}
+
+//MergeRules ... per field source precedence, fields are named as in DefaultMergeRules e.g. "attendance",
+//"home.score"; a field without rules goes to the first source that has it
+type MergeRules struct {
+	Precedence map[string][]Provider
+	Geo        func(v *Venue) (string, error) // optional venue geocode lookup, e.g. Venue.MasterEntity
+}
+
+//DefaultMergeRules NBA is the system of record for the box score (scores, attendance, duration) and ESPN for
+//what only it carries (odds, broadcasts, records, leaders, venue names)
+func DefaultMergeRules() MergeRules {
+	nba := []Provider{ProviderNBA, ProviderESPN}
+	espn := []Provider{ProviderESPN, ProviderNBA}
+	rules := MergeRules{Precedence: map[string][]Provider{
+		"gameId":         nba,
+		"season":         nba,
+		"startTime":      nba,
+		"attendance":     nba,
+		"gameDuration":   nba,
+		"period":         espn,
+		"neutralSite":    espn,
+		"conferenceGame": espn,
+		"venue":          espn,
+		"status":         espn,
+		"links":          espn,
+		"lines":          espn,
+		"broadcasts":     espn,
+		"officials":      nba,
+	}}
+	for _, side := range []string{"home", "visit"} {
+		rules.Precedence[side+".team"] = nba
+		rules.Precedence[side+".identity"] = espn
+		rules.Precedence[side+".score"] = nba
+		rules.Precedence[side+".linescore"] = nba
+		rules.Precedence[side+".record"] = espn
+		rules.Precedence[side+".rank"] = espn
+		rules.Precedence[side+".leaders"] = espn
+		rules.Precedence[side+".statistics"] = espn
+		rules.Precedence[side+".gameStats"] = nba
+		rules.Precedence[side+".roster"] = nba
+	}
+	return rules
+}
+
+//DataQualityIssue ... a disagreement between providers about a game
+type DataQualityIssue struct {
+	League     League              `json:"league"`
+	GameKey    string              `json:"gameKey"` // "2019-12-25:BOS:TOR"
+	Field      string              `json:"field"`   // "home.score"
+	Kind       string              `json:"kind"`    // "scoreMismatch", "linescoreMismatch"
+	Values     map[Provider]string `json:"values"`  // what each provider has
+	Detail     string              `json:"detail,omitempty"`
+	DetectedAt time.Time           `json:"detectedAt"`
+}
+
+// kinds of DataQualityIssue
+const (
+	//IssueScoreMismatch the final scores differ
+	IssueScoreMismatch = "scoreMismatch"
+	//IssueLinescoreMismatch the period scores differ
+	IssueLinescoreMismatch = "linescoreMismatch"
+)
+
+//DataQualityTable ... the issues of a league, persisted as their own table
+type DataQualityTable struct {
+	League League
+	Issues []*DataQualityIssue
+}
+
+//MergeResult ... the merged event, the provider each field came from and the issues found
+type MergeResult struct {
+	Event   *Event
+	Sources map[string]Provider
+	Issues  []*DataQualityIssue
+}
+
+type merger struct {
+	rules   MergeRules
+	sources []MergeSource
+	result  *MergeResult
+}
+
+//pick returns the event of the first provider in the field's precedence that has it set, then of any source
+//that has it set, and records the winner
+func (m *merger) pick(field string, set func(e *Event) bool) *Event {
+	for _, p := range m.rules.Precedence[field] {
+		for _, s := range m.sources {
+			if s.Provider == p && set(s.Event) {
+				m.result.Sources[field] = p
+				return s.Event
+			}
+		}
+	}
+	for _, s := range m.sources {
+		if set(s.Event) {
+			m.result.Sources[field] = s.Provider
+			return s.Event
+		}
+	}
+	return nil
+}
+
+//pickCompetitor is pick for a field of the home or visit competitor
+func (m *merger) pickCompetitor(side string, field string, set func(c *Competitor) bool) *Competitor {
+	e := m.pick(side+"."+field, func(e *Event) bool {
+		c := competitorOf(e, side)
+		return c != nil && set(c)
+	})
+	if e == nil {
+		return nil
+	}
+	return competitorOf(e, side)
+}
+
+func competitorOf(e *Event, side string) *Competitor {
+	if side == "home" {
+		return e.HomeTeam
+	}
+	return e.VisitTeam
+}
+
+func hasDetail(e *Event, set func(gd *GameDetail) bool) bool {
+	return e.GameDetail != nil && set(e.GameDetail)
+}
+
+//MergeEvents merges events of the same game from different providers, all must have the same CanonicalKey
+func MergeEvents(rules MergeRules, sources ...MergeSource) (*MergeResult, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no events to merge")
+	}
+	key, err := sources[0].Event.CanonicalKey()
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range sources[1:] {
+		k, err := s.Event.CanonicalKey()
+		if err != nil {
+			return nil, err
+		}
+		if k != key {
+			return nil, fmt.Errorf("%s event %s is game %s not %s", s.Provider, s.Event.GameID, k, key)
+		}
+		for _, other := range sources[:i+1] {
+			if other.Provider == s.Provider {
+				return nil, fmt.Errorf("%s events %s and %s are two games %s", s.Provider, other.Event.GameID, s.Event.GameID, key)
+			}
+		}
+	}
+	m := merger{rules: rules, sources: sources, result: &MergeResult{Sources: map[string]Provider{}}}
+	ev := Event{League: sources[0].Event.League}
+	ev.EntityID.ID = key
+	if e := m.pick("gameId", func(e *Event) bool { return e.GameID != "" }); e != nil {
+		ev.GameID = e.GameID
+	}
+	if e := m.pick("season", func(e *Event) bool { return e.Season.SeasonYear != 0 }); e != nil {
+		ev.Season = e.Season
+	}
+	if e := m.pick("venue", func(e *Event) bool { return e.Venue != nil && e.Venue.FullName != "" }); e != nil {
+		v := *e.Venue
+		ev.Venue = &v
+	}
+	if ev.Venue != nil && rules.Geo != nil {
+		if geo, err := rules.Geo(ev.Venue); err == nil && geo != "" {
+			addr := Address{}
+			if ev.Venue.Address != nil {
+				addr = *ev.Venue.Address
+			}
+			addr.GeoLoc = geo
+			ev.Venue.Address = &addr
+			m.result.Sources["venue.geo"] = SourceRegistry
+		}
+	}
+	if e := m.pick("status", func(e *Event) bool { return e.Status != nil }); e != nil {
+		st := *e.Status
+		ev.Status = &st
+	}
+	if e := m.pick("links", func(e *Event) bool { return e.Links != nil && len(*e.Links) > 0 }); e != nil {
+		ev.Links = e.Links
+	}
+	if e := m.pick("lines", func(e *Event) bool { return len(e.Lines) > 0 }); e != nil {
+		for _, l := range e.Lines {
+			line := *l
+			ev.Lines = append(ev.Lines, &line)
+		}
+	}
+	if e := m.pick("broadcasts", func(e *Event) bool { return len(e.Broadcasts) > 0 }); e != nil {
+		for _, b := range e.Broadcasts {
+			broadcast := *b
+			ev.Broadcasts = append(ev.Broadcasts, &broadcast)
+		}
+	}
+	if e := m.pick("officials", func(e *Event) bool { return len(e.Officials) > 0 }); e != nil {
+		ev.Officials = e.Officials
+	}
+	ev.GameDetail = m.mergeDetail()
+	ev.HomeTeam = m.mergeCompetitor("home")
+	ev.VisitTeam = m.mergeCompetitor("visit")
+	// the copied child table rows are keyed on the game id of the merged event
+	for _, l := range ev.Lines {
+		l.GameID = ev.GameID
+	}
+	for _, b := range ev.Broadcasts {
+		b.GameID = ev.GameID
+	}
+	m.result.Event = &ev
+	m.checkScores(key)
+	return m.result, nil
+}
+
+func (m *merger) mergeDetail() *GameDetail {
+	gd := GameDetail{}
+	if e := m.pick("startTime", func(e *Event) bool {
+		return hasDetail(e, func(gd *GameDetail) bool { return gd.StartTime != nil || gd.StartDateEastern != "" })
+	}); e != nil {
+		gd.StartTime = e.GameDetail.StartTime
+		gd.StartDateEastern = e.GameDetail.StartDateEastern
+		gd.StartTimeEastern = e.GameDetail.StartTimeEastern
+		gd.TimeZone = e.GameDetail.TimeZone
+		gd.StartTimeLocal = e.GameDetail.StartTimeLocal
+	}
+	if e := m.pick("attendance", func(e *Event) bool {
+		return hasDetail(e, func(gd *GameDetail) bool { return gd.Attendance > 0 })
+	}); e != nil {
+		gd.Attendance = e.GameDetail.Attendance
+	}
+	if e := m.pick("gameDuration", func(e *Event) bool {
+		return hasDetail(e, func(gd *GameDetail) bool { return gd.GameDurationMinutes > 0 })
+	}); e != nil {
+		gd.GameDurationMinutes = e.GameDetail.GameDurationMinutes
+	}
+	if e := m.pick("period", func(e *Event) bool {
+		return hasDetail(e, func(gd *GameDetail) bool { return gd.Period != nil })
+	}); e != nil {
+		p := *e.GameDetail.Period
+		gd.Period = &p
+	}
+	if e := m.pick("neutralSite", func(e *Event) bool {
+		return hasDetail(e, func(gd *GameDetail) bool { return gd.NeutralSite })
+	}); e != nil {
+		gd.NeutralSite = true
+	}
+	if e := m.pick("conferenceGame", func(e *Event) bool {
+		return hasDetail(e, func(gd *GameDetail) bool { return gd.ConferenceGame })
+	}); e != nil {
+		gd.ConferenceGame = true
+	}
+	return &gd
+}
+
+func (m *merger) mergeCompetitor(side string) *Competitor {
+	c := Competitor{}
+	if src := m.pickCompetitor(side, "identity", func(c *Competitor) bool { return c.Abbreviation != "" }); src != nil {
+		c.EntityID = src.EntityID
+		c.Name = src.Name
+		c.Abbreviation = src.Abbreviation
+		c.Location = src.Location
+		c.Color = src.Color
+		c.AlternateColor = src.AlternateColor
+		c.IsActive = src.IsActive
+		c.IsAllStar = src.IsAllStar
+		c.Links = src.Links
+	}
+	if src := m.pickCompetitor(side, "team", func(c *Competitor) bool { return c.Team != nil }); src != nil {
+		c.Team = src.Team
+	}
+	if e := m.pick(side+".score", func(e *Event) bool { return scored(e, competitorOf(e, side)) }); e != nil {
+		c.Score = competitorOf(e, side).Score
+	}
+	if src := m.pickCompetitor(side, "linescore", func(c *Competitor) bool {
+		return c.LineScore != nil && len(*c.LineScore) > 0
+	}); src != nil {
+		c.LineScore = src.LineScore
+	}
+	if src := m.pickCompetitor(side, "record", func(c *Competitor) bool {
+		return c.Record.Win+c.Record.Loss > 0 || len(c.Record.Items) > 0
+	}); src != nil {
+		c.Record = src.Record
+	}
+	if src := m.pickCompetitor(side, "rank", func(c *Competitor) bool { return c.Rank > 0 }); src != nil {
+		c.Rank = src.Rank
+	}
+	if src := m.pickCompetitor(side, "leaders", func(c *Competitor) bool { return len(c.Leaders) > 0 }); src != nil {
+		c.Leaders = src.Leaders
+	}
+	if src := m.pickCompetitor(side, "statistics", func(c *Competitor) bool { return len(c.Statistics) > 0 }); src != nil {
+		c.Statistics = src.Statistics
+	}
+	if src := m.pickCompetitor(side, "gameStats", func(c *Competitor) bool { return c.GameStats != nil }); src != nil {
+		c.GameStats = src.GameStats
+	}
+	if src := m.pickCompetitor(side, "roster", func(c *Competitor) bool { return c.Roster != nil }); src != nil {
+		c.Roster = src.Roster
+	}
+	return &c
+}
+
+//scored tells a score of the competitor, 0 included, from one not reported yet: the game is under way or over,
+//events without a status count any points
+func scored(e *Event, c *Competitor) bool {
+	if c == nil {
+		return false
+	}
+	if e.Status != nil && e.Status.State != "" {
+		return e.Status.State != "pre"
+	}
+	return c.Score > 0
+}
+
+//checkScores flags score and linescore disagreements, only once no source has the game in progress as feeds
+//update at different times
+func (m *merger) checkScores(key string) {
+	for _, s := range m.sources {
+		if s.Event.Status != nil && s.Event.Status.State == "in" {
+			return
+		}
+	}
+	for _, side := range []string{"home", "visit"} {
+		scores := map[Provider]string{}
+		linescores := map[Provider]string{}
+		for _, s := range m.sources {
+			c := competitorOf(s.Event, side)
+			if c == nil {
+				continue
+			}
+			if scored(s.Event, c) {
+				scores[s.Provider] = fmt.Sprintf("%d", c.Score)
+			}
+			if c.LineScore != nil && len(*c.LineScore) > 0 {
+				periods := []string{}
+				for _, sc := range *c.LineScore {
+					periods = append(periods, fmt.Sprintf("%g", sc.Score))
+				}
+				linescores[s.Provider] = strings.Join(periods, ",")
+			}
+		}
+		m.flag(key, side+".score", IssueScoreMismatch, scores)
+		m.flag(key, side+".linescore", IssueLinescoreMismatch, linescores)
+	}
+}
+
+func (m *merger) flag(key string, field string, kind string, values map[Provider]string) {
+	distinct := map[string]bool{}
+	for _, v := range values {
+		distinct[v] = true
+	}
+	if len(distinct) < 2 {
+		return
+	}
+	providers := []string{}
+	for p, v := range values {
+		providers = append(providers, string(p)+"="+v)
+	}
+	sort.Strings(providers)
+	m.result.Issues = append(m.result.Issues, &DataQualityIssue{League: m.result.Event.League, GameKey: key,
+		Field: field, Kind: kind, Values: values, Detail: strings.Join(providers, " "), DetectedAt: time.Now().UTC()})
+}
+
+//MergeScoreBoards matches the events of each provider's scoreboard on their CanonicalKey and merges them, the
+//results are sorted by key.  Two events of one provider with a key (a doubleheader, a postponed game played the
+//same day) are two games, matched in feed order with the other provider's e.g. "2019-12-25:BOS:TOR:2".  Events
+//that can't be keyed are returned as errors and left out
+func MergeScoreBoards(rules MergeRules, boards map[Provider]*ScoreBoard) ([]*MergeResult, []error) {
+	providers := []string{}
+	for p := range boards {
+		providers = append(providers, string(p))
+	}
+	sort.Strings(providers)
+	games := map[string][]MergeSource{}
+	errs := []error{}
+	for _, p := range providers {
+		sb := boards[Provider(p)]
+		if sb == nil {
+			continue
+		}
+		seen := map[string]int{}
+		for i := range sb.Events {
+			e := &sb.Events[i]
+			key, err := e.CanonicalKey()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s event %s: %s", p, e.GameID, err))
+				continue
+			}
+			seen[key]++
+			if seen[key] > 1 {
+				key = key + ":" + strconv.Itoa(seen[key])
+			}
+			games[key] = append(games[key], MergeSource{Provider: Provider(p), Event: e})
+		}
+	}
+	keys := []string{}
+	for key := range games {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	results := []*MergeResult{}
+	for _, key := range keys {
+		r, err := MergeEvents(rules, games[key]...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, r)
+	}
+	return results, errs
+}
+
+//DataQuality collects the issues of merge results into a table for league
+func DataQuality(league League, results []*MergeResult) *DataQualityTable {
+	t := DataQualityTable{League: league, Issues: []*DataQualityIssue{}}
+	for _, r := range results {
+		t.Issues = append(t.Issues, r.Issues...)
+	}
+	return &t
+}
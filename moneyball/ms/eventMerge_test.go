@@ -0,0 +1,186 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mergeFixtures() (*Event, *Event) {
+	start := time.Date(2019, time.December, 26, 0, 30, 0, 0, time.UTC)
+	nba := &Event{GameID: "0021900478", League: LeagueNBA, Season: Season{2020, 2},
+		VisitTeam: &Competitor{Team: &Team{TeamIDNBA: "1610612738"}, Score: 112,
+			LineScore: &[]Score{{Score: 30}, {Score: 28}, {Score: 26}, {Score: 28}}},
+		HomeTeam: &Competitor{Team: &Team{TeamIDNBA: "1610612761"}, Score: 118,
+			LineScore: &[]Score{{Score: 31}, {Score: 29}, {Score: 30}, {Score: 28}}},
+		GameDetail: &GameDetail{StartTime: &start, Attendance: 19800, GameDurationMinutes: 134},
+		Venue:      &Venue{FullName: "Scotiabank Arena"}}
+	espn := &Event{GameID: "401161427", League: LeagueNBA, Season: Season{2020, 2},
+		VisitTeam: &Competitor{Abbreviation: "BOS", Name: "Celtics", Team: &Team{TeamIDESPN: "2"}, Score: 112,
+			Record: Record{Win: 22, Loss: 7}},
+		HomeTeam: &Competitor{Abbreviation: "TOR", Name: "Raptors", Team: &Team{TeamIDESPN: "28"}, Score: 118,
+			Record: Record{Win: 21, Loss: 9}},
+		GameDetail: &GameDetail{StartDateEastern: "2019-12-25", Attendance: 19000,
+			Period: &GamePeriod{Current: 4, MaxRegular: 4}},
+		Venue:      &Venue{FullName: "Scotiabank Arena", Capacity: 19800, Address: &Address{City: "Toronto"}},
+		Status:     &GameStatus{State: "post", Detail: "Final"},
+		Lines:      []*BettingLine{{GameID: "401161427", Provider: "Caesars", Details: "TOR -1.5"}},
+		Broadcasts: []*Broadcast{{GameID: "401161427", Network: "TNT"}}}
+	return nba, espn
+}
+
+func TestMergeEvents(t *testing.T) {
+	nba, espn := mergeFixtures()
+	rules := DefaultMergeRules()
+	rules.Geo = func(v *Venue) (string, error) { return "87M2MJ76+8X", nil }
+	r, err := MergeEvents(rules, MergeSource{ProviderESPN, espn}, MergeSource{ProviderNBA, nba})
+	assert.Nil(t, err)
+	ev := r.Event
+	assert.Equal(t, "2019-12-25:BOS:TOR", ev.EntityID.ID)
+	assert.Equal(t, GameID("0021900478"), ev.GameID)
+	assert.Equal(t, 19800, ev.GameDetail.Attendance)
+	assert.Equal(t, ProviderNBA, r.Sources["attendance"])
+	assert.Equal(t, 134, ev.GameDetail.GameDurationMinutes)
+	assert.Equal(t, 4, ev.GameDetail.Period.Current)
+	assert.Equal(t, ProviderESPN, r.Sources["period"])
+	if assert.Len(t, ev.Lines, 1) {
+		assert.Equal(t, GameID("0021900478"), ev.Lines[0].GameID)
+		assert.Equal(t, GameID("401161427"), espn.Lines[0].GameID, "the source is left as is")
+	}
+	assert.Equal(t, ProviderESPN, r.Sources["lines"])
+	assert.Equal(t, "Toronto", ev.Venue.Address.City)
+	assert.Equal(t, "87M2MJ76+8X", ev.Venue.Address.GeoLoc)
+	assert.Equal(t, SourceRegistry, r.Sources["venue.geo"])
+
+	assert.Equal(t, "TOR", ev.HomeTeam.Abbreviation)
+	assert.Equal(t, 118, ev.HomeTeam.Score)
+	assert.Equal(t, ProviderNBA, r.Sources["home.score"])
+	assert.Len(t, *ev.HomeTeam.LineScore, 4)
+	assert.Equal(t, 21, ev.HomeTeam.Record.Win)
+	assert.Equal(t, ProviderESPN, r.Sources["home.record"])
+	assert.Len(t, r.Issues, 0)
+}
+
+func TestMergeEventsIssues(t *testing.T) {
+	nba, espn := mergeFixtures()
+	espn.HomeTeam.Score = 116
+	espn.HomeTeam.LineScore = &[]Score{{Score: 31}, {Score: 29}, {Score: 28}, {Score: 28}}
+	r, err := MergeEvents(DefaultMergeRules(), MergeSource{ProviderNBA, nba}, MergeSource{ProviderESPN, espn})
+	assert.Nil(t, err)
+	if assert.Len(t, r.Issues, 2) {
+		assert.Equal(t, IssueScoreMismatch, r.Issues[0].Kind)
+		assert.Equal(t, "home.score", r.Issues[0].Field)
+		assert.Equal(t, "espn=116 nba=118", r.Issues[0].Detail)
+		assert.Equal(t, IssueLinescoreMismatch, r.Issues[1].Kind)
+	}
+	assert.Equal(t, 118, r.Event.HomeTeam.Score, "NBA wins the score")
+
+	// no issues while a feed has the game in progress
+	espn.Status.State = "in"
+	r, _ = MergeEvents(DefaultMergeRules(), MergeSource{ProviderNBA, nba}, MergeSource{ProviderESPN, espn})
+	assert.Len(t, r.Issues, 0)
+
+	// a different game doesn't merge
+	other, _ := mergeFixtures()
+	other.HomeTeam.Team = &Team{TeamIDNBA: "1610612744"}
+	_, err = MergeEvents(DefaultMergeRules(), MergeSource{ProviderNBA, other}, MergeSource{ProviderESPN, espn})
+	assert.NotNil(t, err)
+}
+
+func TestMergeScoreBoards(t *testing.T) {
+	nba, espn := mergeFixtures()
+	espn.HomeTeam.Score = 116
+	only := &Event{GameID: "401161428", League: LeagueNBA, VisitTeam: &Competitor{Abbreviation: "LAL"},
+		HomeTeam: &Competitor{Abbreviation: "LAC"}, GameDetail: &GameDetail{StartDateEastern: "2019-12-25"}}
+	results, errs := MergeScoreBoards(DefaultMergeRules(), map[Provider]*ScoreBoard{
+		ProviderNBA:  {Events: []Event{*nba}},
+		ProviderESPN: {Events: []Event{*espn, *only, {GameID: "broken"}}},
+	})
+	assert.Len(t, errs, 1)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "2019-12-25:BOS:TOR", results[0].Event.EntityID.ID)
+		assert.Equal(t, "2019-12-25:LAL:LAC", results[1].Event.EntityID.ID)
+		assert.Equal(t, ProviderESPN, results[1].Sources["home.identity"])
+	}
+	dq := DataQuality(LeagueNBA, results)
+	assert.Len(t, dq.Issues, 1)
+	var b bytes.Buffer
+	assert.Nil(t, dq.marshalNBJSON(&b))
+	assert.Equal(t, "dataqualityNBA", dq.tableName())
+	assert.NotZero(t, b.Len())
+}
+
+func TestMergeScoreBoardsDoubleheader(t *testing.T) {
+	nba, espn := mergeFixtures()
+	nightcap, espnNightcap := mergeFixtures()
+	nightcap.GameID, espnNightcap.GameID = "0021900479", "401161428"
+	nightcap.HomeTeam.Score, espnNightcap.HomeTeam.Score = 99, 99
+	results, errs := MergeScoreBoards(DefaultMergeRules(), map[Provider]*ScoreBoard{
+		ProviderNBA:  {Events: []Event{*nba, *nightcap}},
+		ProviderESPN: {Events: []Event{*espn, *espnNightcap}},
+	})
+	assert.Len(t, errs, 0)
+	if assert.Len(t, results, 2, "two games, not one") {
+		assert.Equal(t, GameID("0021900478"), results[0].Event.GameID)
+		assert.Equal(t, 118, results[0].Event.HomeTeam.Score)
+		assert.Equal(t, GameID("0021900479"), results[1].Event.GameID)
+		assert.Equal(t, 99, results[1].Event.HomeTeam.Score)
+		assert.Len(t, results[1].Issues, 0)
+	}
+
+	_, err := MergeEvents(DefaultMergeRules(), MergeSource{ProviderNBA, nba}, MergeSource{ProviderNBA, nightcap})
+	assert.NotNil(t, err, "two events of one provider")
+}
+
+func TestMergeEventsZeroScore(t *testing.T) {
+	nba, espn := mergeFixtures()
+	nba.Status = &GameStatus{State: "post"}
+	nba.HomeTeam.Score, nba.HomeTeam.LineScore = 0, nil
+	espn.HomeTeam.Score = 1
+	r, err := MergeEvents(DefaultMergeRules(), MergeSource{ProviderNBA, nba}, MergeSource{ProviderESPN, espn})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, r.Event.HomeTeam.Score, "a final 0 is a score")
+	assert.Equal(t, ProviderNBA, r.Sources["home.score"])
+	if assert.Len(t, r.Issues, 1) {
+		assert.Equal(t, "espn=1 nba=0", r.Issues[0].Detail)
+	}
+
+	// a scheduled game has no score yet
+	nba.Status.State, espn.Status.State = "pre", "pre"
+	r, _ = MergeEvents(DefaultMergeRules(), MergeSource{ProviderNBA, nba}, MergeSource{ProviderESPN, espn})
+	_, picked := r.Sources["home.score"]
+	assert.False(t, picked)
+	assert.Len(t, r.Issues, 0)
+}
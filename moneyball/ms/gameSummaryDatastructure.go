@@ -0,0 +1,109 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// a GameSummary is the per game detail behind an Event: the box score lines of every player, the team
+// totals, the game leaders, the officials, the play-by-play and the home team's win probability after
+// each play.  Team and player ids are the provider's, as they are for PlayEvents
+
+//GameTeamStats ... team box score totals for a game, the team counterpart of GamePlayersStats
+type GameTeamStats struct {
+	TeamID   string  `json:"teamID"`             // the team
+	GameID   string  `json:"gameID"`             // during a game
+	HomeAway string  `json:"homeAway,omitempty"` // "home" or "away"
+	Stats    []*Stat `json:"gameTeamStat"`
+}
+
+//Stat returns the stat with key or long key k, or nil
+func (ts *GameTeamStats) Stat(k string) *Stat {
+	return findStat(ts.Stats, k)
+}
+
+//GameLeader ... a team's leader in a stat category for a game e.g. points
+type GameLeader struct {
+	TeamID   string `json:"teamID"`
+	Category string `json:"category"`       // "points"
+	PlayerID string `json:"playerId"`       // "3012"
+	Name     string `json:"name,omitempty"` // "Kyle Lowry"
+	Value    string `json:"value"`          // "36"
+}
+
+//GameOfficial ... a referee working a game, the crew assignment of an Official
+type GameOfficial struct {
+	Name       string `json:"name"`                 // "Scott Foster"
+	Position   string `json:"position,omitempty"`   // role on the crew e.g. "Referee", "Crew Chief"
+	Order      int    `json:"order,omitempty"`      // 1 is the crew chief
+	Jersey     string `json:"jersey,omitempty"`     // "48"
+	IDNBA      string `json:"idNBA,omitempty"`      // NBA person_id "1146"
+	OfficialID string `json:"officialId,omitempty"` // canonical Official id, see OfficialRegistry
+}
+
+//WinProbability ... the home team's chance of winning after a play
+type WinProbability struct {
+	PlayID            string  `json:"playId"`                  // "4011615594"
+	Sequence          int     `json:"sequence,omitempty"`      // Sequence of the PlayEvent, 0 when the play is unknown
+	HomeWinPercentage float64 `json:"homeWinPercentage"`       // 0.552
+	TiePercentage     float64 `json:"tiePercentage,omitempty"` // 0.0
+}
+
+//GameSummary ...
+type GameSummary struct {
+	GameID         GameID              `json:"gameId"`
+	League         League              `json:"league"`
+	HomeTeamID     string              `json:"homeTeamId,omitempty"`
+	VisitTeamID    string              `json:"visitTeamId,omitempty"`
+	Players        []*GamePlayersStats `json:"players,omitempty"`
+	Teams          []*GameTeamStats    `json:"teams,omitempty"`
+	Leaders        []*GameLeader       `json:"leaders,omitempty"`
+	Officials      []*GameOfficial     `json:"officials,omitempty"`
+	Plays          []*PlayEvent        `json:"plays,omitempty"`
+	WinProbability []*WinProbability   `json:"winProbability,omitempty"`
+}
+
+//Player returns the box score line of a player, or nil if the player isn't in the box score
+func (gs *GameSummary) Player(playerID string) *GamePlayersStats {
+	for _, p := range gs.Players {
+		if p.PlayerID == playerID {
+			return p
+		}
+	}
+	return nil
+}
+
+//Team returns the team totals of a team, or nil
+func (gs *GameSummary) Team(teamID string) *GameTeamStats {
+	for _, t := range gs.Teams {
+		if t.TeamID == teamID {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// game times are kept as the UTC start, the US eastern game day and clock the leagues schedule by, and the
+// venue's IANA time zone with the local tip-off.  The eastern day is the GameDay used for game keys, calendars
+// and rest days, always formatted as GameDayFormat whatever the feed's format ("20190930" for NBA)
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // venue zones must load on hosts without a zoneinfo database
+)
+
+const (
+	//GameDayFormat is the one format of StartDateEastern and the day in game keys
+	GameDayFormat = "2006-01-02"
+	//EasternTimeFormat is the format of StartTimeEastern
+	EasternTimeFormat = "15:04:05"
+	//EasternTimeZone is the zone game days are taken in
+	EasternTimeZone = "America/New_York"
+)
+
+var (
+	eastern     *time.Location
+	easternOnce sync.Once
+)
+
+//Eastern returns the US eastern location, a fixed EST zone if it can't be loaded
+func Eastern() *time.Location {
+	easternOnce.Do(func() {
+		location, err := time.LoadLocation(EasternTimeZone)
+		if err != nil {
+			location = time.FixedZone("EST", -5*60*60)
+		}
+		eastern = location
+	})
+	return eastern
+}
+
+//GameDayOf is the US eastern day of an instant as midnight UTC
+func GameDayOf(t time.Time) time.Time {
+	est := t.In(Eastern())
+	return time.Date(est.Year(), est.Month(), est.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+//ParseGameDay reads a day as "2006-01-02" or the NBA's "20060102"
+func ParseGameDay(s string) (time.Time, error) {
+	for _, layout := range []string{GameDayFormat, "20060102"} {
+		if day, err := time.Parse(layout, s); err == nil {
+			return day, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid game day %q", s)
+}
+
+//FormatGameDay ... "2019-10-22"
+func FormatGameDay(day time.Time) string {
+	return day.Format(GameDayFormat)
+}
+
+//RestDays is the number of days off between two game days (see Event.GameDay), 0 for a back-to-back
+func RestDays(previous time.Time, next time.Time) int {
+	days := int(next.Sub(previous).Round(24*time.Hour)/(24*time.Hour)) - 1
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+//TeamRestDays returns the days of rest before each of a team's games keyed by game id, the team's first game
+//and games without a day are left out
+func TeamRestDays(abbreviation string, events []*Event) map[GameID]int {
+	days := []time.Time{}
+	games := map[time.Time]GameID{}
+	for _, e := range events {
+		if (e.HomeTeam == nil || e.HomeTeam.Abbreviation != abbreviation) &&
+			(e.VisitTeam == nil || e.VisitTeam.Abbreviation != abbreviation) {
+			continue
+		}
+		day, err := e.GameDay()
+		if err != nil {
+			continue
+		}
+		if _, ok := games[day]; !ok {
+			days = append(days, day)
+		}
+		games[day] = e.GameID
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	rest := map[GameID]int{}
+	for i := 1; i < len(days); i++ {
+		rest[games[days[i]]] = RestDays(days[i-1], days[i])
+	}
+	return rest
+}
+
+//SetStartTime sets the UTC start, the eastern day and clock and, when the venue's zone is known, the local start
+func (gd *GameDetail) SetStartTime(start time.Time, timeZone string) error {
+	utc := start.UTC()
+	gd.StartTime = &utc
+	est := start.In(Eastern())
+	gd.StartDateEastern = est.Format(GameDayFormat)
+	gd.StartTimeEastern = est.Format(EasternTimeFormat)
+	if timeZone == "" {
+		return nil
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return fmt.Errorf("time zone %s of game starting %s: %w", timeZone, utc.Format(time.RFC3339), err)
+	}
+	gd.TimeZone = timeZone
+	gd.StartTimeLocal = start.In(location).Format(time.RFC3339)
+	return nil
+}
+
+//LocalStartTime is the start in the venue's zone, or eastern when the zone isn't known
+func (gd *GameDetail) LocalStartTime() (time.Time, error) {
+	if gd.StartTime == nil {
+		return time.Time{}, fmt.Errorf("game has no start time")
+	}
+	location := Eastern()
+	if gd.TimeZone != "" {
+		l, err := time.LoadLocation(gd.TimeZone)
+		if err != nil {
+			return time.Time{}, err
+		}
+		location = l
+	}
+	return gd.StartTime.In(location), nil
+}
+
+// zones of the cities in states or provinces that span zones, or outside the US and Canada
+var cityTimeZones = map[string]string{
+	"el paso":     "America/Denver",
+	"knoxville":   "America/New_York",
+	"chattanooga": "America/New_York",
+	"pensacola":   "America/Chicago",
+	"mexico city": "America/Mexico_City",
+	"london":      "Europe/London",
+	"paris":       "Europe/Paris",
+}
+
+// zones of US states and Canadian provinces by postal abbreviation, the zone of most of the state
+var stateTimeZones = map[string]string{
+	"AL": "America/Chicago", "AK": "America/Anchorage", "AZ": "America/Phoenix", "AR": "America/Chicago",
+	"CA": "America/Los_Angeles", "CO": "America/Denver", "CT": "America/New_York", "DE": "America/New_York",
+	"DC": "America/New_York", "FL": "America/New_York", "GA": "America/New_York", "HI": "Pacific/Honolulu",
+	"ID": "America/Boise", "IL": "America/Chicago", "IN": "America/Indiana/Indianapolis", "IA": "America/Chicago",
+	"KS": "America/Chicago", "KY": "America/New_York", "LA": "America/Chicago", "ME": "America/New_York",
+	"MD": "America/New_York", "MA": "America/New_York", "MI": "America/Detroit", "MN": "America/Chicago",
+	"MS": "America/Chicago", "MO": "America/Chicago", "MT": "America/Denver", "NE": "America/Chicago",
+	"NV": "America/Los_Angeles", "NH": "America/New_York", "NJ": "America/New_York", "NM": "America/Denver",
+	"NY": "America/New_York", "NC": "America/New_York", "ND": "America/Chicago", "OH": "America/New_York",
+	"OK": "America/Chicago", "OR": "America/Los_Angeles", "PA": "America/New_York", "RI": "America/New_York",
+	"SC": "America/New_York", "SD": "America/Chicago", "TN": "America/Chicago", "TX": "America/Chicago",
+	"UT": "America/Denver", "VT": "America/New_York", "VA": "America/New_York", "WA": "America/Los_Angeles",
+	"WV": "America/New_York", "WI": "America/Chicago", "WY": "America/Denver", "PR": "America/Puerto_Rico",
+	"ON": "America/Toronto", "QC": "America/Toronto", "BC": "America/Vancouver", "AB": "America/Edmonton",
+	"MB": "America/Winnipeg",
+}
+
+//VenueTimeZone returns the IANA zone of a venue from its city or state, empty when it isn't known
+func VenueTimeZone(v *Venue) string {
+	if v == nil {
+		return ""
+	}
+	if v.TimeZone != "" {
+		return v.TimeZone
+	}
+	if v.Address == nil {
+		return ""
+	}
+	if zone, ok := cityTimeZones[strings.ToLower(strings.TrimSpace(v.Address.City))]; ok {
+		return zone
+	}
+	return stateTimeZones[strings.ToUpper(strings.TrimSpace(v.Address.State))]
+}
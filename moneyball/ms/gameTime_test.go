@@ -0,0 +1,110 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetStartTime(t *testing.T) {
+	// a 10:30 PM eastern tip in Los Angeles
+	start := time.Date(2019, 10, 23, 2, 30, 0, 0, time.UTC)
+	gd := GameDetail{}
+	assert.Nil(t, gd.SetStartTime(start, "America/Los_Angeles"))
+	assert.Equal(t, start, *gd.StartTime)
+	assert.Equal(t, "2019-10-22", gd.StartDateEastern)
+	assert.Equal(t, "22:30:00", gd.StartTimeEastern)
+	assert.Equal(t, "America/Los_Angeles", gd.TimeZone)
+	assert.Equal(t, "2019-10-22T19:30:00-07:00", gd.StartTimeLocal)
+	local, err := gd.LocalStartTime()
+	assert.Nil(t, err)
+	assert.Equal(t, 19, local.Hour())
+
+	e := Event{GameID: "0021900002", GameDetail: &gd}
+	day, err := e.GameDay()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2019, 10, 22, 0, 0, 0, 0, time.UTC), day, "the eastern day, not the UTC day")
+
+	gd = GameDetail{}
+	assert.NotNil(t, gd.SetStartTime(start, "Mars/Olympus_Mons"))
+	assert.Equal(t, "2019-10-22", gd.StartDateEastern, "eastern fields are set whatever the zone")
+	assert.Equal(t, "", gd.TimeZone)
+}
+
+func TestParseGameDay(t *testing.T) {
+	for _, s := range []string{"2019-09-30", "20190930"} {
+		day, err := ParseGameDay(s)
+		assert.Nil(t, err)
+		assert.Equal(t, "2019-09-30", FormatGameDay(day))
+	}
+	_, err := ParseGameDay("9/30/2019")
+	assert.NotNil(t, err)
+
+	e := Event{GameDetail: &GameDetail{StartDateEastern: "20190930"}}
+	day, err := e.GameDay()
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-09-30", FormatGameDay(day))
+}
+
+func TestVenueTimeZone(t *testing.T) {
+	assert.Equal(t, "America/Chicago", VenueTimeZone(&Venue{Address: &Address{City: "Milwaukee", State: "WI"}}))
+	assert.Equal(t, "America/New_York", VenueTimeZone(&Venue{Address: &Address{City: "Knoxville", State: "TN"}}))
+	assert.Equal(t, "America/Chicago", VenueTimeZone(&Venue{Address: &Address{City: "Memphis", State: "TN"}}))
+	assert.Equal(t, "America/Toronto", VenueTimeZone(&Venue{Address: &Address{City: "Toronto", State: "ON"}}))
+	assert.Equal(t, "America/Phoenix", VenueTimeZone(&Venue{TimeZone: "America/Phoenix"}))
+	assert.Equal(t, "", VenueTimeZone(&Venue{FullName: "Scotiabank Arena"}))
+	assert.Equal(t, "", VenueTimeZone(nil))
+}
+
+func TestRestDays(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := ParseGameDay(s)
+		return d
+	}
+	assert.Equal(t, 0, RestDays(day("2020-01-01"), day("2020-01-02")), "back-to-back")
+	assert.Equal(t, 2, RestDays(day("2020-01-01"), day("2020-01-04")))
+	assert.Equal(t, 1, RestDays(day("2020-03-07"), day("2020-03-09")), "across the change to daylight time")
+
+	game := func(id string, date string, home string, visit string) *Event {
+		return &Event{GameID: GameID(id), HomeTeam: &Competitor{Abbreviation: home},
+			VisitTeam: &Competitor{Abbreviation: visit}, GameDetail: &GameDetail{StartDateEastern: date}}
+	}
+	rest := TeamRestDays("MIL", []*Event{
+		game("3", "2020-01-06", "MIL", "BOS"),
+		game("1", "2020-01-01", "MIL", "CHI"),
+		game("x", "2020-01-02", "BOS", "CHI"),
+		game("2", "2020-01-02", "DET", "MIL"),
+	})
+	assert.Equal(t, map[GameID]int{"2": 0, "3": 3}, rest)
+}
@@ -0,0 +1,565 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// the IdentityResolver keeps the crosswalk from provider ids (NBA gameId/teamId/personId, ESPN id and uid) to
+// the canonical ids tables are keyed on.  Deterministic rules go first: a provider id already in the crosswalk,
+// the TeamRegistry for teams, and for games the canonical key "2019-12-29:BOS:TOR" (US eastern game day, visit,
+// home) built from canonical team abbreviations.  Games the rules miss are matched on the team pair a day either
+// side, feeds disagree on the day of late west coast games, and players on their name backed by birth date.  A
+// fuzzy match that isn't clear cut is kept for review (see Ambiguous and Confirm) rather than guessed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+//Provider ... a source of provider ids
+type Provider string
+
+const (
+	//ProviderNBA is data.nba.net and stats.nba.com
+	ProviderNBA Provider = "nba"
+	//ProviderESPN ...
+	ProviderESPN Provider = "espn"
+)
+
+//EntityKind ... the kind of entity a crosswalk entry identifies
+type EntityKind string
+
+const (
+	//KindGame ...
+	KindGame EntityKind = "game"
+	//KindTeam ...
+	KindTeam EntityKind = "team"
+	//KindPlayer ...
+	KindPlayer EntityKind = "player"
+	//KindCompetitor is a team in a game
+	KindCompetitor EntityKind = "competitor"
+	//KindVenue ...
+	KindVenue EntityKind = "venue"
+	//KindOfficial is a referee or umpire
+	KindOfficial EntityKind = "official"
+)
+
+//MatchRule ... how a provider id was matched to its canonical id
+type MatchRule string
+
+const (
+	//RuleDeterministic the provider ids or canonical keys agree
+	RuleDeterministic MatchRule = "deterministic"
+	//RuleFuzzy a unique candidate scored above the threshold
+	RuleFuzzy MatchRule = "fuzzy"
+	//RuleManual confirmed by review
+	RuleManual MatchRule = "manual"
+)
+
+//ErrAmbiguousMatch is returned when a fuzzy match has more than one candidate, see IdentityResolver.Ambiguous
+var ErrAmbiguousMatch = errors.New("ambiguous identity match, held for review")
+
+//CrosswalkEntry ... a provider id and the canonical id it resolves to
+type CrosswalkEntry struct {
+	League      League     `json:"league"`
+	Kind        EntityKind `json:"kind"`                 // "player"
+	Provider    Provider   `json:"provider"`             // "espn"
+	ProviderID  string     `json:"providerId"`           // "3012"
+	CanonicalID string     `json:"canonicalId"`          // "nba:200768"
+	Name        string     `json:"name,omitempty"`       // "Kyle Lowry", kept to fuzzy match players after a reload
+	Rule        MatchRule  `json:"rule"`                 // "fuzzy"
+	Confidence  float64    `json:"confidence,omitempty"` // 0..1 score of a fuzzy match
+	MatchedAt   time.Time  `json:"matchedAt"`
+}
+
+//MatchCandidate ... a canonical id a provider id may belong to
+type MatchCandidate struct {
+	CanonicalID string  `json:"canonicalId"`
+	Name        string  `json:"name,omitempty"`
+	Score       float64 `json:"score"`
+}
+
+//AmbiguousMatch ... a provider id with more than one plausible canonical id, resolve with Confirm
+type AmbiguousMatch struct {
+	Kind       EntityKind        `json:"kind"`
+	Provider   Provider          `json:"provider"`
+	ProviderID string            `json:"providerId"`
+	Name       string            `json:"name,omitempty"` // player name or game key as the provider has it
+	Candidates []*MatchCandidate `json:"candidates"`
+	ReportedAt time.Time         `json:"reportedAt"`
+}
+
+type gameIdentity struct {
+	day   time.Time
+	visit string
+	home  string
+	ids   map[Provider]string
+}
+
+type playerIdentity struct {
+	name      string // normalized
+	birthDate *time.Time
+	ids       map[Provider]string
+}
+
+//IdentityResolver ... the crosswalk of a league
+type IdentityResolver struct {
+	League    League
+	Threshold float64 // minimum fuzzy score to accept a match, 0.85
+	Margin    float64 // how far the best candidate must lead the next to be accepted, 0.05
+	teams     *TeamRegistry
+	mu        sync.Mutex
+	crosswalk map[string]*CrosswalkEntry
+	games     map[string]*gameIdentity
+	players   map[string]*playerIdentity
+	ambiguous []*AmbiguousMatch
+}
+
+//NewIdentityResolver returns an empty resolver for league, teams resolve through TeamRegistryFor(league)
+func NewIdentityResolver(league League) *IdentityResolver {
+	return &IdentityResolver{League: league, Threshold: 0.85, Margin: 0.05, teams: TeamRegistryFor(league),
+		crosswalk: map[string]*CrosswalkEntry{}, games: map[string]*gameIdentity{}, players: map[string]*playerIdentity{}}
+}
+
+var (
+	identityResolvers   = map[League]*IdentityResolver{}
+	identityResolversMu sync.Mutex
+)
+
+//IdentityResolverFor returns the shared resolver of a league
+func IdentityResolverFor(league League) *IdentityResolver {
+	if league == "" {
+		league = LeagueNBA
+	}
+	identityResolversMu.Lock()
+	defer identityResolversMu.Unlock()
+	r, ok := identityResolvers[league]
+	if !ok {
+		r = NewIdentityResolver(league)
+		identityResolvers[league] = r
+	}
+	return r
+}
+
+func crosswalkKey(kind EntityKind, provider Provider, id string) string {
+	return string(kind) + ":" + string(provider) + ":" + id
+}
+
+func (r *IdentityResolver) link(kind EntityKind, provider Provider, id string, canonicalID string, rule MatchRule, confidence float64) *CrosswalkEntry {
+	entry := &CrosswalkEntry{League: r.League, Kind: kind, Provider: provider, ProviderID: id, CanonicalID: canonicalID,
+		Rule: rule, Confidence: confidence, MatchedAt: time.Now().UTC()}
+	if id != "" {
+		r.crosswalk[crosswalkKey(kind, provider, id)] = entry
+	}
+	return entry
+}
+
+func (r *IdentityResolver) report(kind EntityKind, provider Provider, id string, name string, candidates []*MatchCandidate) {
+	for _, a := range r.ambiguous {
+		if a.Kind == kind && a.Provider == provider && a.ProviderID == id {
+			a.Candidates = candidates
+			return
+		}
+	}
+	r.ambiguous = append(r.ambiguous, &AmbiguousMatch{Kind: kind, Provider: provider, ProviderID: id, Name: name,
+		Candidates: candidates, ReportedAt: time.Now().UTC()})
+}
+
+//Lookup returns the canonical id a provider id is already mapped to, or ""
+func (r *IdentityResolver) Lookup(kind EntityKind, provider Provider, id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.crosswalk[crosswalkKey(kind, provider, id)]; ok {
+		return entry.CanonicalID
+	}
+	return ""
+}
+
+//ResolveTeam returns the canonical id of the team in the league's TeamRegistry
+func (r *IdentityResolver) ResolveTeam(provider Provider, t *Team) (string, error) {
+	if t == nil {
+		return "", errors.New("nil team")
+	}
+	id := t.TeamIDNBA
+	if provider == ProviderESPN {
+		id = espnTeamID(t.TeamIDESPN)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.crosswalk[crosswalkKey(KindTeam, provider, id)]; ok {
+		return entry.CanonicalID, nil
+	}
+	canon := r.teams.Resolve(t)
+	if canon == nil {
+		return "", fmt.Errorf("team %s %s not in the %s registry", t.Abbreviation, id, r.League)
+	}
+	r.link(KindTeam, provider, id, canon.ID, RuleDeterministic, 0)
+	return canon.ID, nil
+}
+
+func (r *IdentityResolver) resolveCompetitor(provider Provider, c *Competitor) (string, error) {
+	if c == nil {
+		return "", errors.New("nil competitor")
+	}
+	if c.Team != nil {
+		if id, err := r.ResolveTeam(provider, c.Team); err == nil {
+			return id, nil
+		}
+	}
+	return r.ResolveTeam(provider, &Team{Abbreviation: c.Abbreviation})
+}
+
+//GameDay is the US eastern day the event starts, from StartTime or else StartDateEastern ("2006-01-02" or "20060102")
+func (e *Event) GameDay() (time.Time, error) {
+	if e.GameDetail == nil {
+		return time.Time{}, fmt.Errorf("game %s has no detail to take its day from", e.GameID)
+	}
+	if e.GameDetail.StartTime != nil && !e.GameDetail.StartTime.IsZero() {
+		return GameDayOf(*e.GameDetail.StartTime), nil
+	}
+	if day, err := ParseGameDay(e.GameDetail.StartDateEastern); err == nil {
+		return day, nil
+	}
+	return time.Time{}, fmt.Errorf("game %s has no start time", e.GameID)
+}
+
+//GameKey is the canonical key of a game "2019-12-29:BOS:TOR", visit then home
+func GameKey(day time.Time, visit string, home string) string {
+	return FormatGameDay(day) + ":" + visit + ":" + home
+}
+
+//ResolveGame returns the canonical key of the event, the provider id is its GameID.  The NBA and ESPN event
+//marshallers resolve each game so the crosswalk links the feeds of a game
+func (r *IdentityResolver) ResolveGame(provider Provider, e *Event) (string, error) {
+	id := string(e.GameID)
+	if canon := r.Lookup(KindGame, provider, id); canon != "" {
+		return canon, nil
+	}
+	visit, err := r.resolveCompetitor(provider, e.VisitTeam)
+	if err != nil {
+		return "", err
+	}
+	home, err := r.resolveCompetitor(provider, e.HomeTeam)
+	if err != nil {
+		return "", err
+	}
+	day, err := e.GameDay()
+	if err != nil {
+		return "", err
+	}
+	key := GameKey(day, visit, home)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if g, ok := r.games[key]; ok {
+		// a different id from the same provider is a different game e.g. a doubleheader or a replayed game, the
+		// second is keyed "2019-12-25:BOS:TOR:2"
+		for n := 2; g.ids[provider] != "" && g.ids[provider] != id; n++ {
+			next := key + ":" + strconv.Itoa(n)
+			if g, ok = r.games[next]; !ok {
+				r.games[next] = &gameIdentity{day: day, visit: visit, home: home, ids: map[Provider]string{provider: id}}
+				r.link(KindGame, provider, id, next, RuleDeterministic, 0)
+				return next, nil
+			}
+			key = next
+		}
+		g.ids[provider] = id
+		r.link(KindGame, provider, id, key, RuleDeterministic, 0)
+		return key, nil
+	}
+	candidates := []*MatchCandidate{}
+	for canon, g := range r.games {
+		if _, taken := g.ids[provider]; taken {
+			continue // a different id from the same provider is a different game e.g. back-to-backs in one building
+		}
+		if g.visit == visit && g.home == home {
+			if diff := g.day.Sub(day); diff <= 24*time.Hour && diff >= -24*time.Hour {
+				candidates = append(candidates, &MatchCandidate{CanonicalID: canon, Score: 0.9})
+			}
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		r.games[key] = &gameIdentity{day: day, visit: visit, home: home, ids: map[Provider]string{provider: id}}
+		r.link(KindGame, provider, id, key, RuleDeterministic, 0)
+		return key, nil
+	case 1:
+		r.games[candidates[0].CanonicalID].ids[provider] = id
+		r.link(KindGame, provider, id, candidates[0].CanonicalID, RuleFuzzy, candidates[0].Score)
+		return candidates[0].CanonicalID, nil
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].CanonicalID < candidates[j].CanonicalID })
+	r.report(KindGame, provider, id, key, candidates)
+	return "", ErrAmbiguousMatch
+}
+
+func playerProviderID(provider Provider, p *Player) string {
+	if provider == ProviderESPN {
+		return p.IDESPN
+	}
+	return p.IDNBA
+}
+
+//ResolvePlayer returns the canonical id of the player, the provider id is its IDNBA or IDESPN.  New players
+//are keyed by the first provider id seen e.g. "nba:200768"
+func (r *IdentityResolver) ResolvePlayer(provider Provider, p *Player) (string, error) {
+	id := playerProviderID(provider, p)
+	if id == "" {
+		return "", fmt.Errorf("player %s has no %s id", p.FullName, provider)
+	}
+	name := p.FullName
+	if name == "" {
+		name = p.DisplayName
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.crosswalk[crosswalkKey(KindPlayer, provider, id)]; ok {
+		return entry.CanonicalID, nil
+	}
+	// the player already carries the id of another provider we know
+	for _, other := range []Provider{ProviderNBA, ProviderESPN} {
+		if oid := playerProviderID(other, p); other != provider && oid != "" {
+			if entry, ok := r.crosswalk[crosswalkKey(KindPlayer, other, oid)]; ok {
+				r.player(entry.CanonicalID, name).ids[provider] = id
+				r.link(KindPlayer, provider, id, entry.CanonicalID, RuleDeterministic, 0).Name = name
+				return entry.CanonicalID, nil
+			}
+		}
+	}
+
+	normalized := normalizeName(name)
+	candidates := []*MatchCandidate{}
+	for canon, pi := range r.players {
+		if _, taken := pi.ids[provider]; taken {
+			continue // a different id from the same provider is a different player
+		}
+		if p.BirthDate != nil && pi.birthDate != nil && !sameDay(*p.BirthDate, *pi.birthDate) {
+			continue
+		}
+		score := nameSimilarity(normalized, pi.name)
+		if p.BirthDate != nil && pi.birthDate != nil {
+			score = score + 0.1
+			if score > 1 {
+				score = 1
+			}
+		}
+		if score >= r.Threshold {
+			candidates = append(candidates, &MatchCandidate{CanonicalID: canon, Name: pi.name, Score: score})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score == candidates[j].Score {
+			return candidates[i].CanonicalID < candidates[j].CanonicalID
+		}
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > 1 && candidates[0].Score-candidates[1].Score < r.Margin {
+		r.report(KindPlayer, provider, id, name, candidates)
+		return "", ErrAmbiguousMatch
+	}
+	if len(candidates) > 0 {
+		best := candidates[0]
+		r.player(best.CanonicalID, name).ids[provider] = id
+		r.link(KindPlayer, provider, id, best.CanonicalID, RuleFuzzy, best.Score).Name = name
+		return best.CanonicalID, nil
+	}
+	canon := string(provider) + ":" + id
+	r.players[canon] = &playerIdentity{name: normalized, birthDate: p.BirthDate, ids: map[Provider]string{provider: id}}
+	r.link(KindPlayer, provider, id, canon, RuleDeterministic, 0).Name = name
+	return canon, nil
+}
+
+//player returns the canonical player, adding it under name when it isn't known
+func (r *IdentityResolver) player(canonicalID string, name string) *playerIdentity {
+	pi, ok := r.players[canonicalID]
+	if !ok {
+		pi = &playerIdentity{name: normalizeName(name), ids: map[Provider]string{}}
+		r.players[canonicalID] = pi
+	}
+	return pi
+}
+
+//Confirm records a reviewed match of a provider id to a canonical id and clears it from Ambiguous
+func (r *IdentityResolver) Confirm(kind EntityKind, provider Provider, id string, canonicalID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := r.link(kind, provider, id, canonicalID, RuleManual, 1)
+	pending := []*AmbiguousMatch{}
+	for _, a := range r.ambiguous {
+		if a.Kind == kind && a.Provider == provider && a.ProviderID == id {
+			entry.Name = a.Name
+			continue
+		}
+		pending = append(pending, a)
+	}
+	r.ambiguous = pending
+	switch kind {
+	case KindPlayer:
+		r.player(canonicalID, entry.Name).ids[provider] = id
+	case KindGame:
+		if g, ok := r.games[canonicalID]; ok {
+			g.ids[provider] = id
+		}
+	}
+}
+
+//Ambiguous returns the matches held for review
+func (r *IdentityResolver) Ambiguous() []*AmbiguousMatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*AmbiguousMatch{}, r.ambiguous...)
+}
+
+//Crosswalk returns the entries sorted by kind, provider and provider id, for persisting
+func (r *IdentityResolver) Crosswalk() []*CrosswalkEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := []*CrosswalkEntry{}
+	for _, entry := range r.crosswalk {
+		c := *entry
+		entries = append(entries, &c)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return crosswalkKey(entries[i].Kind, entries[i].Provider, entries[i].ProviderID) <
+			crosswalkKey(entries[j].Kind, entries[j].Provider, entries[j].ProviderID)
+	})
+	return entries
+}
+
+//Load restores a persisted crosswalk, canonical games and players are rebuilt from the entries
+func (r *IdentityResolver) Load(entries []*CrosswalkEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range entries {
+		c := *entry
+		r.crosswalk[crosswalkKey(c.Kind, c.Provider, c.ProviderID)] = &c
+		switch c.Kind {
+		case KindGame:
+			parts := strings.Split(c.CanonicalID, ":") // a second game of the day has a ":2" suffix
+			day, err := time.Parse(GameDayFormat, parts[0])
+			if len(parts) < 3 || len(parts) > 4 || err != nil {
+				return fmt.Errorf("invalid game key %s", c.CanonicalID)
+			}
+			g, ok := r.games[c.CanonicalID]
+			if !ok {
+				g = &gameIdentity{day: day, visit: parts[1], home: parts[2], ids: map[Provider]string{}}
+				r.games[c.CanonicalID] = g
+			}
+			g.ids[c.Provider] = c.ProviderID
+		case KindPlayer:
+			r.player(c.CanonicalID, c.Name).ids[c.Provider] = c.ProviderID
+		}
+	}
+	return nil
+}
+
+//ReadCrosswalk decodes newline delimited crosswalk entries as written by the ndjson table
+func ReadCrosswalk(rd io.Reader) ([]*CrosswalkEntry, error) {
+	entries := []*CrosswalkEntry{}
+	dec := json.NewDecoder(rd)
+	for {
+		entry := CrosswalkEntry{}
+		if err := dec.Decode(&entry); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return entries, err
+		}
+		entries = append(entries, &entry)
+	}
+}
+
+func sameDay(a time.Time, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+//normalizeName lowercases a person's name, drops punctuation and suffixes e.g. "Larry Nance Jr." is "larry nance"
+func normalizeName(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(c rune) bool {
+		return unicode.IsSpace(c) || c == '-'
+	})
+	kept := []string{}
+	for _, w := range words {
+		w = strings.Map(func(c rune) rune {
+			if unicode.IsLetter(c) || unicode.IsDigit(c) {
+				return c
+			}
+			return -1
+		}, w)
+		switch w {
+		case "", "jr", "sr", "ii", "iii", "iv":
+			continue
+		}
+		kept = append(kept, w)
+	}
+	return strings.Join(kept, " ")
+}
+
+//nameSimilarity is 1 less the edit distance of two normalized names over the longer one's length
+func nameSimilarity(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
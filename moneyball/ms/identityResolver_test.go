@@ -0,0 +1,227 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func identityEvent(gameID string, visit *Team, home *Team, detail *GameDetail) *Event {
+	return &Event{GameID: GameID(gameID), League: LeagueNBA, VisitTeam: &Competitor{Team: visit},
+		HomeTeam: &Competitor{Team: home}, GameDetail: detail}
+}
+
+func TestResolveGame(t *testing.T) {
+	r := NewIdentityResolver(LeagueNBA)
+	start := time.Date(2019, time.December, 26, 0, 30, 0, 0, time.UTC) // 7:30pm eastern on the 25th
+	nba := identityEvent("0021900478", &Team{TeamIDNBA: "1610612738"}, &Team{TeamIDNBA: "1610612761"},
+		&GameDetail{StartTime: &start})
+	key, err := r.ResolveGame(ProviderNBA, nba)
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-12-25:BOS:TOR", key)
+
+	espn := identityEvent("401161427", &Team{TeamIDESPN: "2"}, &Team{TeamIDESPN: "s:40~l:46~t:28"},
+		&GameDetail{StartDateEastern: "20191225"})
+	key, err = r.ResolveGame(ProviderESPN, espn)
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-12-25:BOS:TOR", key, "same game from both feeds")
+
+	// a feed a day off still matches on the team pair
+	_, err = r.ResolveGame(ProviderNBA, identityEvent("0021900550", &Team{Abbreviation: "BOS"}, &Team{Abbreviation: "TOR"},
+		&GameDetail{StartDateEastern: "2020-01-10"}))
+	assert.Nil(t, err)
+	late := identityEvent("401161999", &Team{TeamIDESPN: "2"}, &Team{TeamIDESPN: "28"},
+		&GameDetail{StartDateEastern: "2020-01-11"})
+	key, err = r.ResolveGame(ProviderESPN, late)
+	assert.Nil(t, err)
+	assert.Equal(t, "2020-01-10:BOS:TOR", key)
+	assert.Equal(t, "2020-01-10:BOS:TOR", r.Lookup(KindGame, ProviderESPN, "401161999"))
+
+	// between two known games it's held for review
+	_, err = r.ResolveGame(ProviderNBA, identityEvent("0021900800", &Team{Abbreviation: "BOS"}, &Team{Abbreviation: "TOR"},
+		&GameDetail{StartDateEastern: "2020-02-01"}))
+	assert.Nil(t, err)
+	_, err = r.ResolveGame(ProviderNBA, identityEvent("0021900999", &Team{Abbreviation: "BOS"}, &Team{Abbreviation: "TOR"},
+		&GameDetail{StartDateEastern: "2020-02-03"}))
+	assert.Nil(t, err)
+	_, err = r.ResolveGame(ProviderESPN, identityEvent("401162000", &Team{TeamIDESPN: "2"}, &Team{TeamIDESPN: "28"},
+		&GameDetail{StartDateEastern: "2020-02-02"}))
+	assert.Equal(t, ErrAmbiguousMatch, err)
+	if assert.Len(t, r.Ambiguous(), 1) {
+		assert.Len(t, r.Ambiguous()[0].Candidates, 2)
+	}
+	r.Confirm(KindGame, ProviderESPN, "401162000", "2020-02-03:BOS:TOR")
+	assert.Len(t, r.Ambiguous(), 0)
+	assert.Equal(t, "2020-02-03:BOS:TOR", r.Lookup(KindGame, ProviderESPN, "401162000"))
+
+	_, err = r.ResolveGame(ProviderESPN, identityEvent("1", &Team{TeamIDESPN: "999"}, &Team{TeamIDESPN: "28"},
+		&GameDetail{StartDateEastern: "2019-12-26"}))
+	assert.NotNil(t, err, "unknown team")
+}
+
+func TestResolveGameBackToBack(t *testing.T) {
+	// 2020-21 series were played on consecutive nights in the same building
+	r := NewIdentityResolver(LeagueNBA)
+	first, err := r.ResolveGame(ProviderNBA, identityEvent("0022000120", &Team{Abbreviation: "BOS"},
+		&Team{Abbreviation: "CHI"}, &GameDetail{StartDateEastern: "2021-01-15"}))
+	assert.Nil(t, err)
+	second, err := r.ResolveGame(ProviderNBA, identityEvent("0022000135", &Team{Abbreviation: "BOS"},
+		&Team{Abbreviation: "CHI"}, &GameDetail{StartDateEastern: "2021-01-16"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "2021-01-15:BOS:CHI", first)
+	assert.Equal(t, "2021-01-16:BOS:CHI", second, "a second NBA id is a second game")
+
+	// each ESPN game then links to its own night
+	key, err := r.ResolveGame(ProviderESPN, identityEvent("401267140", &Team{Abbreviation: "BOS"},
+		&Team{Abbreviation: "CHI"}, &GameDetail{StartDateEastern: "2021-01-16"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "2021-01-16:BOS:CHI", key)
+	key, err = r.ResolveGame(ProviderESPN, identityEvent("401267125", &Team{Abbreviation: "BOS"},
+		&Team{Abbreviation: "CHI"}, &GameDetail{StartDateEastern: "2021-01-15"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "2021-01-15:BOS:CHI", key)
+
+	// the links survive a reload
+	reloaded := NewIdentityResolver(LeagueNBA)
+	assert.Nil(t, reloaded.Load(r.Crosswalk()))
+	key, err = reloaded.ResolveGame(ProviderNBA, identityEvent("0022000136", &Team{Abbreviation: "BOS"},
+		&Team{Abbreviation: "CHI"}, &GameDetail{StartDateEastern: "2021-01-17"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "2021-01-17:BOS:CHI", key)
+}
+
+func TestResolveGameDoubleheader(t *testing.T) {
+	r := NewIdentityResolver(LeagueNBA)
+	g1 := identityEvent("401161427", &Team{Abbreviation: "BOS"}, &Team{Abbreviation: "TOR"},
+		&GameDetail{StartDateEastern: "2019-12-25"})
+	g2 := identityEvent("401161428", &Team{Abbreviation: "BOS"}, &Team{Abbreviation: "TOR"},
+		&GameDetail{StartDateEastern: "2019-12-25"})
+	key, err := r.ResolveGame(ProviderESPN, g1)
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-12-25:BOS:TOR", key)
+	key, err = r.ResolveGame(ProviderESPN, g2)
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-12-25:BOS:TOR:2", key, "a second id from one provider is a second game")
+	key, _ = r.ResolveGame(ProviderESPN, g1)
+	assert.Equal(t, "2019-12-25:BOS:TOR", key)
+
+	// the other provider's copies link to the games in the order they come
+	key, err = r.ResolveGame(ProviderNBA, identityEvent("0021900478", &Team{Abbreviation: "BOS"},
+		&Team{Abbreviation: "TOR"}, &GameDetail{StartDateEastern: "2019-12-25"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-12-25:BOS:TOR", key)
+	key, err = r.ResolveGame(ProviderNBA, identityEvent("0021900479", &Team{Abbreviation: "BOS"},
+		&Team{Abbreviation: "TOR"}, &GameDetail{StartDateEastern: "2019-12-25"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-12-25:BOS:TOR:2", key)
+
+	reloaded := NewIdentityResolver(LeagueNBA)
+	assert.Nil(t, reloaded.Load(r.Crosswalk()))
+	assert.Equal(t, "2019-12-25:BOS:TOR:2", reloaded.Lookup(KindGame, ProviderNBA, "0021900479"))
+}
+
+func TestResolvePlayer(t *testing.T) {
+	r := NewIdentityResolver(LeagueNBA)
+	bd := time.Date(1986, time.March, 25, 0, 0, 0, 0, time.UTC)
+	id, err := r.ResolvePlayer(ProviderNBA, &Player{IDNBA: "200768", FullName: "Kyle Lowry", BirthDate: &bd})
+	assert.Nil(t, err)
+	assert.Equal(t, "nba:200768", id)
+
+	id, err = r.ResolvePlayer(ProviderESPN, &Player{IDESPN: "3012", DisplayName: "Kyle Lowry", BirthDate: &bd})
+	assert.Nil(t, err)
+	assert.Equal(t, "nba:200768", id)
+
+	id, _ = r.ResolvePlayer(ProviderNBA, &Player{IDNBA: "1626204", FullName: "Larry Nance Jr."})
+	assert.Equal(t, "nba:1626204", id)
+	id, _ = r.ResolvePlayer(ProviderESPN, &Player{IDESPN: "2580365", FullName: "Larry Nance"})
+	assert.Equal(t, "nba:1626204", id, "suffixes are ignored")
+
+	// a different birth date is a different player
+	other := time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)
+	id, _ = r.ResolvePlayer(ProviderESPN, &Player{IDESPN: "9999", FullName: "Kyle Lowry", BirthDate: &other})
+	assert.Equal(t, "espn:9999", id)
+
+	// two players of the same name are held for review
+	r.ResolvePlayer(ProviderNBA, &Player{IDNBA: "203187", FullName: "Chris Johnson"})
+	r.ResolvePlayer(ProviderNBA, &Player{IDNBA: "204067", FullName: "Chris Johnson"})
+	_, err = r.ResolvePlayer(ProviderESPN, &Player{IDESPN: "2528588", FullName: "Chris Johnson"})
+	assert.Equal(t, ErrAmbiguousMatch, err)
+	if assert.Len(t, r.Ambiguous(), 1) {
+		assert.Equal(t, "Chris Johnson", r.Ambiguous()[0].Name)
+	}
+
+	// a player carrying both ids links deterministically
+	id, _ = r.ResolvePlayer(ProviderESPN, &Player{IDNBA: "203187", IDESPN: "2528588", FullName: "Chris Johnson"})
+	assert.Equal(t, "nba:203187", id)
+	assert.Equal(t, RuleDeterministic, r.crosswalk[crosswalkKey(KindPlayer, ProviderESPN, "2528588")].Rule)
+}
+
+func TestCrosswalkPersistence(t *testing.T) {
+	r := NewIdentityResolver(LeagueNBA)
+	r.ResolvePlayer(ProviderNBA, &Player{IDNBA: "200768", FullName: "Kyle Lowry"})
+	r.ResolvePlayer(ProviderESPN, &Player{IDESPN: "3012", FullName: "Kyle Lowry"})
+	r.ResolveGame(ProviderNBA, identityEvent("0021900478", &Team{TeamIDNBA: "1610612738"}, &Team{TeamIDNBA: "1610612761"},
+		&GameDetail{StartDateEastern: "2019-12-25"}))
+
+	var b bytes.Buffer
+	assert.Nil(t, r.marshalNBJSON(&b))
+	assert.Equal(t, "crosswalkNBA", r.tableName())
+	entries, err := ReadCrosswalk(&b)
+	assert.Nil(t, err)
+	assert.Len(t, entries, len(r.Crosswalk()))
+
+	reloaded := NewIdentityResolver(LeagueNBA)
+	assert.Nil(t, reloaded.Load(entries))
+	assert.Equal(t, "nba:200768", reloaded.Lookup(KindPlayer, ProviderESPN, "3012"))
+	id, _ := reloaded.ResolvePlayer(ProviderNBA, &Player{IDNBA: "200768"})
+	assert.Equal(t, "nba:200768", id)
+	key, _ := reloaded.ResolveGame(ProviderESPN, identityEvent("401161427", &Team{TeamIDESPN: "2"}, &Team{TeamIDESPN: "28"},
+		&GameDetail{StartDateEastern: "2019-12-25"}))
+	assert.Equal(t, "2019-12-25:BOS:TOR", key)
+}
+
+func TestPlayerMasterEntityLeague(t *testing.T) {
+	_, err := (&Player{IDESPN: "3149673", FullName: "A'ja Wilson"}).MasterEntity()
+	assert.NotNil(t, err, "no league to resolve in")
+
+	id, err := (&Player{IDESPN: "3149673", FullName: "A'ja Wilson", League: LeagueWNBA}).MasterEntity()
+	assert.Nil(t, err)
+	assert.Equal(t, "espn:3149673", id)
+	assert.Equal(t, "espn:3149673", IdentityResolverFor(LeagueWNBA).Lookup(KindPlayer, ProviderESPN, "3149673"))
+	assert.Equal(t, "", IdentityResolverFor(LeagueNBA).Lookup(KindPlayer, ProviderESPN, "3149673"), "not in the NBA crosswalk")
+
+	_, err = NewKeyer().Key(KindPlayer, &Player{IDNBA: "1628932", FullName: "A'ja Wilson"}, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,423 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// keys of the tables are built by a KeyScheme registered per kind of entity.  A scheme is versioned and
+// documents its format, the latest version registered is the one in use:
+//
+//	game       v1 "2019-12-25:BOS:TOR"     eastern game day:visit:home, canonical abbreviations
+//	competitor v1 "TOR:2019-12-25:BOS:TOR" abbreviation:game key
+//	team       v1 "TOR:2020:2"             abbreviation:season year:season stage of the game
+//	venue      v1 "87M2MJ76+8X"            open location code of the address
+//	player     v1 "nba:200768"             canonical id of the league's IdentityResolver
+//	official   v1 "scott-foster"           normalized name
+//
+// the Keyer remembers which entity each key of a batch went to and reports a KeyCollision when a different one
+// gets the same key (e.g. a baseball doubleheader under the game v1 scheme), a batch ends when a table is loaded
+// (see Keyer.Reset).  When a scheme
+// changes, register the new version and Rekey the historical data, the KeyChanges map old keys to new for the
+// tables keyed on them
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//ErrKeyCollision is returned with the key when it was already given to a different entity, see Keyer.Collisions
+var ErrKeyCollision = errors.New("key already used by a different entity")
+
+//KeyScheme ... how the key of a kind of entity is built
+type KeyScheme struct {
+	Kind    EntityKind
+	Version int
+	Format  string                                        // documents the key e.g. "{abbreviation}:{seasonYear}:{seasonStage}"
+	Key     func(v interface{}, e *Event) (string, error) // e is the event v is keyed in, nil when there is none
+	// Identity tells entities apart for collision detection, the same key for a different identity collides;
+	// nil or "" skips the check.  e is the event as for Key
+	Identity func(v interface{}, e *Event) string
+}
+
+//KeyCollision ... a key given to more than one entity
+type KeyCollision struct {
+	Kind       EntityKind `json:"kind"`
+	Version    int        `json:"version"`
+	Key        string     `json:"key"`
+	Identities []string   `json:"identities"` // first the holder of the key then the newcomer
+	DetectedAt time.Time  `json:"detectedAt"`
+}
+
+//KeyChange ... the key of an entity under two versions of its scheme
+type KeyChange struct {
+	Kind   EntityKind `json:"kind"`
+	From   int        `json:"fromVersion"`
+	To     int        `json:"toVersion"`
+	OldKey string     `json:"oldKey"`
+	NewKey string     `json:"newKey"`
+}
+
+//KeyChangeTable ... the key changes of a league, loaded to re-key the tables
+type KeyChangeTable struct {
+	League  League
+	Changes []*KeyChange
+}
+
+//Keyer ... the registered key schemes and the keys handed out
+type Keyer struct {
+	mu         sync.Mutex
+	schemes    map[EntityKind]map[int]*KeyScheme
+	current    map[EntityKind]int
+	seen       map[string]string
+	collisions []*KeyCollision
+}
+
+//NewKeyer returns a Keyer with the v1 schemes registered
+func NewKeyer() *Keyer {
+	k := &Keyer{schemes: map[EntityKind]map[int]*KeyScheme{}, current: map[EntityKind]int{}, seen: map[string]string{}}
+	for _, s := range defaultKeySchemes() {
+		k.Register(s)
+	}
+	return k
+}
+
+var (
+	defaultKeyer     *Keyer
+	defaultKeyerOnce sync.Once
+)
+
+//DefaultKeyer returns the shared Keyer MasterIdentity uses
+func DefaultKeyer() *Keyer {
+	defaultKeyerOnce.Do(func() {
+		defaultKeyer = NewKeyer()
+	})
+	return defaultKeyer
+}
+
+//Register adds a scheme, a version above the current one becomes the scheme in use
+func (k *Keyer) Register(s KeyScheme) error {
+	if s.Kind == "" || s.Version < 1 || s.Key == nil {
+		return fmt.Errorf("key scheme needs a kind, a version from 1 and a Key func")
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.schemes[s.Kind]; !ok {
+		k.schemes[s.Kind] = map[int]*KeyScheme{}
+	}
+	if _, ok := k.schemes[s.Kind][s.Version]; ok {
+		return fmt.Errorf("%s key scheme v%d is already registered", s.Kind, s.Version)
+	}
+	k.schemes[s.Kind][s.Version] = &s
+	if s.Version > k.current[s.Kind] {
+		k.current[s.Kind] = s.Version
+	}
+	return nil
+}
+
+//Scheme returns version of the kind's scheme, 0 is the current one
+func (k *Keyer) Scheme(kind EntityKind, version int) (*KeyScheme, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if version == 0 {
+		version = k.current[kind]
+	}
+	s, ok := k.schemes[kind][version]
+	if !ok {
+		return nil, fmt.Errorf("no %s key scheme v%d", kind, version)
+	}
+	return s, nil
+}
+
+//Key builds the key of v with the current scheme of kind, e is the event v is keyed in if any.  The key
+//is returned with ErrKeyCollision when it was already given to a different entity
+func (k *Keyer) Key(kind EntityKind, v interface{}, e *Event) (string, error) {
+	s, err := k.Scheme(kind, 0)
+	if err != nil {
+		return "", err
+	}
+	key, err := s.Key(v, e)
+	if err != nil {
+		return "", err
+	}
+	if s.Identity == nil {
+		return key, nil
+	}
+	identity := s.Identity(v, e)
+	if identity == "" {
+		return key, nil
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	seenKey := string(kind) + ":" + strconv.Itoa(s.Version) + ":" + key
+	holder, ok := k.seen[seenKey]
+	if !ok {
+		k.seen[seenKey] = identity
+		return key, nil
+	}
+	if holder != identity {
+		k.collisions = append(k.collisions, &KeyCollision{Kind: kind, Version: s.Version, Key: key,
+			Identities: []string{holder, identity}, DetectedAt: time.Now().UTC()})
+		return key, ErrKeyCollision
+	}
+	return key, nil
+}
+
+//Collisions returns the collisions detected so far
+func (k *Keyer) Collisions() []*KeyCollision {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return append([]*KeyCollision{}, k.collisions...)
+}
+
+//Reset forgets the keys handed out and returns the collisions detected since the last Reset, the keys are only
+//remembered for a batch (e.g. a season of scoreboards) so call it when the batch is loaded
+func (k *Keyer) Reset() []*KeyCollision {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	collisions := k.collisions
+	k.seen = map[string]string{}
+	k.collisions = nil
+	return collisions
+}
+
+//Rekey returns the key of v under two versions of the kind's scheme
+func (k *Keyer) Rekey(kind EntityKind, from int, to int, v interface{}, e *Event) (*KeyChange, error) {
+	old, err := k.Scheme(kind, from)
+	if err != nil {
+		return nil, err
+	}
+	next, err := k.Scheme(kind, to)
+	if err != nil {
+		return nil, err
+	}
+	change := KeyChange{Kind: kind, From: old.Version, To: next.Version}
+	if change.OldKey, err = old.Key(v, e); err != nil {
+		return nil, err
+	}
+	if change.NewKey, err = next.Key(v, e); err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+//RekeyEvents moves the events' games, competitors or teams (kind) from one version of the scheme to another,
+//the entities get the new key and the changed keys are returned to re-key the stored tables
+func (k *Keyer) RekeyEvents(events []Event, kind EntityKind, from int, to int) ([]*KeyChange, error) {
+	changes := []*KeyChange{}
+	rekey := func(id *EntityID, v interface{}, e *Event) error {
+		change, err := k.Rekey(kind, from, to, v, e)
+		if err != nil {
+			return err
+		}
+		id.ID = change.NewKey
+		if change.OldKey != change.NewKey {
+			changes = append(changes, change)
+		}
+		return nil
+	}
+	for i := range events {
+		e := &events[i]
+		switch kind {
+		case KindGame:
+			if err := rekey(&e.EntityID, e, nil); err != nil {
+				return changes, err
+			}
+		case KindCompetitor, KindTeam:
+			for _, c := range []*Competitor{e.HomeTeam, e.VisitTeam} {
+				if c == nil {
+					continue
+				}
+				if kind == KindCompetitor {
+					if err := rekey(&c.EntityID, c, e); err != nil {
+						return changes, err
+					}
+				} else if c.Team != nil {
+					if err := rekey(&c.Team.EntityID, c.Team, e); err != nil {
+						return changes, err
+					}
+				}
+			}
+		default:
+			return changes, fmt.Errorf("%s keys are not held by events", kind)
+		}
+	}
+	return changes, nil
+}
+
+//gameKeyIdentity tells games of the same teams on the same day apart by the canonical game the league's
+//IdentityResolver resolves them to, so the NBA and ESPN feeds of one game agree.  Events of no provider (e.g.
+//merged) take the crosswalk entry of their game id, else the game id itself
+func gameKeyIdentity(e *Event) string {
+	if e == nil || e.GameID == "" {
+		return ""
+	}
+	r := IdentityResolverFor(e.League)
+	if e.Provider != "" {
+		if canonical, err := r.ResolveGame(e.Provider, e); err == nil {
+			return canonical
+		}
+	}
+	for _, provider := range []Provider{ProviderNBA, ProviderESPN} {
+		if canonical := r.Lookup(KindGame, provider, string(e.GameID)); canonical != "" {
+			return canonical
+		}
+	}
+	return string(e.GameID)
+}
+
+func keyTypeError(kind EntityKind, v interface{}) error {
+	return fmt.Errorf("%s key scheme can't key %T", kind, v)
+}
+
+func defaultKeySchemes() []KeyScheme {
+	return []KeyScheme{
+		{Kind: KindGame, Version: 1, Format: "{eastern game day yyyy-mm-dd}:{visit}:{home}",
+			Key: func(v interface{}, _ *Event) (string, error) {
+				e, ok := v.(*Event)
+				if !ok || e == nil {
+					return "", keyTypeError(KindGame, v)
+				}
+				return e.CanonicalKey()
+			},
+			Identity: func(v interface{}, _ *Event) string {
+				e, _ := v.(*Event)
+				return gameKeyIdentity(e)
+			}},
+		{Kind: KindCompetitor, Version: 1, Format: "{abbreviation}:{game key}",
+			Key: func(v interface{}, e *Event) (string, error) {
+				c, ok := v.(*Competitor)
+				if !ok || c == nil || e == nil {
+					return "", keyTypeError(KindCompetitor, v)
+				}
+				game := e.EntityID.ID
+				if game == "" {
+					var err error
+					if game, err = e.CanonicalKey(); err != nil {
+						return "", err
+					}
+				}
+				return c.Abbreviation + ":" + game, nil
+			},
+			// the competitors of two games with one key e.g. a doubleheader are told apart by their game
+			Identity: func(_ interface{}, e *Event) string {
+				return gameKeyIdentity(e)
+			}},
+		{Kind: KindTeam, Version: 1, Format: "{abbreviation}:{seasonYear}:{seasonStage}",
+			Key: func(v interface{}, e *Event) (string, error) {
+				t, ok := v.(*Team)
+				if !ok || t == nil || e == nil {
+					return "", keyTypeError(KindTeam, v)
+				}
+				return t.Abbreviation + ":" + strconv.Itoa(e.Season.SeasonYear) + ":" + strconv.Itoa(e.Season.SeasonStage), nil
+			}},
+		{Kind: KindVenue, Version: 1, Format: "{open location code}",
+			Key: func(v interface{}, _ *Event) (string, error) {
+				venue, ok := v.(*Venue)
+				if !ok || venue == nil {
+					return "", keyTypeError(KindVenue, v)
+				}
+				if venue.EntityID.ID != "" {
+					return venue.EntityID.ID, nil
+				}
+				return GetGeoCodeAddress(venue)
+			},
+			// arenas shared by two teams have one name, a different name at the same spot is worth a look
+			Identity: func(v interface{}, _ *Event) string {
+				venue, _ := v.(*Venue)
+				if venue == nil {
+					return ""
+				}
+				return normalize(venue.FullName)
+			}},
+		{Kind: KindPlayer, Version: 1, Format: "{provider}:{provider id} of the player's crosswalk",
+			Key: func(v interface{}, e *Event) (string, error) {
+				p, ok := v.(*Player)
+				if !ok || p == nil {
+					return "", keyTypeError(KindPlayer, v)
+				}
+				league := p.League
+				if e != nil && e.League != "" {
+					league = e.League
+				}
+				if league == "" {
+					return "", fmt.Errorf("player %s has no league to key in", p.FullName)
+				}
+				provider := ProviderNBA
+				if p.IDNBA == "" {
+					provider = ProviderESPN
+				}
+				return IdentityResolverFor(league).ResolvePlayer(provider, p)
+			}},
+		{Kind: KindOfficial, Version: 1, Format: "{normalized name with dashes}",
+			Key: func(v interface{}, _ *Event) (string, error) {
+				var name string
+				switch o := v.(type) {
+				case *GameOfficial:
+					name = o.Name
+				case *Official:
+					name = o.Name
+				default:
+					return "", keyTypeError(KindOfficial, v)
+				}
+				name = strings.Replace(normalizeName(name), " ", "-", -1)
+				if name == "" {
+					return "", fmt.Errorf("official has no name to key")
+				}
+				return name, nil
+			}},
+	}
+}
+
+//sortedKinds lists the kinds with a registered scheme
+func (k *Keyer) sortedKinds() []EntityKind {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	kinds := []EntityKind{}
+	for kind := range k.schemes {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	return kinds
+}
+
+//Schemes returns the current scheme of every kind, e.g. to document the keys of a dataset
+func (k *Keyer) Schemes() []*KeyScheme {
+	schemes := []*KeyScheme{}
+	for _, kind := range k.sortedKinds() {
+		if s, err := k.Scheme(kind, 0); err == nil {
+			schemes = append(schemes, s)
+		}
+	}
+	return schemes
+}
@@ -0,0 +1,166 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func keyedEvent(start time.Time) *Event {
+	return &Event{GameID: "0021900478", League: LeagueNBA, Season: Season{2020, 2},
+		VisitTeam:  &Competitor{Abbreviation: "BOS", Team: NBATeamRegistry().ByAbbreviation("BOS")},
+		HomeTeam:   &Competitor{Abbreviation: "TOR", Team: NBATeamRegistry().ByAbbreviation("TOR")},
+		GameDetail: &GameDetail{StartTime: &start}}
+}
+
+func TestMasterIdentityKeys(t *testing.T) {
+	e := keyedEvent(time.Date(2019, time.December, 26, 0, 30, 0, 0, time.UTC))
+	key, err := MasterIdentity(e)
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-12-25:BOS:TOR", key)
+	assert.Equal(t, "TOR:2019-12-25:BOS:TOR", e.HomeTeam.ID)
+	assert.Equal(t, "BOS:2019-12-25:BOS:TOR", e.VisitTeam.ID)
+	assert.Equal(t, "TOR:2020:2", e.HomeTeam.Team.ID, "season digits, not runes")
+	assert.Equal(t, "TOR", NBATeamRegistry().ByAbbreviation("TOR").ID, "the canonical team is left alone")
+
+	key, err = MasterIdentity(&GameOfficial{Name: "Scott Foster"})
+	assert.Nil(t, err)
+	assert.Equal(t, "scott-foster", key)
+}
+
+func TestKeyCollision(t *testing.T) {
+	k := NewKeyer()
+	first := keyedEvent(time.Date(2019, time.December, 25, 17, 0, 0, 0, time.UTC))
+	_, err := k.Key(KindGame, first, nil)
+	assert.Nil(t, err)
+	_, err = k.Key(KindGame, first, nil)
+	assert.Nil(t, err, "the same game keyed twice")
+
+	nightcap := keyedEvent(time.Date(2019, time.December, 25, 23, 0, 0, 0, time.UTC))
+	nightcap.GameID = "0021900479"
+	key, err := k.Key(KindGame, nightcap, nil)
+	assert.Equal(t, ErrKeyCollision, err)
+	assert.Equal(t, "2019-12-25:BOS:TOR", key)
+	if assert.Len(t, k.Collisions(), 1) {
+		assert.Equal(t, KindGame, k.Collisions()[0].Kind)
+	}
+	_, err = k.Key(KindCompetitor, first.HomeTeam, first)
+	assert.Nil(t, err)
+	_, err = k.Key(KindCompetitor, nightcap.HomeTeam, nightcap)
+	assert.Equal(t, ErrKeyCollision, err, "the home team of both games")
+
+	assert.Len(t, k.Reset(), 2)
+	assert.Empty(t, k.Collisions())
+	_, err = k.Key(KindGame, nightcap, nil)
+	assert.Nil(t, err, "a new batch")
+
+	// the ESPN feed of a game the crosswalk links to the NBA one is the same game
+	nba := keyedEvent(time.Date(2019, time.December, 28, 0, 30, 0, 0, time.UTC))
+	nba.GameID = "0021900492"
+	espn := keyedEvent(time.Date(2019, time.December, 28, 0, 30, 0, 0, time.UTC))
+	espn.GameID = "401161592"
+	r := IdentityResolverFor(LeagueNBA)
+	_, err = r.ResolveGame(ProviderNBA, nba)
+	assert.Nil(t, err)
+	_, err = r.ResolveGame(ProviderESPN, espn)
+	assert.Nil(t, err)
+	_, err = k.Key(KindGame, nba, nil)
+	assert.Nil(t, err)
+	_, err = k.Key(KindGame, espn, nil)
+	assert.Nil(t, err)
+}
+
+func TestKeyCrossProvider(t *testing.T) {
+	DefaultKeyer().Reset()
+	start := time.Date(2020, time.January, 5, 0, 30, 0, 0, time.UTC)
+	nba := keyedEvent(start)
+	nba.GameID, nba.Provider = "0021900530", ProviderNBA
+	espn := keyedEvent(start)
+	espn.GameID, espn.Provider = "401161630", ProviderESPN
+	espn.VisitTeam.Team = &Team{TeamIDESPN: "2", Abbreviation: "BOS"}
+	espn.HomeTeam.Team = &Team{TeamIDESPN: "28", Abbreviation: "TOR"}
+
+	key, err := MasterIdentity(nba)
+	assert.Nil(t, err)
+	assert.Equal(t, "2020-01-04:BOS:TOR", key)
+	key, err = MasterIdentity(espn)
+	assert.Nil(t, err, "the ESPN feed of a game keyed from NBA")
+	assert.Equal(t, "2020-01-04:BOS:TOR", key)
+	assert.Equal(t, nba.HomeTeam.ID, espn.HomeTeam.ID)
+	assert.Empty(t, DefaultKeyer().Reset(), "no game or competitor collisions")
+
+	// a second game of one provider under the same key still collides
+	nightcap := keyedEvent(start.Add(3 * time.Hour))
+	nightcap.GameID, nightcap.Provider = "0021900531", ProviderNBA
+	_, err = MasterIdentity(nba)
+	assert.Nil(t, err)
+	_, err = MasterIdentity(nightcap)
+	assert.Equal(t, ErrKeyCollision, err)
+	assert.Len(t, DefaultKeyer().Reset(), 3, "the game and both competitors")
+}
+
+func TestRekeyEvents(t *testing.T) {
+	k := NewKeyer()
+	assert.NotNil(t, k.Register(KeyScheme{Kind: KindGame, Version: 1, Key: func(interface{}, *Event) (string, error) { return "", nil }}))
+	v2 := KeyScheme{Kind: KindGame, Version: 2, Format: "{league}:{v1 key}",
+		Key: func(v interface{}, _ *Event) (string, error) {
+			e := v.(*Event)
+			key, err := e.CanonicalKey()
+			return string(e.League) + ":" + key, err
+		}}
+	assert.Nil(t, k.Register(v2))
+	s, _ := k.Scheme(KindGame, 0)
+	assert.Equal(t, 2, s.Version)
+
+	events := []Event{*keyedEvent(time.Date(2019, time.December, 26, 0, 30, 0, 0, time.UTC))}
+	changes, err := k.RekeyEvents(events, KindGame, 1, 2)
+	assert.Nil(t, err)
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, "2019-12-25:BOS:TOR", changes[0].OldKey)
+		assert.Equal(t, "NBA:2019-12-25:BOS:TOR", changes[0].NewKey)
+	}
+	assert.Equal(t, "NBA:2019-12-25:BOS:TOR", events[0].ID)
+
+	changes, err = k.RekeyEvents(events, KindTeam, 1, 1)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 0, "unchanged keys aren't reported")
+	assert.Equal(t, "TOR:2020:2", events[0].HomeTeam.Team.ID)
+
+	table := KeyChangeTable{League: LeagueNBA, Changes: []*KeyChange{{Kind: KindGame, From: 1, To: 2}}}
+	var b bytes.Buffer
+	assert.Nil(t, table.marshalNBJSON(&b))
+	assert.Equal(t, "rekeyNBA", table.tableName())
+	assert.Len(t, k.Schemes(), 6)
+}
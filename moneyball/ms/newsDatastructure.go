@@ -0,0 +1,100 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// a NewsItem is an article from a provider's news feed, linked to the canonical teams, players and games it is
+// about so analysts can tag injuries and trades.  Feeds are fetched incrementally: NewsSince keeps the items
+// published after the last one seen and advances the high-water mark
+
+import (
+	"sort"
+	"time"
+)
+
+//NewsItem ...
+type NewsItem struct {
+	EntityID
+	League       League     `json:"league"`
+	Type         string     `json:"type,omitempty"` // "HeadlineNews", "Story", "Recap"
+	Headline     string     `json:"headline"`
+	Description  string     `json:"description,omitempty"`
+	Byline       string     `json:"byline,omitempty"`
+	Published    *time.Time `json:"published,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Link         string     `json:"link,omitempty"`    // web link to the article
+	Teams        []string   `json:"teams,omitempty"`   // canonical team ids, see TeamRegistry
+	Players      []string   `json:"players,omitempty"` // player ids
+	Games        []GameID   `json:"games,omitempty"`
+	Topics       []string   `json:"topics,omitempty"` // other categories e.g. "Injuries", "Trade"
+}
+
+//NewsFeed ... the news items of a league
+type NewsFeed struct {
+	League League      `json:"league"`
+	Items  []*NewsItem `json:"items"`
+}
+
+//AboutTeam reports whether the item is linked to the canonical team id
+func (n *NewsItem) AboutTeam(teamID string) bool {
+	return containsString(n.Teams, teamID)
+}
+
+//AboutPlayer reports whether the item is linked to the player id
+func (n *NewsItem) AboutPlayer(playerID string) bool {
+	return containsString(n.Players, playerID)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+//NewsSince returns the items published after since, oldest first, and the latest published time seen, which
+//is since when no item is newer.  Items without a published time are skipped
+func NewsSince(items []*NewsItem, since time.Time) ([]*NewsItem, time.Time) {
+	latest := since
+	fresh := []*NewsItem{}
+	for _, n := range items {
+		if n.Published == nil || !n.Published.After(since) {
+			continue
+		}
+		fresh = append(fresh, n)
+		if n.Published.After(latest) {
+			latest = *n.Published
+		}
+	}
+	sort.SliceStable(fresh, func(i, j int) bool { return fresh[i].Published.Before(*fresh[j].Published) })
+	return fresh, latest
+}
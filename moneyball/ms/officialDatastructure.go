@@ -0,0 +1,301 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// an Official is a referee known across providers, the OfficialRegistry resolves the officials of a game's crew
+// (GameOfficial) to one by NBA person_id, then by name.  The OfficiatingLog keeps every crew assignment with the
+// fouls and free throws of the game so the effect of a referee or a crew on foul rates and pace can be studied
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Official ... a referee
+type Official struct {
+	EntityID         // "scott-foster", see the KindOfficial key scheme
+	IDNBA     string `json:"idNBA,omitempty"`     // NBA person_id "1146"
+	Name      string `json:"name"`                // "Tony Brothers"
+	FirstName string `json:"firstName,omitempty"` // "Tony"
+	LastName  string `json:"lastName,omitempty"`  // "Brothers"
+	Jersey    string `json:"jersey,omitempty"`    // latest jersey number "25"
+}
+
+//OfficialRegistry ... canonical officials of a league
+type OfficialRegistry struct {
+	League    League
+	mu        sync.RWMutex
+	officials map[string]*Official // by canonical id
+	byNBAID   map[string]*Official
+}
+
+//NewOfficialRegistry returns an empty registry for league
+func NewOfficialRegistry(league League) *OfficialRegistry {
+	return &OfficialRegistry{League: league, officials: map[string]*Official{}, byNBAID: map[string]*Official{}}
+}
+
+var (
+	officialRegistries   = map[League]*OfficialRegistry{}
+	officialRegistriesMu sync.Mutex
+)
+
+//OfficialRegistryFor returns the shared registry of a league
+func OfficialRegistryFor(league League) *OfficialRegistry {
+	if league == "" {
+		league = LeagueNBA
+	}
+	officialRegistriesMu.Lock()
+	defer officialRegistriesMu.Unlock()
+	r, ok := officialRegistries[league]
+	if !ok {
+		r = NewOfficialRegistry(league)
+		officialRegistries[league] = r
+	}
+	return r
+}
+
+//Register resolves the official of a crew assignment by NBA id then canonical id, adds it when it is new, and
+//sets the assignment's OfficialID.  The name only resolves to an official when one of the two has no NBA id,
+//a namesake with another NBA id is a new official keyed "tony-brothers-1146"
+func (r *OfficialRegistry) Register(o *GameOfficial) (*Official, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	canon, ok := r.byNBAID[o.IDNBA]
+	if !ok || o.IDNBA == "" {
+		key, err := DefaultKeyer().Key(KindOfficial, o, nil)
+		if err != nil {
+			return nil, err
+		}
+		canon, ok = r.officials[key]
+		if ok && o.IDNBA != "" && canon.IDNBA != "" {
+			key, ok = key+"-"+o.IDNBA, false
+		}
+		if !ok {
+			canon = &Official{Name: o.Name}
+			canon.ID = key
+			if names := strings.Fields(o.Name); len(names) > 1 {
+				canon.FirstName = names[0]
+				canon.LastName = strings.Join(names[1:], " ")
+			}
+			r.officials[key] = canon
+		}
+	}
+	if canon.IDNBA == "" && o.IDNBA != "" {
+		canon.IDNBA = o.IDNBA
+		r.byNBAID[o.IDNBA] = canon
+	}
+	if o.Jersey != "" {
+		canon.Jersey = o.Jersey
+	}
+	o.OfficialID = canon.ID
+	return canon, nil
+}
+
+//ByID returns the official with canonical id, or nil
+func (r *OfficialRegistry) ByID(id string) *Official {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.officials[id]
+}
+
+//ByNBAID returns the official with NBA person_id, or nil
+func (r *OfficialRegistry) ByNBAID(id string) *Official {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.byNBAID[id]
+}
+
+//AttachOfficials sets the crew of the event, each official is resolved in the registry of the event's league
+func (e *Event) AttachOfficials(officials ...*GameOfficial) error {
+	registry := OfficialRegistryFor(e.League)
+	e.Officials = []*GameOfficial{}
+	for _, o := range officials {
+		if o == nil {
+			continue
+		}
+		if _, err := registry.Register(o); err != nil {
+			return err
+		}
+		e.Officials = append(e.Officials, o)
+	}
+	return nil
+}
+
+//OfficialAssignment ... an official working a game, with the game totals of both teams
+type OfficialAssignment struct {
+	OfficialID          string    `json:"officialId"` // "scott-foster"
+	League              League    `json:"league"`
+	GameID              GameID    `json:"gameId"`
+	GameKey             string    `json:"gameKey,omitempty"` // "2019-12-25:BOS:TOR"
+	Date                time.Time `json:"date"`
+	Position            string    `json:"position,omitempty"` // "Crew Chief"
+	Order               int       `json:"order,omitempty"`
+	Jersey              string    `json:"jersey,omitempty"`
+	Fouls               float64   `json:"fouls"`               // personal fouls of both teams
+	FreeThrowsAttempted float64   `json:"freeThrowsAttempted"` // of both teams
+}
+
+//OfficialHistory ... the games of an official, newest last, and the per game averages
+type OfficialHistory struct {
+	Official          *Official             `json:"official,omitempty"`
+	Games             []*OfficialAssignment `json:"games"`
+	FoulsPerGame      float64               `json:"foulsPerGame"`
+	FreeThrowsPerGame float64               `json:"freeThrowsPerGame"`
+}
+
+//OfficiatingLog ... the crew assignments of a league, persisted as their own table
+type OfficiatingLog struct {
+	League      League
+	mu          sync.Mutex
+	Assignments []*OfficialAssignment
+}
+
+//NewOfficiatingLog ...
+func NewOfficiatingLog(league League) *OfficiatingLog {
+	return &OfficiatingLog{League: league, Assignments: []*OfficialAssignment{}}
+}
+
+//gameFouls sums the personal fouls and free throw attempts of the teams of a game summary, ESPN gives free
+//throws as "freeThrowsMade-freeThrowsAttempted" "18-23"
+func gameFouls(gs *GameSummary) (float64, float64) {
+	var fouls, fta float64
+	if gs == nil {
+		return fouls, fta
+	}
+	for _, ts := range gs.Teams {
+		for _, k := range []string{"fouls", "pFouls", "PF"} {
+			if st := ts.Stat(k); st != nil {
+				fouls += statFloat(st.Value)
+				break
+			}
+		}
+		if st := ts.Stat("freeThrowsAttempted"); st != nil {
+			fta += statFloat(st.Value)
+		} else if st := ts.Stat("freeThrowsMade-freeThrowsAttempted"); st != nil {
+			if parts := strings.SplitN(statString(st.Value), "-", 2); len(parts) == 2 {
+				fta += statFloat(parts[1])
+			}
+		}
+	}
+	return fouls, fta
+}
+
+//eventFouls sums the personal fouls and free throw attempts of the competitors' GameStats, see
+//Event.AttachTeamGameStats
+func eventFouls(e *Event) (float64, float64) {
+	var fouls, fta float64
+	for _, c := range []*Competitor{e.HomeTeam, e.VisitTeam} {
+		if c == nil || c.GameStats == nil {
+			continue
+		}
+		fouls += float64(c.GameStats.PersonalFouls)
+		fta += float64(c.GameStats.FreeThrowsAttempted)
+	}
+	return fouls, fta
+}
+
+//Add logs the crew of the event, from the event's Officials or else the summary's, with the game's fouls and
+//free throws taken from the summary's team totals or, without a summary, the event's team stats.  Adding a game
+//again replaces the assignments of its officials rather than counting the game twice
+func (l *OfficiatingLog) Add(e *Event, gs *GameSummary) error {
+	officials := e.Officials
+	if len(officials) == 0 && gs != nil {
+		officials = gs.Officials
+	}
+	registry := OfficialRegistryFor(e.League)
+	key := e.EntityID.ID
+	if key == "" {
+		key, _ = e.CanonicalKey()
+	}
+	date := time.Time{}
+	if e.GameDetail != nil && e.GameDetail.StartTime != nil {
+		date = *e.GameDetail.StartTime
+	} else if day, err := e.GameDay(); err == nil {
+		date = day
+	}
+	fouls, fta := gameFouls(gs)
+	if gs == nil {
+		fouls, fta = eventFouls(e)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, o := range officials {
+		if o.OfficialID == "" {
+			if _, err := registry.Register(o); err != nil {
+				return err
+			}
+		}
+		a := &OfficialAssignment{OfficialID: o.OfficialID, League: e.League,
+			GameID: e.GameID, GameKey: key, Date: date, Position: o.Position, Order: o.Order, Jersey: o.Jersey,
+			Fouls: fouls, FreeThrowsAttempted: fta}
+		if i := l.assignment(key, o.OfficialID); i >= 0 {
+			l.Assignments[i] = a
+			continue
+		}
+		l.Assignments = append(l.Assignments, a)
+	}
+	return nil
+}
+
+//assignment is the index of the official's assignment to the game, or -1
+func (l *OfficiatingLog) assignment(gameKey string, officialID string) int {
+	for i, a := range l.Assignments {
+		if a.GameKey == gameKey && a.OfficialID == officialID {
+			return i
+		}
+	}
+	return -1
+}
+
+//History returns the games of an official in date order and the per game fouls and free throws
+func (l *OfficiatingLog) History(officialID string) *OfficialHistory {
+	h := OfficialHistory{Official: OfficialRegistryFor(l.League).ByID(officialID), Games: []*OfficialAssignment{}}
+	l.mu.Lock()
+	for _, a := range l.Assignments {
+		if a.OfficialID == officialID {
+			h.Games = append(h.Games, a)
+		}
+	}
+	l.mu.Unlock()
+	sort.SliceStable(h.Games, func(i, j int) bool { return h.Games[i].Date.Before(h.Games[j].Date) })
+	if len(h.Games) == 0 {
+		return &h
+	}
+	for _, g := range h.Games {
+		h.FoulsPerGame += g.Fouls
+		h.FreeThrowsPerGame += g.FreeThrowsAttempted
+	}
+	h.FoulsPerGame = h.FoulsPerGame / float64(len(h.Games))
+	h.FreeThrowsPerGame = h.FreeThrowsPerGame / float64(len(h.Games))
+	return &h
+}
@@ -0,0 +1,139 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func officiatedGame(id GameID, day int, crew ...*GameOfficial) (*Event, *GameSummary) {
+	start := time.Date(2020, time.June, day, 23, 0, 0, 0, time.UTC)
+	e := &Event{GameID: id, League: LeagueWNBA, GameDetail: &GameDetail{StartTime: &start},
+		VisitTeam: &Competitor{Abbreviation: "SEA"}, HomeTeam: &Competitor{Abbreviation: "LVA"}}
+	e.AttachOfficials(crew...)
+	gs := &GameSummary{GameID: id, League: LeagueWNBA, Teams: []*GameTeamStats{
+		{TeamID: "30", Stats: []*Stat{{Key: "fouls", Value: "18"}, {Key: "freeThrowsMade-freeThrowsAttempted", Value: "12-15"}}},
+		{TeamID: "17", Stats: []*Stat{{Key: "PF", Value: 22.0}, {Key: "freeThrowsAttempted", Value: 25.0}}}}}
+	return e, gs
+}
+
+func TestOfficialRegistry(t *testing.T) {
+	r := NewOfficialRegistry(LeagueWNBA)
+	byID := &GameOfficial{Name: "Tony Brothers", IDNBA: "1146", Jersey: "25"}
+	o, err := r.Register(byID)
+	assert.Nil(t, err)
+	assert.Equal(t, "tony-brothers", o.ID)
+	assert.Equal(t, "Brothers", o.LastName)
+	assert.Equal(t, "tony-brothers", byID.OfficialID)
+
+	// ESPN lists the same official without the NBA id
+	byName := &GameOfficial{Name: "Tony Brothers", Position: "Crew Chief", Order: 1}
+	same, err := r.Register(byName)
+	assert.Nil(t, err)
+	assert.True(t, o == same)
+	assert.True(t, o == r.ByNBAID("1146"))
+
+	// a display name change is caught by the NBA id
+	renamed := &GameOfficial{Name: "Anthony Brothers", IDNBA: "1146", Jersey: "26"}
+	same, _ = r.Register(renamed)
+	assert.True(t, o == same)
+	assert.Equal(t, "tony-brothers", renamed.OfficialID)
+	assert.Equal(t, "26", o.Jersey)
+
+	// a namesake with another NBA id is another official
+	namesake := &GameOfficial{Name: "Tony Brothers", IDNBA: "2001"}
+	other, err := r.Register(namesake)
+	assert.Nil(t, err)
+	assert.False(t, o == other)
+	assert.Equal(t, "tony-brothers-2001", namesake.OfficialID)
+	assert.True(t, other == r.ByNBAID("2001"))
+	assert.True(t, o == r.ByNBAID("1146"))
+
+	_, err = r.Register(&GameOfficial{})
+	assert.NotNil(t, err, "an official needs a name")
+	assert.Nil(t, r.ByID("scott-foster"))
+}
+
+func TestOfficiatingHistory(t *testing.T) {
+	log := NewOfficiatingLog(LeagueWNBA)
+	later, gs := officiatedGame("2", 12, &GameOfficial{Name: "Sue Blauch", Order: 1},
+		&GameOfficial{Name: "Tiara Cruse", Order: 2})
+	assert.Nil(t, log.Add(later, gs))
+	earlier, gs := officiatedGame("1", 10, &GameOfficial{Name: "Sue Blauch", Order: 2})
+	gs.Teams[1].Stats[0].Value = 30.0
+	assert.Nil(t, log.Add(earlier, gs))
+	// a crew only known from the summary
+	other, gs := officiatedGame("3", 11)
+	gs.Officials = []*GameOfficial{{Name: "Tiara Cruse"}}
+	assert.Nil(t, log.Add(other, gs))
+
+	h := log.History("sue-blauch")
+	if assert.Len(t, h.Games, 2) {
+		assert.Equal(t, GameID("1"), h.Games[0].GameID, "oldest game first")
+		assert.Equal(t, 2, h.Games[0].Order)
+		assert.Equal(t, 48.0, h.Games[0].Fouls)
+		assert.Equal(t, 40.0, h.Games[1].Fouls)
+		assert.Equal(t, 40.0, h.Games[1].FreeThrowsAttempted)
+	}
+	assert.Equal(t, 44.0, h.FoulsPerGame)
+	assert.Equal(t, 40.0, h.FreeThrowsPerGame)
+	if assert.NotNil(t, h.Official) {
+		assert.Equal(t, "Sue Blauch", h.Official.Name)
+	}
+	assert.Len(t, log.History("tiara-cruse").Games, 2)
+	assert.Empty(t, log.History("nobody").Games)
+
+	// adding a game again replaces its assignments
+	again, gs := officiatedGame("2", 12, &GameOfficial{Name: "Sue Blauch", Order: 1})
+	assert.Nil(t, log.Add(again, gs))
+	assert.Len(t, log.History("sue-blauch").Games, 2)
+
+	b := bytes.Buffer{}
+	assert.Nil(t, log.marshalNBJSON(&b))
+	assert.Equal(t, 4, bytes.Count(b.Bytes(), []byte("\n")))
+	assert.Equal(t, "officiatingWNBA", log.tableName())
+}
+
+func TestOfficiatingLogEventStats(t *testing.T) {
+	log := NewOfficiatingLog(LeagueWNBA)
+	e, _ := officiatedGame("4", 14, &GameOfficial{Name: "Sue Blauch"})
+	e.HomeTeam.GameStats = &TeamGameStats{PersonalFouls: 19, FreeThrowsAttempted: 21}
+	e.VisitTeam.GameStats = &TeamGameStats{PersonalFouls: 17, FreeThrowsAttempted: 24}
+	assert.Nil(t, log.Add(e, nil), "the NBA path has no summary")
+	if h := log.History("sue-blauch"); assert.Len(t, h.Games, 1) {
+		assert.Equal(t, 36.0, h.Games[0].Fouls, "from the event's team stats")
+		assert.Equal(t, 45.0, h.Games[0].FreeThrowsAttempted)
+	}
+}
@@ -0,0 +1,212 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// a PlayEvent is one row of game flow (play-by-play) for an Event,
+// the stream of PlayEvents for a game is ordered by Sequence and is the basis for
+// deriving possessions, lineups and stints downstream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//PlayEventType ... canonical classification of a play-by-play event across providers
+type PlayEventType string
+
+const (
+	//PlayShot is a field goal attempt, made or missed
+	PlayShot PlayEventType = "shot"
+	//PlayFreeThrow is a free throw attempt, made or missed
+	PlayFreeThrow PlayEventType = "freethrow"
+	//PlayRebound ...
+	PlayRebound PlayEventType = "rebound"
+	//PlayTurnover ...
+	PlayTurnover PlayEventType = "turnover"
+	//PlayFoul ...
+	PlayFoul PlayEventType = "foul"
+	//PlayViolation ...
+	PlayViolation PlayEventType = "violation"
+	//PlaySubstitution is a player entering for another player
+	PlaySubstitution PlayEventType = "substitution"
+	//PlayTimeout ...
+	PlayTimeout PlayEventType = "timeout"
+	//PlayJumpBall ...
+	PlayJumpBall PlayEventType = "jumpball"
+	//PlayEjection ...
+	PlayEjection PlayEventType = "ejection"
+	//PlayPeriodStart ...
+	PlayPeriodStart PlayEventType = "periodstart"
+	//PlayPeriodEnd ...
+	PlayPeriodEnd PlayEventType = "periodend"
+	//PlayOther is anything we don't (yet) classify e.g. instant replay
+	PlayOther PlayEventType = "other"
+)
+
+//ShotResult ... outcome of a shot or free throw
+type ShotResult string
+
+const (
+	//ShotMade ...
+	ShotMade ShotResult = "made"
+	//ShotMissed ...
+	ShotMissed ShotResult = "missed"
+)
+
+// roles that a player can have in a PlayEvent
+const (
+	//RoleShooter took the shot or free throw
+	RoleShooter = "shooter"
+	//RoleAssist assisted a made shot
+	RoleAssist = "assist"
+	//RoleBlock blocked a missed shot
+	RoleBlock = "block"
+	//RoleRebounder ...
+	RoleRebounder = "rebounder"
+	//RoleTurnover committed the turnover
+	RoleTurnover = "turnover"
+	//RoleSteal ...
+	RoleSteal = "steal"
+	//RoleFouler committed the foul
+	RoleFouler = "fouler"
+	//RoleFouled drew the foul
+	RoleFouled = "fouled"
+	//RoleSubIn is the player entering the game
+	RoleSubIn = "in"
+	//RoleSubOut is the player leaving the game
+	RoleSubOut = "out"
+	//RoleJumper is a participant in a jump ball
+	RoleJumper = "jumper"
+	//RolePossession gained possession from a jump ball
+	RolePossession = "possession"
+	//RolePrimary is the only/first player of events with no finer role e.g. violation
+	RolePrimary = "primary"
+)
+
+//PlayEventPlayer ... a player involved in a PlayEvent and the role they played
+type PlayEventPlayer struct {
+	PlayerID string `json:"playerId"`         // provider player id e.g. NBA personId "201935" or ESPN athlete id "3992"
+	Name     string `json:"name,omitempty"`   // e.g. "James Harden"
+	TeamID   string `json:"teamId,omitempty"` // provider team id
+	Role     string `json:"role"`             // one of the Role* constants
+}
+
+//ShotLocation ... court coordinates of a shot as given by the provider
+type ShotLocation struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+//FreeThrowAttempt ... "1 of 2" for a free throw in a trip to the line
+type FreeThrowAttempt struct {
+	Number int `json:"number"`
+	Of     int `json:"of"`
+}
+
+//PlayEvent ... canonical play-by-play event
+type PlayEvent struct {
+	EntityID
+	GameID       GameID             `json:"gameId"`
+	Sequence     int                `json:"sequence"`               // ordering of the event within the game
+	Period       int                `json:"period"`                 // 1..4 quarters, 5+ overtime
+	Clock        string             `json:"clock"`                  // game clock as displayed e.g. "11:44"
+	ClockSeconds float64            `json:"clockSeconds"`           // seconds remaining in the period
+	WallClock    *time.Time         `json:"wallClock,omitempty"`    // real world time of the event (UTC) when known
+	TeamID       string             `json:"teamId,omitempty"`       // provider team id the event is credited to
+	Players      []*PlayEventPlayer `json:"players,omitempty"`      // players involved
+	Type         PlayEventType      `json:"type"`                   // canonical event type
+	SubType      string             `json:"subType,omitempty"`      // provider action e.g. "Jump Shot", "Defensive Rebound"
+	Description  string             `json:"description,omitempty"`  // provider text
+	ShotResult   ShotResult         `json:"shotResult,omitempty"`   // for shots and free throws
+	ShotValue    int                `json:"shotValue,omitempty"`    // 1, 2 or 3
+	ShotLocation *ShotLocation      `json:"shotLocation,omitempty"` // where the provider gives it
+	FreeThrow    *FreeThrowAttempt  `json:"freeThrow,omitempty"`    // for free throws
+	HomeScore    int                `json:"homeScore"`              // home score after the event
+	VisitScore   int                `json:"visitScore"`             // visitor score after the event
+}
+
+//Player returns the first player in the event with the given role, or nil
+func (p *PlayEvent) Player(role string) *PlayEventPlayer {
+	for _, pl := range p.Players {
+		if pl.Role == role {
+			return pl
+		}
+	}
+	return nil
+}
+
+//ParseGameClock converts a provider game clock into seconds remaining in the period, supports
+//"11:44", "11:44.0", "44.5" and ISO8601 durations "PT11M44.00S"
+func ParseGameClock(clock string) (float64, error) {
+	c := strings.TrimSpace(clock)
+	if c == "" {
+		return 0.0, nil
+	}
+	if strings.HasPrefix(c, "PT") {
+		c = strings.TrimSuffix(strings.TrimPrefix(c, "PT"), "S")
+		minutes := 0.0
+		if i := strings.Index(c, "M"); i >= 0 {
+			m, err := strconv.ParseFloat(c[:i], 64)
+			if err != nil {
+				return 0.0, fmt.Errorf("invalid game clock %s", clock)
+			}
+			minutes = m
+			c = c[i+1:]
+		}
+		seconds := 0.0
+		if c != "" {
+			s, err := strconv.ParseFloat(c, 64)
+			if err != nil {
+				return 0.0, fmt.Errorf("invalid game clock %s", clock)
+			}
+			seconds = s
+		}
+		return minutes*60 + seconds, nil
+	}
+	parts := strings.Split(c, ":")
+	if len(parts) > 2 {
+		return 0.0, fmt.Errorf("invalid game clock %s", clock)
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("invalid game clock %s", clock)
+	}
+	if len(parts) == 2 {
+		minutes, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return 0.0, fmt.Errorf("invalid game clock %s", clock)
+		}
+		seconds = seconds + float64(minutes*60)
+	}
+	return seconds, nil
+}
@@ -0,0 +1,561 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// persisted records carry the version of their table's schema in SchemaVersionField, stamped as the NDJSON is
+// written.  Records written before an ms struct changed are brought up to the current struct through the
+// migrations of the SchemaRegistry, and StructSchema/CompareSchema diff a struct against the live BigQuery
+// table so a load that would drop or retype columns downstream queries use is refused (see InsertTable)
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+//SchemaVersionField is the column every persisted record carries its schema version in
+const SchemaVersionField = "schemaVersion"
+
+var (
+	//ErrUnknownSchema the table kind isn't registered
+	ErrUnknownSchema = errors.New("unknown schema")
+	//ErrNoMigration a record can't be brought up to the current version
+	ErrNoMigration = errors.New("no migration")
+	//ErrFutureSchema the record was written by a newer struct than this one
+	ErrFutureSchema = errors.New("record is newer than the schema")
+	//ErrSchemaIncompatible loading would break the live table's schema
+	ErrSchemaIncompatible = errors.New("schema is incompatible with the table")
+)
+
+//Migration upgrades a decoded record from one version to the next in place, numbers are json.Number
+type Migration func(record map[string]interface{}) error
+
+//TableSchema ... the current version and struct of the rows of a kind of table, and the migrations to it
+type TableSchema struct {
+	Kind       string       // table name without the league e.g. "boxscores"
+	Version    int          // current version, records without a version are version 0
+	Row        reflect.Type // struct of a row e.g. Event
+	migrations map[int]Migration
+}
+
+//SchemaRegistry ... the table schemas by kind
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]*TableSchema
+}
+
+//NewSchemaRegistry ...
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{schemas: map[string]*TableSchema{}}
+}
+
+//TableKind is the kind of a table name, the name without its league "boxscoresNBA" is "boxscores"
+func TableKind(tableName string) string {
+	return strings.TrimRight(tableName, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+}
+
+//Register sets the current version and row struct of a kind, row is a value or pointer of the struct
+func (r *SchemaRegistry) Register(kind string, version int, row interface{}) *TableSchema {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := reflect.TypeOf(row)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	ts, ok := r.schemas[kind]
+	if !ok {
+		ts = &TableSchema{Kind: kind, migrations: map[int]Migration{}}
+		r.schemas[kind] = ts
+	}
+	ts.Version, ts.Row = version, t
+	return ts
+}
+
+//AddMigration adds the migration of a kind's records from version from to from+1
+func (r *SchemaRegistry) AddMigration(kind string, from int, m Migration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ts, ok := r.schemas[kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSchema, kind)
+	}
+	if from < 0 || from >= ts.Version {
+		return fmt.Errorf("migration of %s from version %d past the current version %d", kind, from, ts.Version)
+	}
+	ts.migrations[from] = m
+	return nil
+}
+
+//Schema returns the schema of a kind
+func (r *SchemaRegistry) Schema(kind string) (*TableSchema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if ts, ok := r.schemas[kind]; ok {
+		return ts, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, kind)
+}
+
+//Kinds returns the registered kinds in order
+func (r *SchemaRegistry) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := []string{}
+	for k := range r.schemas {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+//RecordVersion returns the schema version a record was written with, 0 when it has none
+func RecordVersion(record map[string]interface{}) (int, error) {
+	switch v := record[SchemaVersionField].(type) {
+	case nil:
+		return 0, nil
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err
+	case float64:
+		return int(v), nil
+	case int:
+		return v, nil
+	}
+	return 0, fmt.Errorf("invalid %s %v", SchemaVersionField, record[SchemaVersionField])
+}
+
+//Upgrade brings a decoded record of a kind up to the current version and stamps it with the version
+func (r *SchemaRegistry) Upgrade(kind string, record map[string]interface{}) error {
+	ts, err := r.Schema(kind)
+	if err != nil {
+		return err
+	}
+	version, err := RecordVersion(record)
+	if err != nil {
+		return err
+	}
+	if version > ts.Version {
+		return fmt.Errorf("%w: %s version %d, current %d", ErrFutureSchema, kind, version, ts.Version)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for ; version < ts.Version; version++ {
+		m, ok := ts.migrations[version]
+		if !ok {
+			return fmt.Errorf("%w: %s from version %d", ErrNoMigration, kind, version)
+		}
+		if err := m(record); err != nil {
+			return fmt.Errorf("migrating %s from version %d: %w", kind, version, err)
+		}
+	}
+	record[SchemaVersionField] = ts.Version
+	return nil
+}
+
+//Decode upgrades one NDJSON record of a kind and decodes it into v, a pointer to the kind's row struct
+func (r *SchemaRegistry) Decode(kind string, line []byte, v interface{}) error {
+	record, err := decodeRecord(line)
+	if err != nil {
+		return err
+	}
+	if err := r.Upgrade(kind, record); err != nil {
+		return err
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+//MigrateNDJSON upgrades every record of a kind read from rd and writes them to w, returning how many changed version
+func (r *SchemaRegistry) MigrateNDJSON(kind string, rd io.Reader, w io.Writer) (int, error) {
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(w)
+	migrated := 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		record, err := decodeRecord(scanner.Bytes())
+		if err != nil {
+			return migrated, fmt.Errorf("line %d: %w", line, err)
+		}
+		from, _ := RecordVersion(record)
+		if err := r.Upgrade(kind, record); err != nil {
+			return migrated, fmt.Errorf("line %d: %w", line, err)
+		}
+		if v, _ := RecordVersion(record); v != from {
+			migrated++
+		}
+		if err := enc.Encode(record); err != nil {
+			return migrated, err
+		}
+	}
+	return migrated, scanner.Err()
+}
+
+//Stamp adds the kind's current version to every NDJSON record in b
+func (r *SchemaRegistry) Stamp(kind string, b *bytes.Buffer) error {
+	ts, err := r.Schema(kind)
+	if err != nil {
+		return err
+	}
+	field := []byte(`{"` + SchemaVersionField + `":` + strconv.Itoa(ts.Version))
+	stamped := bytes.Buffer{}
+	for _, line := range bytes.Split(b.Bytes(), []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] != '{' {
+			return fmt.Errorf("%s record is not an object: %.40s", kind, line)
+		}
+		stamped.Write(field)
+		if rest := bytes.TrimSpace(line[1:]); len(rest) > 0 && rest[0] != '}' {
+			stamped.WriteByte(',')
+		}
+		stamped.Write(line[1:])
+		stamped.WriteByte('\n')
+	}
+	b.Reset()
+	_, err = stamped.WriteTo(b)
+	return err
+}
+
+func decodeRecord(line []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	record := map[string]interface{}{}
+	if err := dec.Decode(&record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+var (
+	defaultSchemas     *SchemaRegistry
+	defaultSchemasOnce sync.Once
+)
+
+//DefaultSchemaRegistry ... the schemas of the tables written by this package
+func DefaultSchemaRegistry() *SchemaRegistry {
+	defaultSchemasOnce.Do(func() {
+		r := NewSchemaRegistry()
+		rows := map[string]interface{}{
+			"boxscores":     Event{},
+			"stints":        Stint{},
+			"standings":     Standing{},
+			"news":          NewsItem{},
+			"lines":         BettingLine{},
+			"broadcasts":    Broadcast{},
+			"dataquality":   DataQualityIssue{},
+			"teamgamestats": TeamGameStats{},
+			"series":        Series{},
+			"officiating":   OfficialAssignment{},
+			"rekey":         KeyChange{},
+			"crosswalk":     CrosswalkEntry{},
+		}
+		// version 1 is the first stamped version
+		migrations := map[string]Migration{
+			"boxscores":     migrateEventV1,
+			"teamgamestats": migrateTeamGameStatsV1,
+		}
+		for kind, row := range rows {
+			r.Register(kind, 1, row)
+			m, ok := migrations[kind]
+			if !ok {
+				m = unchanged
+			}
+			_ = r.AddMigration(kind, 0, m)
+		}
+		defaultSchemas = r
+	})
+	return defaultSchemas
+}
+
+// unchanged is the migration of a version whose records are the same as the previous version's
+func unchanged(record map[string]interface{}) error {
+	return nil
+}
+
+// migrateEventV1: StartDateEastern in GameDayFormat rather than the NBA's "20190930", and stats in the
+// value/text form of Stat.MarshalJSON
+func migrateEventV1(record map[string]interface{}) error {
+	if gd, ok := record["gameDetail"].(map[string]interface{}); ok {
+		if s, ok := gd["startDateEastern"].(string); ok && s != "" {
+			day, err := ParseGameDay(s)
+			if err != nil {
+				return err
+			}
+			gd["startDateEastern"] = FormatGameDay(day)
+		}
+	}
+	for _, side := range []string{"homeTeam", "visitTeam"} {
+		if c, ok := record[side].(map[string]interface{}); ok {
+			migrateStatsV1(c["statistics"])
+		}
+	}
+	return nil
+}
+
+// migrateTeamGameStatsV1: stats outside the vocabulary in the value/text form of Stat.MarshalJSON
+func migrateTeamGameStatsV1(record map[string]interface{}) error {
+	migrateStatsV1(record["other"])
+	return nil
+}
+
+// migrateStatsV1 moves stat values that aren't numbers e.g. "15-1" from "value" to "text"
+func migrateStatsV1(stats interface{}) {
+	list, ok := stats.([]interface{})
+	if !ok {
+		return
+	}
+	for _, s := range list {
+		stat, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch v := stat["value"].(type) {
+		case json.Number, float64:
+		case nil:
+			delete(stat, "value")
+		default:
+			stat["text"] = statString(v)
+			delete(stat, "value")
+		}
+	}
+}
+
+// persisted forms of the structs that marshal themselves
+var persistedForms = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(Stat{}): reflect.TypeOf(statJSON{}),
+}
+
+//StructSchema is the BigQuery schema NDJSON of the struct row loads as, by its json names, with the schema
+//version column.  Fields of interfaces and maps have no type (not checked)
+func StructSchema(row interface{}) bigquery.Schema {
+	t := reflect.TypeOf(row)
+	if rt, ok := row.(reflect.Type); ok {
+		t = rt
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	schema := bigquery.Schema{{Name: SchemaVersionField, Type: bigquery.IntegerFieldType}}
+	return append(schema, structFields(t, map[reflect.Type]bool{})...)
+}
+
+func structFields(t reflect.Type, path map[reflect.Type]bool) bigquery.Schema {
+	if form, ok := persistedForms[t]; ok {
+		t = form
+	}
+	path[t] = true
+	defer delete(path, t)
+	schema := bigquery.Schema{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		if tag[0] == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && tag[0] == "" && ft.Kind() == reflect.Struct {
+			schema = append(schema, structFields(ft, path)...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		name := tag[0]
+		if name == "" {
+			name = f.Name
+		}
+		field := fieldSchema(ft, path)
+		field.Name = name
+		for _, option := range tag[1:] {
+			if option == "string" {
+				field.Type = bigquery.StringFieldType
+			}
+		}
+		schema = append(schema, field)
+	}
+	return schema
+}
+
+func fieldSchema(t reflect.Type, path map[reflect.Type]bool) *bigquery.FieldSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	field := bigquery.FieldSchema{}
+	if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8 {
+		field.Repeated = true
+		t = t.Elem()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		field.Type = bigquery.TimestampFieldType
+	case t.Kind() == reflect.Struct:
+		if !path[t] {
+			field.Type = bigquery.RecordFieldType
+			field.Schema = structFields(t, path)
+		}
+	case t.Kind() == reflect.String:
+		field.Type = bigquery.StringFieldType
+	case t.Kind() == reflect.Bool:
+		field.Type = bigquery.BooleanFieldType
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		field.Type = bigquery.IntegerFieldType
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		field.Type = bigquery.FloatFieldType
+	case t.Kind() == reflect.Slice:
+		field.Type = bigquery.BytesFieldType
+	}
+	return &field
+}
+
+// schema change kinds of a SchemaDiff
+const (
+	//SchemaAdded the struct has a column the table doesn't, the load adds it
+	SchemaAdded = "added"
+	//SchemaRemoved the table has a column the struct no longer writes
+	SchemaRemoved = "removed"
+	//SchemaRetyped the column's type differs
+	SchemaRetyped = "type"
+	//SchemaMode the column is repeated in one and not the other
+	SchemaMode = "mode"
+)
+
+//SchemaDiff ... a difference between a struct's schema and a live table's
+type SchemaDiff struct {
+	Path     string             `json:"path"`   // "gameDetail.timeZone"
+	Change   string             `json:"change"` // "added", "removed", "type", "mode"
+	Want     bigquery.FieldType `json:"want,omitempty"`
+	Have     bigquery.FieldType `json:"have,omitempty"`
+	Breaking bool               `json:"breaking"` // queries of the live table would break
+}
+
+func (d *SchemaDiff) String() string {
+	switch d.Change {
+	case SchemaAdded:
+		return fmt.Sprintf("%s added (%s)", d.Path, d.Want)
+	case SchemaRemoved:
+		return fmt.Sprintf("%s removed (%s)", d.Path, d.Have)
+	case SchemaMode:
+		return fmt.Sprintf("%s repeated mode changed", d.Path)
+	}
+	return fmt.Sprintf("%s is %s, the table has %s", d.Path, d.Want, d.Have)
+}
+
+// types autodetect may give a column that are loaded from the same json
+var compatibleTypes = map[bigquery.FieldType][]bigquery.FieldType{
+	bigquery.FloatFieldType:     {bigquery.IntegerFieldType, bigquery.NumericFieldType},
+	bigquery.IntegerFieldType:   {bigquery.FloatFieldType, bigquery.NumericFieldType},
+	bigquery.StringFieldType:    {bigquery.DateFieldType, bigquery.TimeFieldType, bigquery.DateTimeFieldType, bigquery.TimestampFieldType},
+	bigquery.TimestampFieldType: {bigquery.StringFieldType},
+}
+
+func compatibleType(want bigquery.FieldType, have bigquery.FieldType) bool {
+	if want == "" || want == have {
+		return true
+	}
+	for _, t := range compatibleTypes[want] {
+		if t == have {
+			return true
+		}
+	}
+	return false
+}
+
+//CompareSchema diffs the schema a struct writes (StructSchema) against the live table's, ordered by path
+func CompareSchema(want bigquery.Schema, have bigquery.Schema) []*SchemaDiff {
+	diffs := compareSchema("", want, have)
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs
+}
+
+func compareSchema(prefix string, want bigquery.Schema, have bigquery.Schema) []*SchemaDiff {
+	diffs := []*SchemaDiff{}
+	live := map[string]*bigquery.FieldSchema{}
+	for _, f := range have {
+		live[strings.ToLower(f.Name)] = f
+	}
+	for _, w := range want {
+		path := prefix + w.Name
+		h, ok := live[strings.ToLower(w.Name)]
+		if !ok {
+			diffs = append(diffs, &SchemaDiff{Path: path, Change: SchemaAdded, Want: w.Type})
+			continue
+		}
+		delete(live, strings.ToLower(w.Name))
+		switch {
+		case w.Repeated != h.Repeated:
+			diffs = append(diffs, &SchemaDiff{Path: path, Change: SchemaMode, Want: w.Type, Have: h.Type, Breaking: true})
+		case w.Type == bigquery.RecordFieldType && h.Type == bigquery.RecordFieldType:
+			diffs = append(diffs, compareSchema(path+".", w.Schema, h.Schema)...)
+		case !compatibleType(w.Type, h.Type):
+			diffs = append(diffs, &SchemaDiff{Path: path, Change: SchemaRetyped, Want: w.Type, Have: h.Type, Breaking: true})
+		}
+	}
+	for _, h := range live {
+		diffs = append(diffs, &SchemaDiff{Path: prefix + h.Name, Change: SchemaRemoved, Have: h.Type, Breaking: true})
+	}
+	return diffs
+}
+
+//BreakingChanges returns the diffs that would break queries of the live table
+func BreakingChanges(diffs []*SchemaDiff) []*SchemaDiff {
+	breaking := []*SchemaDiff{}
+	for _, d := range diffs {
+		if d.Breaking {
+			breaking = append(breaking, d)
+		}
+	}
+	return breaking
+}
@@ -0,0 +1,194 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRegistryTables(t *testing.T) {
+	r := DefaultSchemaRegistry()
+	tables := []NBJson{
+		&ScoreBoard{Events: []Event{{League: LeagueNBA}}}, &StintTable{League: LeagueNBA},
+		&StandingsSnapshot{League: LeagueNBA}, &NewsFeed{League: LeagueNBA}, &BettingLineTable{League: LeagueNBA},
+		&BroadcastTable{League: LeagueNBA}, &DataQualityTable{League: LeagueNBA},
+		&TeamGameStatsTable{League: LeagueWNBA}, &SeriesTable{League: LeagueNBA}, &OfficiatingLog{League: LeagueNBA},
+		&KeyChangeTable{League: LeagueNBA}, NewIdentityResolver(LeagueNBA),
+	}
+	for _, table := range tables {
+		ts, err := r.Schema(TableKind(table.tableName()))
+		assert.Nil(t, err, table.tableName())
+		if err == nil {
+			assert.Equal(t, 1, ts.Version)
+		}
+	}
+	assert.Equal(t, "teamgamestats", TableKind("teamgamestatsWNBA"))
+	_, err := r.Schema("boxes")
+	assert.True(t, errors.Is(err, ErrUnknownSchema))
+}
+
+func TestSchemaStamp(t *testing.T) {
+	b := bytes.Buffer{}
+	sb := ScoreBoard{Events: []Event{{GameID: "1", League: LeagueNBA}, {GameID: "2", League: LeagueNBA}}}
+	assert.Nil(t, sb.marshalNBJSON(&b))
+	assert.Nil(t, DefaultSchemaRegistry().Stamp(TableKind(sb.tableName()), &b))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	for _, line := range lines {
+		assert.True(t, strings.HasPrefix(line, `{"schemaVersion":1,"id":`), line)
+	}
+
+	b = *bytes.NewBufferString("{}\n")
+	assert.Nil(t, DefaultSchemaRegistry().Stamp("news", &b))
+	assert.Equal(t, "{\"schemaVersion\":1}\n", b.String())
+}
+
+func TestSchemaUpgrade(t *testing.T) {
+	r := DefaultSchemaRegistry()
+	// written before versions, the nba day format and a record in "value"
+	old := `{"id":"2019-09-30:LAL:LAC","gameId":"0021900001","league":"NBA",` +
+		`"homeTeam":{"abbreviation":"LAC","score":112,"statistics":[{"key":"rec","longKey":"record","value":"15-1"},` +
+		`{"key":"reb","longKey":"rebounds","value":44}]},"visitTeam":{"abbreviation":"LAL","score":102},` +
+		`"gameDetail":{"startTimeUTC":"2019-10-01T02:30:00Z","startDateEastern":"20190930"}}`
+	e := Event{}
+	assert.Nil(t, r.Decode("boxscores", []byte(old), &e))
+	assert.Equal(t, "2019-09-30", e.GameDetail.StartDateEastern)
+	assert.Equal(t, "15-1", e.HomeTeam.Statistics[0].Value)
+	assert.Equal(t, 44.0, e.HomeTeam.Statistics[1].Value)
+	assert.Equal(t, 112, e.HomeTeam.Score)
+
+	out := bytes.Buffer{}
+	in := old + "\n\n" + `{"schemaVersion":1,"id":"x","gameId":"2"}` + "\n"
+	migrated, err := r.MigrateNDJSON("boxscores", strings.NewReader(in), &out)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, migrated, "the second record is current")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Contains(t, lines[0], `"startDateEastern":"2019-09-30"`)
+	assert.Contains(t, lines[0], `"text":"15-1"`)
+	assert.Contains(t, lines[0], `"gameId":"0021900001"`)
+
+	err = r.Decode("boxscores", []byte(`{"schemaVersion":2}`), &e)
+	assert.True(t, errors.Is(err, ErrFutureSchema))
+
+	r = NewSchemaRegistry()
+	r.Register("series", 2, &Series{})
+	assert.Nil(t, r.AddMigration("series", 0, unchanged))
+	assert.NotNil(t, r.AddMigration("series", 2, unchanged), "past the current version")
+	assert.True(t, errors.Is(r.AddMigration("news", 0, unchanged), ErrUnknownSchema))
+	err = r.Upgrade("series", map[string]interface{}{"id": "2019:1:MIL:ORL"})
+	assert.True(t, errors.Is(err, ErrNoMigration), "no migration from version 1")
+	record := map[string]interface{}{"id": "2019:1:MIL:ORL", SchemaVersionField: 2.0}
+	assert.Nil(t, r.Upgrade("series", record))
+	assert.Equal(t, 2, record[SchemaVersionField])
+}
+
+type schemaRow struct {
+	EntityID
+	Round   int           `json:"round"`
+	Name    string        `json:"name,omitempty"`
+	Count   int64         `json:"count,string"`
+	Start   *time.Time    `json:"start"`
+	Games   []*SeriesGame `json:"games"`
+	Stats   []*Stat       `json:"stats"`
+	Any     interface{}   `json:"any"`
+	Skipped string        `json:"-"`
+	hidden  int
+}
+
+func TestStructSchema(t *testing.T) {
+	schema := StructSchema(schemaRow{})
+	names := []string{}
+	for _, f := range schema {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"schemaVersion", "id", "extract_time", "extract_src", "round", "name", "count", "start",
+		"games", "stats", "any"}, names)
+	assert.Equal(t, bigquery.IntegerFieldType, schema[4].Type)
+	assert.Equal(t, bigquery.StringFieldType, schema[6].Type, "json ,string")
+	assert.Equal(t, bigquery.TimestampFieldType, schema[7].Type)
+	assert.True(t, schema[8].Repeated)
+	assert.Equal(t, bigquery.RecordFieldType, schema[8].Type)
+	assert.Equal(t, "seriesId", schema[8].Schema[0].Name)
+	stats := schema[9].Schema
+	assert.Equal(t, 4, len(stats), "the persisted form of a Stat")
+	assert.Equal(t, "value", stats[2].Name)
+	assert.Equal(t, bigquery.FloatFieldType, stats[2].Type)
+	assert.Equal(t, bigquery.FieldType(""), schema[10].Type, "interfaces aren't typed")
+}
+
+func TestCompareSchema(t *testing.T) {
+	want := StructSchema(&schemaRow{})
+	have := bigquery.Schema{
+		{Name: "schemaVersion", Type: bigquery.IntegerFieldType},
+		{Name: "id", Type: bigquery.StringFieldType},
+		{Name: "round", Type: bigquery.FloatFieldType},
+		{Name: "count", Type: bigquery.IntegerFieldType},
+		{Name: "start", Type: bigquery.TimestampFieldType},
+		{Name: "games", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "seriesId", Type: bigquery.StringFieldType},
+			{Name: "date", Type: bigquery.DateFieldType},
+			{Name: "game", Type: bigquery.StringFieldType},
+		}},
+		{Name: "stats", Type: bigquery.RecordFieldType, Repeated: true, Schema: bigquery.Schema{
+			{Name: "key", Type: bigquery.StringFieldType},
+		}},
+		{Name: "any", Type: bigquery.StringFieldType},
+		{Name: "oldColumn", Type: bigquery.StringFieldType},
+	}
+	diffs := CompareSchema(want, have)
+	changes := map[string]string{}
+	for _, d := range diffs {
+		changes[d.Path] = d.Change
+	}
+	assert.Equal(t, SchemaRetyped, changes["count"], "a string is not an integer")
+	assert.Equal(t, SchemaMode, changes["games"], "repeated in the struct")
+	assert.Equal(t, SchemaRemoved, changes["oldColumn"])
+	assert.Equal(t, SchemaAdded, changes["name"])
+	assert.Equal(t, SchemaAdded, changes["stats.value"])
+	_, ok := changes["round"]
+	assert.False(t, ok, "autodetect reads whole floats as integers")
+	_, ok = changes["any"]
+	assert.False(t, ok)
+
+	breaking := BreakingChanges(diffs)
+	assert.Equal(t, 3, len(breaking))
+	assert.Equal(t, "count is STRING, the table has INTEGER", breaking[0].String())
+	assert.Equal(t, "oldColumn removed (STRING)", breaking[2].String())
+	assert.Equal(t, 0, len(BreakingChanges(CompareSchema(want, nil))), "a new table")
+}
@@ -0,0 +1,419 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// the season calendar: a Season is a league's season named for the year it starts (2019 is the 2019-20 NBA
+// season, as the NBA names it, ESPN names it 2020) and a Stage of it.  Stages are the NBA's seasonStageId, other
+// encodings map to them: ESPN season types (StageFromESPN), NBA stats season ids "22019" (SeasonFromNBAStatsID)
+// and the digits of an NBA game id "0041900123" (SeasonFromNBAGameID).  A SeasonCalendar dates the stages of a
+// season and lists its game days, SeasonCalendars answer which season and stage a date is in
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//Stage ... part of a season, the value of Season.SeasonStage
+type Stage int
+
+const (
+	//StageUnknown ...
+	StageUnknown Stage = iota
+	//StagePreseason ...
+	StagePreseason
+	//StageRegular is the regular season
+	StageRegular
+	//StageAllStar ...
+	StageAllStar
+	//StagePlayoffs ...
+	StagePlayoffs
+	//StagePlayIn is the tournament for the last playoff seeds
+	StagePlayIn
+	//StageOffseason ...
+	StageOffseason
+)
+
+var stageNames = []string{"unknown", "preseason", "regular", "allStar", "playoffs", "playIn", "offseason"}
+
+func (s Stage) String() string {
+	if s < 0 || int(s) >= len(stageNames) {
+		return stageNames[StageUnknown]
+	}
+	return stageNames[s]
+}
+
+//StageFromNBA maps an NBA seasonStageId, which Stage follows
+func StageFromNBA(id int) Stage {
+	if id < int(StagePreseason) || id > int(StagePlayIn) {
+		return StageUnknown
+	}
+	return Stage(id)
+}
+
+//StageFromESPN maps an ESPN season type: 1 preseason, 2 regular season, 3 postseason, 4 off season, 5 play-in
+func StageFromESPN(seasonType int) Stage {
+	switch seasonType {
+	case 1:
+		return StagePreseason
+	case 2:
+		return StageRegular
+	case 3:
+		return StagePlayoffs
+	case 4:
+		return StageOffseason
+	case 5:
+		return StagePlayIn
+	}
+	return StageUnknown
+}
+
+//SeasonFromNBAStatsID maps a stats.nba.com season id, the stage digit then the year "22019" or 42015
+func SeasonFromNBAStatsID(id string) (Season, error) {
+	if len(id) != 5 {
+		return Season{}, fmt.Errorf("invalid NBA season id %s", id)
+	}
+	year, err := strconv.Atoi(id[1:])
+	if err != nil {
+		return Season{}, fmt.Errorf("invalid NBA season id %s", id)
+	}
+	return Season{SeasonYear: year, SeasonStage: int(StageFromNBA(int(id[0] - '0')))}, nil
+}
+
+//SeasonFromNBAGameID reads the season of an NBA game id "0021900478", "00" then the stage digit and the year
+func SeasonFromNBAGameID(id string) (Season, error) {
+	if len(id) != 10 || id[:2] != "00" {
+		return Season{}, fmt.Errorf("invalid NBA game id %s", id)
+	}
+	year, err := strconv.Atoi(id[3:5])
+	if err != nil {
+		return Season{}, fmt.Errorf("invalid NBA game id %s", id)
+	}
+	// two digit years, the NBA's data starts in 1946
+	if year > 45 {
+		year += 1900
+	} else {
+		year += 2000
+	}
+	return Season{SeasonYear: year, SeasonStage: int(StageFromNBA(int(id[2] - '0')))}, nil
+}
+
+//Stage ...
+func (s Season) Stage() Stage {
+	return Stage(s.SeasonStage)
+}
+
+//SpansYears the league's seasons run over new year e.g. the NBA's October to June
+func (l League) SpansYears() bool {
+	switch l {
+	case LeagueNBA, LeagueNCAAM, LeagueNCAAW, LeagueNHL, LeagueEPL:
+		return true
+	}
+	return false
+}
+
+//DisplayYear "2019-20" for leagues whose seasons run over new year, else "2019"
+func (s Season) DisplayYear(league League) string {
+	if league.SpansYears() {
+		return fmt.Sprintf("%d-%02d", s.SeasonYear, (s.SeasonYear+1)%100)
+	}
+	return strconv.Itoa(s.SeasonYear)
+}
+
+//PlayoffGame ... where a playoff game sits in the bracket
+type PlayoffGame struct {
+	Round  int `json:"round"`  // 1 is the first round
+	Series int `json:"series"` // the series of the round, numbered from 0
+	Game   int `json:"game"`   // game of the series, 1 based
+}
+
+//PlayoffGameFromNBAGameID reads round, series and game from the last digits of an NBA playoff game id e.g.
+//"0041900123" is round 1, series 2, game 3, false for other games
+func PlayoffGameFromNBAGameID(id string) (PlayoffGame, bool) {
+	season, err := SeasonFromNBAGameID(id)
+	if err != nil || season.Stage() != StagePlayoffs {
+		return PlayoffGame{}, false
+	}
+	return PlayoffGame{Round: int(id[7] - '0'), Series: int(id[8] - '0'), Game: int(id[9] - '0')}, true
+}
+
+//PlayoffRoundName ... "Conference Finals"
+func PlayoffRoundName(league League, round int) string {
+	rounds := map[League][]string{
+		LeagueNBA:  {"First Round", "Conference Semifinals", "Conference Finals", "Finals"},
+		LeagueWNBA: {"First Round", "Second Round", "Semifinals", "Finals"},
+	}
+	names, ok := rounds[league]
+	if !ok || round < 1 || round > len(names) {
+		return fmt.Sprintf("Round %d", round)
+	}
+	return names[round-1]
+}
+
+//StageDates ... the first and last day of a stage, dates are midnight UTC as for StandingDate
+type StageDates struct {
+	Stage Stage     `json:"stage"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+//Contains the date (of t) is in the stage
+func (sd StageDates) Contains(t time.Time) bool {
+	day := StandingDate(t)
+	return !day.Before(sd.Start) && !day.After(sd.End)
+}
+
+//SeasonCalendar ... the dated stages and the game days of a league's season
+type SeasonCalendar struct {
+	League   League       `json:"league"`
+	Season   int          `json:"season"` // SeasonYear
+	Stages   []StageDates `json:"stages"`
+	GameDays []time.Time  `json:"gameDays,omitempty"` // sorted, midnight UTC
+}
+
+//Start is the first day of the first stage
+func (c *SeasonCalendar) Start() time.Time {
+	start := time.Time{}
+	for _, sd := range c.Stages {
+		if start.IsZero() || sd.Start.Before(start) {
+			start = sd.Start
+		}
+	}
+	return start
+}
+
+//End is the last day of the last stage
+func (c *SeasonCalendar) End() time.Time {
+	end := time.Time{}
+	for _, sd := range c.Stages {
+		if sd.End.After(end) {
+			end = sd.End
+		}
+	}
+	return end
+}
+
+//Contains the date is between the start and end of the season
+func (c *SeasonCalendar) Contains(t time.Time) bool {
+	day := StandingDate(t)
+	return len(c.Stages) > 0 && !day.Before(c.Start()) && !day.After(c.End())
+}
+
+//StageOn returns the stage of a date, the shortest stage containing it so the all-star break is found within
+//the regular season, a date between stages belongs to the stage before it
+func (c *SeasonCalendar) StageOn(t time.Time) (Stage, bool) {
+	if !c.Contains(t) {
+		return StageUnknown, false
+	}
+	day := StandingDate(t)
+	var found *StageDates
+	for i, sd := range c.Stages {
+		if sd.Contains(day) && (found == nil || sd.End.Sub(sd.Start) < found.End.Sub(found.Start)) {
+			found = &c.Stages[i]
+		}
+	}
+	if found == nil {
+		for i, sd := range c.Stages {
+			if sd.End.Before(day) && (found == nil || sd.End.After(found.End)) {
+				found = &c.Stages[i]
+			}
+		}
+	}
+	return found.Stage, true
+}
+
+//Dates returns the dates of a stage
+func (c *SeasonCalendar) Dates(stage Stage) (StageDates, bool) {
+	for _, sd := range c.Stages {
+		if sd.Stage == stage {
+			return sd, true
+		}
+	}
+	return StageDates{}, false
+}
+
+//AddGameDays adds the days games are played, days are kept sorted and once
+func (c *SeasonCalendar) AddGameDays(days ...time.Time) {
+	for _, t := range days {
+		day := StandingDate(t)
+		i := sort.Search(len(c.GameDays), func(i int) bool { return !c.GameDays[i].Before(day) })
+		if i < len(c.GameDays) && c.GameDays[i].Equal(day) {
+			continue
+		}
+		c.GameDays = append(c.GameDays, time.Time{})
+		copy(c.GameDays[i+1:], c.GameDays[i:])
+		c.GameDays[i] = day
+	}
+}
+
+//AddGames adds the game days of the events of the season
+func (c *SeasonCalendar) AddGames(events ...*Event) {
+	for _, e := range events {
+		if day, err := e.GameDay(); err == nil && c.Contains(day) {
+			c.AddGameDays(day)
+		}
+	}
+}
+
+//GameDaysIn returns the game days of a stage, StageUnknown for all of them
+func (c *SeasonCalendar) GameDaysIn(stage Stage) []time.Time {
+	days := []time.Time{}
+	for _, day := range c.GameDays {
+		if on, _ := c.StageOn(day); stage == StageUnknown || on == stage {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+//IsGameDay ...
+func (c *SeasonCalendar) IsGameDay(t time.Time) bool {
+	day := StandingDate(t)
+	i := sort.Search(len(c.GameDays), func(i int) bool { return !c.GameDays[i].Before(day) })
+	return i < len(c.GameDays) && c.GameDays[i].Equal(day)
+}
+
+//stageTemplate ... a stage of a default calendar, months and days of the first and last day, a month before the
+//season's start month falls in the next year
+type stageTemplate struct {
+	stage      Stage
+	startMonth time.Month
+	startDay   int
+	endMonth   time.Month
+	endDay     int
+	since      int // first season with the stage, 0 for always
+}
+
+var defaultStages = map[League][]stageTemplate{
+	LeagueNBA: {
+		{StagePreseason, time.October, 1, time.October, 20, 0},
+		{StageRegular, time.October, 21, time.April, 14, 0},
+		{StageAllStar, time.February, 14, time.February, 16, 0},
+		{StagePlayIn, time.April, 15, time.April, 19, 2020},
+		{StagePlayoffs, time.April, 20, time.June, 20, 0},
+	},
+	LeagueWNBA: {
+		{StagePreseason, time.May, 1, time.May, 15, 0},
+		{StageRegular, time.May, 16, time.September, 15, 0},
+		{StagePlayoffs, time.September, 16, time.October, 20, 0},
+	},
+	LeagueNCAAM: {
+		{StageRegular, time.November, 1, time.March, 15, 0},
+		{StagePlayoffs, time.March, 16, time.April, 10, 0},
+	},
+	LeagueNCAAW: {
+		{StageRegular, time.November, 1, time.March, 15, 0},
+		{StagePlayoffs, time.March, 16, time.April, 10, 0},
+	},
+}
+
+//DefaultSeasonCalendar returns a calendar of a season from the usual dates of the league's stages, without game
+//days.  Seasons are rescheduled (the NBA's 2019-20 ran to October) so prefer calendars from the providers
+func DefaultSeasonCalendar(league League, seasonYear int) (*SeasonCalendar, error) {
+	templates, ok := defaultStages[league]
+	if !ok {
+		return nil, fmt.Errorf("no default calendar for league %s", league)
+	}
+	first := templates[0].startMonth
+	date := func(m time.Month, d int) time.Time {
+		year := seasonYear
+		if m < first {
+			year++
+		}
+		return time.Date(year, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	c := SeasonCalendar{League: league, Season: seasonYear}
+	for _, st := range templates {
+		if st.since > seasonYear {
+			continue
+		}
+		c.Stages = append(c.Stages, StageDates{Stage: st.stage, Start: date(st.startMonth, st.startDay), End: date(st.endMonth, st.endDay)})
+	}
+	return &c, nil
+}
+
+//SeasonCalendars ... the calendars of the leagues' seasons
+type SeasonCalendars struct {
+	mu        sync.RWMutex
+	calendars map[League][]*SeasonCalendar
+}
+
+//NewSeasonCalendars ...
+func NewSeasonCalendars() *SeasonCalendars {
+	return &SeasonCalendars{calendars: map[League][]*SeasonCalendar{}}
+}
+
+//Add adds a calendar, replacing the calendar of the same league and season
+func (sc *SeasonCalendars) Add(c *SeasonCalendar) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	calendars := sc.calendars[c.League]
+	for i, old := range calendars {
+		if old.Season == c.Season {
+			calendars[i] = c
+			return
+		}
+	}
+	sc.calendars[c.League] = append(calendars, c)
+}
+
+//Calendar returns the calendar of a season, the default calendar when none was added
+func (sc *SeasonCalendars) Calendar(league League, seasonYear int) (*SeasonCalendar, error) {
+	sc.mu.RLock()
+	for _, c := range sc.calendars[league] {
+		if c.Season == seasonYear {
+			sc.mu.RUnlock()
+			return c, nil
+		}
+	}
+	sc.mu.RUnlock()
+	return DefaultSeasonCalendar(league, seasonYear)
+}
+
+//SeasonOn returns the season and stage of a date, a date between seasons is in the offseason of the season that
+//ended
+func (sc *SeasonCalendars) SeasonOn(league League, t time.Time) (Season, error) {
+	for _, year := range []int{t.Year(), t.Year() - 1} {
+		c, err := sc.Calendar(league, year)
+		if err != nil {
+			return Season{}, err
+		}
+		if stage, ok := c.StageOn(t); ok {
+			return Season{SeasonYear: year, SeasonStage: int(stage)}, nil
+		}
+		if !StandingDate(t).Before(c.Start()) {
+			return Season{SeasonYear: year, SeasonStage: int(StageOffseason)}, nil
+		}
+	}
+	return Season{SeasonYear: t.Year() - 1, SeasonStage: int(StageOffseason)}, nil
+}
@@ -0,0 +1,119 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStageMappings(t *testing.T) {
+	assert.Equal(t, StagePlayoffs, StageFromNBA(4))
+	assert.Equal(t, StageUnknown, StageFromNBA(9))
+	assert.Equal(t, StagePlayoffs, StageFromESPN(3))
+	assert.Equal(t, StagePlayIn, StageFromESPN(5))
+	assert.Equal(t, "allStar", StageAllStar.String())
+
+	season, err := SeasonFromNBAStatsID("42015")
+	assert.Nil(t, err)
+	assert.Equal(t, Season{SeasonYear: 2015, SeasonStage: int(StagePlayoffs)}, season)
+	_, err = SeasonFromNBAStatsID("2019")
+	assert.NotNil(t, err)
+
+	season, err = SeasonFromNBAGameID("0021900478")
+	assert.Nil(t, err)
+	assert.Equal(t, Season{SeasonYear: 2019, SeasonStage: int(StageRegular)}, season)
+	assert.Equal(t, "2019-20", season.DisplayYear(LeagueNBA))
+	assert.Equal(t, "2019", season.DisplayYear(LeagueWNBA))
+	season, _ = SeasonFromNBAGameID("0049800001")
+	assert.Equal(t, 1998, season.SeasonYear)
+
+	pg, ok := PlayoffGameFromNBAGameID("0041900123")
+	assert.True(t, ok)
+	assert.Equal(t, PlayoffGame{Round: 1, Series: 2, Game: 3}, pg)
+	_, ok = PlayoffGameFromNBAGameID("0021900123")
+	assert.False(t, ok)
+	assert.Equal(t, "Conference Finals", PlayoffRoundName(LeagueNBA, 3))
+	assert.Equal(t, "Round 2", PlayoffRoundName(LeagueNHL, 2))
+}
+
+func TestSeasonCalendar(t *testing.T) {
+	c, err := DefaultSeasonCalendar(LeagueNBA, 2019)
+	assert.Nil(t, err)
+	assert.Equal(t, day(2019, time.October, 1), c.Start())
+	assert.Equal(t, day(2020, time.June, 20), c.End())
+	_, ok := c.Dates(StagePlayIn)
+	assert.False(t, ok, "the play-in starts with the 2020-21 season")
+
+	stage, _ := c.StageOn(time.Date(2020, time.February, 15, 20, 0, 0, 0, time.UTC))
+	assert.Equal(t, StageAllStar, stage, "the all-star break is within the regular season")
+	stage, _ = c.StageOn(day(2020, time.April, 17))
+	assert.Equal(t, StageRegular, stage, "between the regular season and the playoffs")
+	_, ok = c.StageOn(day(2020, time.July, 1))
+	assert.False(t, ok)
+
+	c.AddGameDays(day(2019, time.October, 22), day(2020, time.April, 25), time.Date(2019, time.October, 22, 23, 30, 0, 0, time.UTC))
+	start := time.Date(2019, time.December, 25, 17, 0, 0, 0, time.UTC)
+	c.AddGames(&Event{GameDetail: &GameDetail{StartTime: &start}})
+	assert.Len(t, c.GameDays, 3)
+	assert.True(t, c.IsGameDay(day(2019, time.December, 25)))
+	assert.Equal(t, []time.Time{day(2020, time.April, 25)}, c.GameDaysIn(StagePlayoffs))
+	assert.Len(t, c.GameDaysIn(StageUnknown), 3)
+
+	_, err = DefaultSeasonCalendar(LeagueMLS, 2019)
+	assert.NotNil(t, err)
+}
+
+func TestSeasonCalendarsSeasonOn(t *testing.T) {
+	sc := NewSeasonCalendars()
+	// the 2019-20 season was suspended and finished in the bubble
+	sc.Add(&SeasonCalendar{League: LeagueNBA, Season: 2019, Stages: []StageDates{
+		{StageRegular, day(2019, time.October, 22), day(2020, time.August, 14)},
+		{StagePlayoffs, day(2020, time.August, 17), day(2020, time.October, 11)}}})
+
+	season, err := sc.SeasonOn(LeagueNBA, day(2020, time.September, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, Season{SeasonYear: 2019, SeasonStage: int(StagePlayoffs)}, season)
+	season, _ = sc.SeasonOn(LeagueNBA, day(2019, time.January, 10))
+	assert.Equal(t, Season{SeasonYear: 2018, SeasonStage: int(StageRegular)}, season, "the default calendar")
+	season, _ = sc.SeasonOn(LeagueNBA, day(2019, time.July, 10))
+	assert.Equal(t, Season{SeasonYear: 2018, SeasonStage: int(StageOffseason)}, season)
+	season, _ = sc.SeasonOn(LeagueWNBA, day(2020, time.July, 10))
+	assert.Equal(t, Season{SeasonYear: 2020, SeasonStage: int(StageRegular)}, season)
+	_, err = sc.SeasonOn(LeagueMLS, day(2020, time.July, 10))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,283 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// a Series is a playoff series between two teams assembled from the playoff stage events of a season
+// (BuildSeries).  Games are numbered within the series and flagged when a team could be eliminated and when the
+// series was clinched, so game 7s, closeout games and comebacks can be queried from the SeriesTable
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// states of a Series
+const (
+	//SeriesScheduled no game has been played
+	SeriesScheduled = "scheduled"
+	//SeriesInProgress ...
+	SeriesInProgress = "inProgress"
+	//SeriesComplete a team has won
+	SeriesComplete = "complete"
+)
+
+//SeriesGame ... a game of a series, wins are the series score after the game
+type SeriesGame struct {
+	SeriesID       string    `json:"seriesId"` // "2019:2:MIL:MIA"
+	GameID         GameID    `json:"gameId"`
+	Game           int       `json:"game"` // 1 based number in the series
+	Date           time.Time `json:"date"` // game day, see Event.GameDay
+	Home           string    `json:"home"` // abbreviation
+	Visit          string    `json:"visit"`
+	HomeScore      int       `json:"homeScore,omitempty"`
+	VisitScore     int       `json:"visitScore,omitempty"`
+	Played         bool      `json:"played"`
+	Winner         string    `json:"winner,omitempty"`
+	HigherSeedWins int       `json:"higherSeedWins"`
+	LowerSeedWins  int       `json:"lowerSeedWins"`
+	IsElimination  bool      `json:"isElimination"` // a team would be eliminated by losing
+	IsClinch       bool      `json:"isClinch"`      // the winner won the series
+	IsDecider      bool      `json:"isDecider"`     // the last possible game e.g. game 7
+}
+
+//Series ... a playoff series
+type Series struct {
+	EntityID                     // "2019:2:MIL:MIA" season, round, higher seed, lower seed see SeriesID
+	League         League        `json:"league"`
+	Season         Season        `json:"season"`
+	Round          int           `json:"round"`                // 1 is the first round
+	RoundName      string        `json:"roundName,omitempty"`  // "Conference Semifinals"
+	Number         int           `json:"number,omitempty"`     // series of the round from NBA game ids, see PlayoffGame
+	Conference     string        `json:"conference,omitempty"` // "East", empty for the finals or when unknown
+	HigherSeed     string        `json:"higherSeed"`           // abbreviation of the team with home court
+	LowerSeed      string        `json:"lowerSeed"`
+	HigherSeedRank int           `json:"higherSeedRank,omitempty"` // conference rank when standings are known
+	LowerSeedRank  int           `json:"lowerSeedRank,omitempty"`
+	BestOf         int           `json:"bestOf"`
+	Games          []*SeriesGame `json:"games"`
+	HigherSeedWins int           `json:"higherSeedWins"`
+	LowerSeedWins  int           `json:"lowerSeedWins"`
+	State          string        `json:"state"`            // "scheduled", "inProgress", "complete"
+	Winner         string        `json:"winner,omitempty"` // abbreviation
+}
+
+//SeriesID keys a series by season, round and the seeds e.g. "2019:2:MIL:MIA"
+func SeriesID(season Season, round int, higherSeed string, lowerSeed string) string {
+	return strings.Join([]string{strconv.Itoa(season.SeasonYear), strconv.Itoa(round), higherSeed, lowerSeed}, ":")
+}
+
+//WinsNeeded ... 4 of a best of 7
+func (s *Series) WinsNeeded() int {
+	return s.BestOf/2 + 1
+}
+
+//Game returns the game with number n, or nil
+func (s *Series) Game(n int) *SeriesGame {
+	for _, g := range s.Games {
+		if g.Game == n {
+			return g
+		}
+	}
+	return nil
+}
+
+//Loser ... the abbreviation of the eliminated team, empty until the series is complete
+func (s *Series) Loser() string {
+	switch s.Winner {
+	case "":
+		return ""
+	case s.HigherSeed:
+		return s.LowerSeed
+	}
+	return s.HigherSeed
+}
+
+//BestOf returns the length of the league's playoff series
+func (l League) BestOf() int {
+	switch l {
+	case LeagueWNBA:
+		return 5
+	case LeagueNCAAM, LeagueNCAAW, LeagueNFL, LeagueNCAAF, LeagueMLS, LeagueEPL:
+		return 1
+	}
+	return 7
+}
+
+//playedWinner is the abbreviation of the winner of a finished game, empty for a game not (fully) played
+func playedWinner(e *Event) string {
+	if e.Status != nil && e.Status.State != "" && e.Status.State != "post" {
+		return ""
+	}
+	if e.HomeTeam.Score == e.VisitTeam.Score {
+		return ""
+	}
+	if e.HomeTeam.Score > e.VisitTeam.Score {
+		return e.HomeTeam.Abbreviation
+	}
+	return e.VisitTeam.Abbreviation
+}
+
+//BuildSeries assembles the series of the playoff stage events, events of other stages are skipped.  The higher
+//seed is the better conference rank of standings when given (nil otherwise) else the home team of game 1, the
+//round is the NBA game id's or else counted from the teams' earlier series
+func BuildSeries(league League, events []*Event, standings *StandingsSnapshot) []*Series {
+	playoffs := []*Event{}
+	for _, e := range events {
+		if e.Season.Stage() == StagePlayoffs && e.HomeTeam != nil && e.VisitTeam != nil {
+			playoffs = append(playoffs, e)
+		}
+	}
+	days := map[*Event]time.Time{}
+	for _, e := range playoffs {
+		days[e], _ = e.GameDay()
+	}
+	sort.SliceStable(playoffs, func(i, j int) bool { return days[playoffs[i]].Before(days[playoffs[j]]) })
+
+	// group by season and pair of teams, in order of the first game
+	type pairing struct {
+		season int
+		teams  string
+	}
+	byPair := map[pairing]*Series{}
+	all := []*Series{}
+	for _, e := range playoffs {
+		teams := []string{e.HomeTeam.Abbreviation, e.VisitTeam.Abbreviation}
+		sort.Strings(teams)
+		p := pairing{e.Season.SeasonYear, strings.Join(teams, ":")}
+		s, ok := byPair[p]
+		if !ok {
+			s = &Series{League: league, Season: e.Season, HigherSeed: e.HomeTeam.Abbreviation,
+				LowerSeed: e.VisitTeam.Abbreviation, BestOf: league.BestOf()}
+			byPair[p] = s
+			all = append(all, s)
+		}
+		if pg, ok := PlayoffGameFromNBAGameID(string(e.GameID)); ok {
+			s.Round, s.Number = pg.Round, pg.Series
+		}
+		g := SeriesGame{GameID: e.GameID, Date: days[e], Home: e.HomeTeam.Abbreviation, Visit: e.VisitTeam.Abbreviation,
+			HomeScore: e.HomeTeam.Score, VisitScore: e.VisitTeam.Score}
+		g.Winner = playedWinner(e)
+		g.Played = g.Winner != ""
+		s.Games = append(s.Games, &g)
+	}
+
+	// rounds not known from game ids follow the teams' earlier series of the season
+	roundOf := map[string]int{}
+	for _, s := range all {
+		higher := strconv.Itoa(s.Season.SeasonYear) + ":" + s.HigherSeed
+		lower := strconv.Itoa(s.Season.SeasonYear) + ":" + s.LowerSeed
+		if s.Round == 0 {
+			s.Round = roundOf[higher] + 1
+			if roundOf[lower] >= s.Round {
+				s.Round = roundOf[lower] + 1
+			}
+		}
+		roundOf[higher], roundOf[lower] = s.Round, s.Round
+	}
+	for _, s := range all {
+		s.seed(standings)
+		s.RoundName = PlayoffRoundName(league, s.Round)
+		s.ID = SeriesID(s.Season, s.Round, s.HigherSeed, s.LowerSeed)
+		s.score()
+	}
+	return all
+}
+
+//seed orders the teams by conference rank when both are in the standings and sets the conference
+func (s *Series) seed(standings *StandingsSnapshot) {
+	if standings == nil {
+		return
+	}
+	higher, lower := standings.Team(s.HigherSeed), standings.Team(s.LowerSeed)
+	if higher == nil || lower == nil {
+		return
+	}
+	if higher.Conference == lower.Conference {
+		s.Conference = higher.Conference
+	}
+	if lower.ConferenceRank != 0 && lower.ConferenceRank < higher.ConferenceRank && s.Conference != "" {
+		higher, lower = lower, higher
+		s.HigherSeed, s.LowerSeed = s.LowerSeed, s.HigherSeed
+	}
+	s.HigherSeedRank, s.LowerSeedRank = higher.ConferenceRank, lower.ConferenceRank
+}
+
+//score numbers the games and keeps the series score, state and the flags of each game
+func (s *Series) score() {
+	need := s.WinsNeeded()
+	s.HigherSeedWins, s.LowerSeedWins = 0, 0
+	for i, g := range s.Games {
+		g.SeriesID = s.ID
+		g.Game = i + 1
+		g.IsDecider = g.Game == s.BestOf
+		g.IsElimination = s.HigherSeedWins == need-1 || s.LowerSeedWins == need-1
+		switch g.Winner {
+		case s.HigherSeed:
+			s.HigherSeedWins++
+		case s.LowerSeed:
+			s.LowerSeedWins++
+		}
+		g.IsClinch = g.Played && (s.HigherSeedWins == need || s.LowerSeedWins == need) && s.Winner == ""
+		if g.IsClinch {
+			s.Winner = g.Winner
+		}
+		g.HigherSeedWins, g.LowerSeedWins = s.HigherSeedWins, s.LowerSeedWins
+	}
+	switch {
+	case s.Winner != "":
+		s.State = SeriesComplete
+	case s.HigherSeedWins+s.LowerSeedWins > 0:
+		s.State = SeriesInProgress
+	default:
+		s.State = SeriesScheduled
+	}
+}
+
+//SeriesTable ... the playoff series of a league, persisted as their own table
+type SeriesTable struct {
+	League League
+	Series []*Series
+}
+
+//Games returns the games of all series that match e.g. game 7s or closeout games
+func (t *SeriesTable) Games(match func(s *Series, g *SeriesGame) bool) []*SeriesGame {
+	games := []*SeriesGame{}
+	for _, s := range t.Series {
+		for _, g := range s.Games {
+			if match(s, g) {
+				games = append(games, g)
+			}
+		}
+	}
+	return games
+}
@@ -0,0 +1,137 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func seriesEvent(id string, day string, stage Stage, home string, homeScore int, visit string, visitScore int) *Event {
+	return &Event{GameID: GameID(id), League: LeagueNBA, Season: Season{SeasonYear: 2019, SeasonStage: int(stage)},
+		HomeTeam:   &Competitor{Abbreviation: home, Score: homeScore},
+		VisitTeam:  &Competitor{Abbreviation: visit, Score: visitScore},
+		Status:     &GameStatus{State: "post"},
+		GameDetail: &GameDetail{StartDateEastern: day}}
+}
+
+func TestBuildSeriesGameSeven(t *testing.T) {
+	events := []*Event{
+		seriesEvent("401237107", "2020-09-15", StagePlayoffs, "DEN", 104, "LAC", 89),
+		seriesEvent("401237101", "2020-09-03", StagePlayoffs, "LAC", 120, "DEN", 97),
+		seriesEvent("401237102", "2020-09-05", StagePlayoffs, "DEN", 110, "LAC", 101),
+		seriesEvent("401237103", "2020-09-07", StagePlayoffs, "LAC", 113, "DEN", 107),
+		seriesEvent("401237104", "2020-09-09", StagePlayoffs, "DEN", 85, "LAC", 96),
+		seriesEvent("401237105", "2020-09-11", StagePlayoffs, "LAC", 105, "DEN", 111),
+		seriesEvent("401237106", "2020-09-13", StagePlayoffs, "DEN", 111, "LAC", 98),
+		seriesEvent("401161001", "2020-03-01", StageRegular, "DEN", 100, "LAC", 90),
+	}
+	// DEN's and LAC's first round series
+	events = append(events,
+		seriesEvent("401225701", "2020-08-17", StagePlayoffs, "DEN", 135, "UTA", 125),
+		seriesEvent("401225702", "2020-08-17", StagePlayoffs, "LAC", 118, "DAL", 110))
+
+	all := BuildSeries(LeagueNBA, events, nil)
+	assert.Equal(t, 3, len(all))
+	s := all[2]
+	assert.Equal(t, "2019:2:LAC:DEN", s.ID)
+	assert.Equal(t, 2, s.Round, "second series of both teams")
+	assert.Equal(t, "Conference Semifinals", s.RoundName)
+	assert.Equal(t, "LAC", s.HigherSeed, "home in game 1")
+	assert.Equal(t, 7, len(s.Games))
+	assert.Equal(t, SeriesComplete, s.State)
+	assert.Equal(t, "DEN", s.Winner)
+	assert.Equal(t, "LAC", s.Loser())
+	assert.Equal(t, 3, s.HigherSeedWins)
+	assert.Equal(t, 4, s.LowerSeedWins)
+
+	elimination := []bool{false, false, false, false, true, true, true}
+	for i, g := range s.Games {
+		assert.Equal(t, i+1, g.Game)
+		assert.Equal(t, s.ID, g.SeriesID)
+		assert.Equal(t, elimination[i], g.IsElimination, "game %d", g.Game)
+		assert.Equal(t, i == 6, g.IsClinch, "game %d", g.Game)
+		assert.Equal(t, i == 6, g.IsDecider, "game %d", g.Game)
+	}
+	assert.Equal(t, GameID("401237107"), s.Game(7).GameID)
+	assert.Equal(t, 3, s.Game(4).HigherSeedWins)
+	assert.Equal(t, 1, s.Game(4).LowerSeedWins)
+	assert.Nil(t, s.Game(8))
+
+	table := SeriesTable{League: LeagueNBA, Series: all}
+	sevens := table.Games(func(s *Series, g *SeriesGame) bool { return g.IsDecider })
+	assert.Equal(t, 1, len(sevens))
+	assert.Equal(t, "DEN", sevens[0].Winner)
+	closeouts := table.Games(func(s *Series, g *SeriesGame) bool { return g.IsClinch })
+	assert.Equal(t, 1, len(closeouts), "the first round series are not won")
+
+	b := bytes.Buffer{}
+	assert.Equal(t, "seriesNBA", table.tableName())
+	assert.Nil(t, table.marshalNBJSON(&b))
+	assert.Equal(t, 3, strings.Count(b.String(), "\n"))
+}
+
+func TestBuildSeriesNBAGameIDs(t *testing.T) {
+	events := []*Event{
+		seriesEvent("0041900111", "2020-08-18", StagePlayoffs, "ORL", 122, "MIL", 110),
+		seriesEvent("0041900112", "2020-08-20", StagePlayoffs, "ORL", 96, "MIL", 111),
+		seriesEvent("0041900113", "2020-08-22", StagePlayoffs, "MIL", 121, "ORL", 107),
+		seriesEvent("0041900114", "2020-08-24", StagePlayoffs, "MIL", 0, "ORL", 0),
+	}
+	events[3].Status.State = "pre"
+	standings := &StandingsSnapshot{League: LeagueNBA, Standings: []*Standing{
+		{Abbreviation: "MIL", Conference: "East", ConferenceRank: 1},
+		{Abbreviation: "ORL", Conference: "East", ConferenceRank: 8},
+	}}
+
+	all := BuildSeries(LeagueNBA, events, standings)
+	assert.Equal(t, 1, len(all))
+	s := all[0]
+	assert.Equal(t, "2019:1:MIL:ORL", s.ID)
+	assert.Equal(t, 1, s.Round)
+	assert.Equal(t, 1, s.Number)
+	assert.Equal(t, "East", s.Conference)
+	assert.Equal(t, "MIL", s.HigherSeed, "seeded by the standings, not by game 1")
+	assert.Equal(t, 1, s.HigherSeedRank)
+	assert.Equal(t, 8, s.LowerSeedRank)
+	assert.Equal(t, SeriesInProgress, s.State)
+	assert.Equal(t, 2, s.HigherSeedWins)
+	assert.Equal(t, 1, s.LowerSeedWins)
+	assert.Equal(t, "", s.Winner)
+	assert.False(t, s.Game(4).Played)
+	assert.Equal(t, "", s.Game(4).Winner)
+
+	assert.Equal(t, SeriesScheduled, BuildSeries(LeagueNBA, events[3:], nil)[0].State)
+	assert.Equal(t, 5, LeagueWNBA.BestOf())
+}
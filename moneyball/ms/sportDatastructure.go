@@ -0,0 +1,179 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// a League belongs to a Sport, and the sport decides how a game is divided: quarters, halves, periods or
+// innings, plus what happens after regulation (overtime, extra innings, extra time and penalties).  Events
+// of every sport are normalized into the same tables, GamePeriod and the linescore Score carry the period
+// number and its label so e.g. "ET1" and "10" can be told apart from "OT"
+
+import (
+	"fmt"
+)
+
+//Sport ...
+type Sport string
+
+// sports we normalize events for
+const (
+	//SportBasketball ...
+	SportBasketball Sport = "basketball"
+	//SportFootball is american football
+	SportFootball Sport = "football"
+	//SportHockey is ice hockey
+	SportHockey Sport = "hockey"
+	//SportBaseball ...
+	SportBaseball Sport = "baseball"
+	//SportSoccer ...
+	SportSoccer Sport = "soccer"
+)
+
+//PeriodUnit ... how a sport divides regulation
+type PeriodUnit string
+
+const (
+	//PeriodQuarter e.g. NBA, NFL
+	PeriodQuarter PeriodUnit = "quarter"
+	//PeriodHalf e.g. men's college basketball, soccer
+	PeriodHalf PeriodUnit = "half"
+	//PeriodPeriod e.g. hockey's three periods
+	PeriodPeriod PeriodUnit = "period"
+	//PeriodInning ...
+	PeriodInning PeriodUnit = "inning"
+)
+
+//PeriodFormat ... the regulation periods of a league and how extra periods are named
+type PeriodFormat struct {
+	Unit        PeriodUnit `json:"unit"`                  // "quarter"
+	Regular     int        `json:"regular"`               // 4 quarters, 9 innings
+	ExtraPrefix string     `json:"extraPrefix,omitempty"` // "OT", "ET", empty for innings which just keep counting
+	MaxExtra    int        `json:"maxExtra,omitempty"`    // soccer plays 2 halves of extra time, 0 is unbounded
+	Decider     string     `json:"decider,omitempty"`     // label of the period after MaxExtra e.g. "PEN"
+}
+
+var sportFormats = map[Sport]PeriodFormat{
+	SportBasketball: {Unit: PeriodQuarter, Regular: 4, ExtraPrefix: "OT"},
+	SportFootball:   {Unit: PeriodQuarter, Regular: 4, ExtraPrefix: "OT"},
+	SportHockey:     {Unit: PeriodPeriod, Regular: 3, ExtraPrefix: "OT"},
+	SportBaseball:   {Unit: PeriodInning, Regular: 9},
+	SportSoccer:     {Unit: PeriodHalf, Regular: 2, ExtraPrefix: "ET", MaxExtra: 2, Decider: "PEN"},
+}
+
+var leagueSports = map[League]Sport{
+	LeagueNBA:   SportBasketball,
+	LeagueWNBA:  SportBasketball,
+	LeagueNCAAM: SportBasketball,
+	LeagueNCAAW: SportBasketball,
+	LeagueNFL:   SportFootball,
+	LeagueNCAAF: SportFootball,
+	LeagueNHL:   SportHockey,
+	LeagueMLB:   SportBaseball,
+	LeagueMLS:   SportSoccer,
+	LeagueEPL:   SportSoccer,
+}
+
+// leagues that don't play the format of their sport
+var leagueFormats = map[League]PeriodFormat{
+	LeagueNCAAM: {Unit: PeriodHalf, Regular: 2, ExtraPrefix: "OT"},
+}
+
+//PeriodFormat of the sport, unknown sports play basketball's
+func (s Sport) PeriodFormat() PeriodFormat {
+	if f, ok := sportFormats[s]; ok {
+		return f
+	}
+	return sportFormats[SportBasketball]
+}
+
+//Sport the league plays, unknown leagues are basketball
+func (l League) Sport() Sport {
+	if s, ok := leagueSports[l]; ok {
+		return s
+	}
+	return SportBasketball
+}
+
+//PeriodFormat of the league
+func (l League) PeriodFormat() PeriodFormat {
+	if f, ok := leagueFormats[l]; ok {
+		return f
+	}
+	return l.Sport().PeriodFormat()
+}
+
+//IsExtra is true when period n is past regulation
+func (f PeriodFormat) IsExtra(n int) bool {
+	return n > f.Regular
+}
+
+//Label names period n (1 based): "Q1", "H2", "P3", "7", then "OT", "2OT", "ET1", "10" or the Decider
+func (f PeriodFormat) Label(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if !f.IsExtra(n) {
+		switch f.Unit {
+		case PeriodQuarter:
+			return fmt.Sprintf("Q%d", n)
+		case PeriodHalf:
+			return fmt.Sprintf("H%d", n)
+		case PeriodPeriod:
+			return fmt.Sprintf("P%d", n)
+		}
+		return fmt.Sprintf("%d", n)
+	}
+	x := n - f.Regular
+	switch {
+	case f.MaxExtra > 0 && x > f.MaxExtra:
+		return f.Decider
+	case f.ExtraPrefix == "":
+		return fmt.Sprintf("%d", n)
+	case f.MaxExtra > 0:
+		return fmt.Sprintf("%s%d", f.ExtraPrefix, x)
+	case x == 1:
+		return f.ExtraPrefix
+	}
+	return fmt.Sprintf("%d%s", x, f.ExtraPrefix)
+}
+
+//GamePeriod returns the period n of a game in the format
+func (f PeriodFormat) GamePeriod(n int) *GamePeriod {
+	return &GamePeriod{Current: n, MaxRegular: f.Regular, Unit: f.Unit, Label: f.Label(n), IsExtra: f.IsExtra(n)}
+}
+
+//LineScore numbers and labels period scores given in order
+func (f PeriodFormat) LineScore(scores []float32) []Score {
+	linescores := []Score{}
+	for i, sc := range scores {
+		linescores = append(linescores, Score{Score: sc, Period: i + 1, Label: f.Label(i + 1), IsExtra: f.IsExtra(i + 1)})
+	}
+	return linescores
+}
@@ -0,0 +1,72 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodFormatLabel(t *testing.T) {
+	nba := LeagueNBA.PeriodFormat()
+	assert.Equal(t, "Q4", nba.Label(4))
+	assert.Equal(t, "OT", nba.Label(5))
+	assert.Equal(t, "3OT", nba.Label(7))
+	assert.Equal(t, "H2", LeagueNCAAM.PeriodFormat().Label(2))
+	assert.Equal(t, "P3", LeagueNHL.PeriodFormat().Label(3))
+
+	mlb := LeagueMLB.PeriodFormat()
+	assert.Equal(t, "9", mlb.Label(9))
+	assert.Equal(t, "12", mlb.Label(12))
+	assert.True(t, mlb.IsExtra(10))
+
+	soccer := LeagueEPL.PeriodFormat()
+	assert.Equal(t, []string{"H1", "H2", "ET1", "ET2", "PEN"},
+		[]string{soccer.Label(1), soccer.Label(2), soccer.Label(3), soccer.Label(4), soccer.Label(5)})
+	assert.Equal(t, "", soccer.Label(0))
+}
+
+func TestLeagueSport(t *testing.T) {
+	assert.Equal(t, SportFootball, LeagueNCAAF.Sport())
+	assert.Equal(t, SportBasketball, League("XYZ").Sport())
+	assert.Equal(t, SportSoccer.PeriodFormat(), LeagueMLS.PeriodFormat())
+
+	gp := LeagueNHL.PeriodFormat().GamePeriod(4)
+	assert.Equal(t, PeriodPeriod, gp.Unit)
+	assert.Equal(t, "OT", gp.Label)
+	assert.True(t, gp.IsExtra)
+	assert.Equal(t, 3, gp.MaxRegular)
+
+	ls := LeagueMLB.PeriodFormat().LineScore([]float32{0, 1, 0, 0, 0, 2, 0, 0, 0, 1})
+	assert.Len(t, ls, 10)
+	assert.Equal(t, Score{Score: 1, Period: 10, Label: "10", IsExtra: true}, ls[9])
+}
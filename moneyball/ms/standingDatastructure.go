@@ -0,0 +1,181 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// a Standing is a team's place in its league table on a given date, standings are collected as
+// StandingsSnapshots (one per league per day) so that the table can be recalled as of any historical
+// date (see StandingsHistory)
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	//standingDateFormat is the date part of a Standing key
+	standingDateFormat = "2006-01-02"
+)
+
+//StandingSplit ... wins and losses for a subset of games e.g. home, last ten
+type StandingSplit struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+}
+
+//ParseStandingSplit converts a "15-1" summary into a StandingSplit
+func ParseStandingSplit(summary string) (StandingSplit, error) {
+	split := StandingSplit{}
+	if _, err := fmt.Sscanf(strings.TrimSpace(summary), "%d-%d", &split.Wins, &split.Losses); err != nil {
+		return split, fmt.Errorf("invalid record summary %s", summary)
+	}
+	return split, nil
+}
+
+//Summary returns the split as "15-1"
+func (s StandingSplit) Summary() string {
+	return fmt.Sprintf("%d-%d", s.Wins, s.Losses)
+}
+
+//Standing ... a team's standing in the league as of a date
+type Standing struct {
+	EntityID                         //EntityID.ID in form of "YYYY-MM-DD.ABR" e.g. "2020-01-06.MIL", see StandingID
+	TeamIDNBA          string        `json:"teamIdNBA,omitempty"`  //"1610612749"
+	TeamIDESPN         string        `json:"teamIdESPN,omitempty"` //"15"
+	Abbreviation       string        `json:"abbreviation"`         //"MIL"
+	League             League        `json:"league"`
+	Season             Season        `json:"season"`
+	AsOf               time.Time     `json:"asOf"`                 // date of the standing, midnight UTC
+	Conference         string        `json:"conference,omitempty"` // "East"
+	Division           string        `json:"division,omitempty"`   // "Central"
+	Wins               int           `json:"wins"`
+	Losses             int           `json:"losses"`
+	WinPct             float64       `json:"winPct"`    // .848
+	GamesBack          float64       `json:"gamesBack"` // games behind the conference leader
+	ConferenceRank     int           `json:"conferenceRank,omitempty"`
+	DivisionRank       int           `json:"divisionRank,omitempty"`
+	Streak             int           `json:"streak"`               // positive for a winning streak, negative for losing
+	Home               StandingSplit `json:"home"`                 // home record
+	Away               StandingSplit `json:"away"`                 // road record
+	ConferenceRecord   StandingSplit `json:"conferenceRecord"`     // record against the conference
+	DivisionRecord     StandingSplit `json:"divisionRecord"`       // record against the division
+	LastTen            StandingSplit `json:"lastTen"`              // record over the last ten games
+	ClinchCode         string        `json:"clinchCode,omitempty"` // provider code e.g. "x", "y", "z", "o"/"e"
+	ClinchedPlayoffs   bool          `json:"clinchedPlayoffs"`
+	ClinchedConference bool          `json:"clinchedConference"`
+	ClinchedDivision   bool          `json:"clinchedDivision"`
+	Eliminated         bool          `json:"eliminated"`
+}
+
+//StandingID keys a Standing by date and team e.g. "2020-01-06.MIL"
+func StandingID(asOf time.Time, abbreviation string) string {
+	return asOf.Format(standingDateFormat) + "." + abbreviation
+}
+
+//StandingDate truncates t to the date (in its own location) a standing is kept for, as midnight UTC
+func StandingDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+//StreakText returns the streak as "W2" or "L3"
+func (s *Standing) StreakText() string {
+	if s.Streak < 0 {
+		return fmt.Sprintf("L%d", -s.Streak)
+	}
+	return fmt.Sprintf("W%d", s.Streak)
+}
+
+//SeasonRecords renders the standing as TeamSeasonRecords for Team.Records
+func (s *Standing) SeasonRecords() *TeamSeasonRecords {
+	season := s.Season
+	return &TeamSeasonRecords{
+		Season:  &season,
+		Summary: StandingSplit{Wins: s.Wins, Losses: s.Losses}.Summary(),
+		Stats: []*Stat{
+			{Key: "W", LongKey: "wins", Value: s.Wins},
+			{Key: "L", LongKey: "losses", Value: s.Losses},
+			{Key: "PCT", LongKey: "winPercent", Value: s.WinPct},
+			{Key: "GB", LongKey: "gamesBehind", Value: s.GamesBack},
+			{Key: "STRK", LongKey: "streak", Value: s.StreakText()},
+			{Key: "HOME", LongKey: "home", Value: s.Home.Summary()},
+			{Key: "AWAY", LongKey: "road", Value: s.Away.Summary()},
+			{Key: "CONF", LongKey: "vsConference", Value: s.ConferenceRecord.Summary()},
+			{Key: "DIV", LongKey: "vsDivision", Value: s.DivisionRecord.Summary()},
+			{Key: "L10", LongKey: "lastTenGames", Value: s.LastTen.Summary()},
+		},
+	}
+}
+
+//StandingsSnapshot ... the league table as of a date
+type StandingsSnapshot struct {
+	League    League      `json:"league"`
+	AsOf      time.Time   `json:"asOf"`
+	Standings []*Standing `json:"standings"`
+}
+
+//Team returns the standing of the team with abbreviation, or nil
+func (ss *StandingsSnapshot) Team(abbreviation string) *Standing {
+	for _, s := range ss.Standings {
+		if s.Abbreviation == abbreviation {
+			return s
+		}
+	}
+	return nil
+}
+
+//StandingsHistory ... snapshots of a league table over time, ordered by date
+type StandingsHistory struct {
+	Snapshots []*StandingsSnapshot `json:"snapshots"`
+}
+
+//Add keeps the snapshot in date order, a later snapshot for the same date replaces the earlier one
+func (h *StandingsHistory) Add(ss *StandingsSnapshot) {
+	day := StandingDate(ss.AsOf)
+	i := sort.Search(len(h.Snapshots), func(i int) bool { return !StandingDate(h.Snapshots[i].AsOf).Before(day) })
+	if i < len(h.Snapshots) && StandingDate(h.Snapshots[i].AsOf).Equal(day) {
+		h.Snapshots[i] = ss
+		return
+	}
+	h.Snapshots = append(h.Snapshots, nil)
+	copy(h.Snapshots[i+1:], h.Snapshots[i:])
+	h.Snapshots[i] = ss
+}
+
+//AsOf returns the latest snapshot taken on or before date, or nil if there is none
+func (h *StandingsHistory) AsOf(date time.Time) *StandingsSnapshot {
+	day := StandingDate(date)
+	i := sort.Search(len(h.Snapshots), func(i int) bool { return StandingDate(h.Snapshots[i].AsOf).After(day) })
+	if i == 0 {
+		return nil
+	}
+	return h.Snapshots[i-1]
+}
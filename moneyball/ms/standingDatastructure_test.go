@@ -0,0 +1,63 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandingsHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2020, time.January, d, 15, 0, 0, 0, time.UTC) }
+	snapshot := func(d int, wins int) *StandingsSnapshot {
+		return &StandingsSnapshot{League: "NBA", AsOf: StandingDate(day(d)),
+			Standings: []*Standing{{Abbreviation: "MIL", Wins: wins}}}
+	}
+	h := StandingsHistory{}
+	h.Add(snapshot(6, 28))
+	h.Add(snapshot(2, 26))
+	h.Add(snapshot(4, 27))
+	h.Add(snapshot(4, 28)) // replaces the earlier snapshot for the 4th
+
+	assert.Equal(t, 3, len(h.Snapshots))
+	assert.Nil(t, h.AsOf(day(1)))
+	assert.Equal(t, 26, h.AsOf(day(3)).Team("MIL").Wins)
+	assert.Equal(t, 28, h.AsOf(day(4)).Team("MIL").Wins)
+	assert.Equal(t, 28, h.AsOf(day(30)).Team("MIL").Wins)
+
+	split, err := ParseStandingSplit("15-1")
+	assert.Nil(t, err)
+	assert.Equal(t, StandingSplit{Wins: 15, Losses: 1}, split)
+	_, err = ParseStandingSplit("n/a")
+	assert.NotNil(t, err)
+}
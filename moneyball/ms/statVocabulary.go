@@ -0,0 +1,556 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// the stat vocabulary: canonical StatDefs keyed by name ("fieldGoalsMade") and short key ("FGM") with their unit
+// and how they aggregate over games, plus per-provider tables mapping the provider's names (ESPN
+// "threePointFieldGoalsMade", NBA "tpm") to them.  Normalize validates a provider Stat against the vocabulary,
+// unknown names and values of the wrong type are errors rather than new warehouse columns.
+// Stats are persisted with numbers in "value" and text in "text" so each column has one type
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//StatUnit ... what a stat's value measures
+type StatUnit string
+
+const (
+	//UnitCount e.g. points, rebounds
+	UnitCount StatUnit = "count"
+	//UnitPercent 0-100 e.g. 47.7
+	UnitPercent StatUnit = "percent"
+	//UnitMinutes ...
+	UnitMinutes StatUnit = "minutes"
+	//UnitRecord a "wins-losses" text e.g. "15-1"
+	UnitRecord StatUnit = "record"
+)
+
+//Aggregation ... how a stat combines over games
+type Aggregation string
+
+const (
+	//AggregateSum totals e.g. points
+	AggregateSum Aggregation = "sum"
+	//AggregateAvg averages e.g. points per game
+	AggregateAvg Aggregation = "avg"
+	//AggregateRate the sum of the Numerator stat over the sum of the Denominator stat e.g. field goal %
+	AggregateRate Aggregation = "rate"
+	//AggregateLatest the last value e.g. streak, games behind
+	AggregateLatest Aggregation = "latest"
+)
+
+var (
+	//ErrUnknownStat the name is not in the vocabulary or the provider's table
+	ErrUnknownStat = errors.New("unknown stat")
+	//ErrStatType the value doesn't fit the unit of the stat
+	ErrStatType = errors.New("stat value of the wrong type")
+)
+
+//StatDef ... a canonical stat
+type StatDef struct {
+	Key         string      `json:"key"`                   // "FG%"
+	Name        string      `json:"name"`                  // "fieldGoalPct"
+	DisplayName string      `json:"displayName"`           // "Field Goal %"
+	Unit        StatUnit    `json:"unit"`                  // "percent"
+	Aggregation Aggregation `json:"aggregation"`           // "rate"
+	Numerator   string      `json:"numerator,omitempty"`   // "fieldGoalsMade"
+	Denominator string      `json:"denominator,omitempty"` // "fieldGoalsAttempted"
+}
+
+//IsText the value is text, a record "15-1"
+func (d *StatDef) IsText() bool {
+	return d.Unit == UnitRecord
+}
+
+//StatVocabulary ... registry of the canonical stats and the providers' names for them
+type StatVocabulary struct {
+	mu       sync.RWMutex
+	defs     map[string]*StatDef // by name
+	byKey    map[string]*StatDef
+	mappings map[Provider]map[string]string // provider name -> canonical name
+}
+
+//NewStatVocabulary returns an empty vocabulary
+func NewStatVocabulary() *StatVocabulary {
+	return &StatVocabulary{defs: map[string]*StatDef{}, byKey: map[string]*StatDef{}, mappings: map[Provider]map[string]string{}}
+}
+
+//Register adds a stat, names and keys are unique and the numerator and denominator of a rate must be registered
+func (v *StatVocabulary) Register(def StatDef) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if def.Name == "" || def.Key == "" {
+		return fmt.Errorf("stat %s: needs a name and a key", def.Name)
+	}
+	if _, ok := v.defs[def.Name]; ok {
+		return fmt.Errorf("stat %s: already registered", def.Name)
+	}
+	if _, ok := v.byKey[def.Key]; ok {
+		return fmt.Errorf("stat %s: key %s already registered", def.Name, def.Key)
+	}
+	if def.Aggregation == AggregateRate {
+		if v.defs[def.Numerator] == nil || v.defs[def.Denominator] == nil {
+			return fmt.Errorf("stat %s: rate of unregistered stats %s/%s", def.Name, def.Numerator, def.Denominator)
+		}
+	}
+	v.defs[def.Name] = &def
+	v.byKey[def.Key] = &def
+	return nil
+}
+
+//Map adds the provider's name for a registered stat
+func (v *StatVocabulary) Map(provider Provider, providerName string, name string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.defs[name] == nil {
+		return fmt.Errorf("%s stat %s: %w %s", provider, providerName, ErrUnknownStat, name)
+	}
+	if v.mappings[provider] == nil {
+		v.mappings[provider] = map[string]string{}
+	}
+	v.mappings[provider][providerName] = name
+	return nil
+}
+
+//Def returns the stat with canonical name or key, or nil
+func (v *StatVocabulary) Def(name string) *StatDef {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if def, ok := v.defs[name]; ok {
+		return def
+	}
+	return v.byKey[name]
+}
+
+//Resolve returns the stat a provider's name stands for, the provider's table first then the canonical names
+func (v *StatVocabulary) Resolve(provider Provider, name string) *StatDef {
+	v.mu.RLock()
+	canonical, ok := v.mappings[provider][name]
+	v.mu.RUnlock()
+	if ok {
+		name = canonical
+	}
+	return v.Def(name)
+}
+
+//Definitions returns the stats ordered by name e.g. for a warehouse dimension table
+func (v *StatVocabulary) Definitions() []StatDef {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	defs := []StatDef{}
+	for _, def := range v.defs {
+		defs = append(defs, *def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+//typedValue converts value to what the stat's unit holds, a float64 or a string
+func (d *StatDef) typedValue(value interface{}) (interface{}, error) {
+	if d.IsText() {
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("stat %s: %w %T, want text", d.Name, ErrStatType, value)
+	}
+	if f, ok := statNumber(value); ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("stat %s: %w %v, want a number", d.Name, ErrStatType, value)
+}
+
+//Normalize maps a provider stat to canonical stats with float64 or string values, a made-attempted stat e.g.
+//ESPN's "fieldGoalsMade-fieldGoalsAttempted" "42-88" gives two stats.  Stats are matched on LongKey then Key
+func (v *StatVocabulary) Normalize(provider Provider, st *Stat) ([]*Stat, error) {
+	for _, name := range []string{st.LongKey, st.Key} {
+		if name == "" {
+			continue
+		}
+		if def := v.Resolve(provider, name); def != nil {
+			value, err := def.typedValue(st.Value)
+			if err != nil {
+				return nil, err
+			}
+			return []*Stat{{Key: def.Key, LongKey: def.Name, Value: value}}, nil
+		}
+		names := strings.Split(name, "-")
+		if len(names) != 2 {
+			continue
+		}
+		made, attempted := v.Resolve(provider, names[0]), v.Resolve(provider, names[1])
+		if made == nil || attempted == nil {
+			continue
+		}
+		values := strings.Split(statString(st.Value), "-")
+		if len(values) != 2 {
+			return nil, fmt.Errorf("stat %s: %w %v, want made-attempted", name, ErrStatType, st.Value)
+		}
+		stats := []*Stat{}
+		for i, def := range []*StatDef{made, attempted} {
+			value, err := def.typedValue(values[i])
+			if err != nil {
+				return nil, err
+			}
+			stats = append(stats, &Stat{Key: def.Key, LongKey: def.Name, Value: value})
+		}
+		return stats, nil
+	}
+	return nil, fmt.Errorf("%s stat %s (%s): %w", provider, st.LongKey, st.Key, ErrUnknownStat)
+}
+
+//NormalizeAll normalizes the stats, returning those that are valid and an error for each that isn't
+func (v *StatVocabulary) NormalizeAll(provider Provider, stats []*Stat) ([]*Stat, []error) {
+	normalized := []*Stat{}
+	errs := []error{}
+	for _, st := range stats {
+		ns, err := v.Normalize(provider, st)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		normalized = append(normalized, ns...)
+	}
+	return normalized, errs
+}
+
+//StatValidationError ... the stats of a record the vocabulary rejected, the valid ones are kept
+type StatValidationError struct {
+	Provider Provider
+	Record   string // what the stats belong to e.g. "competitor 28"
+	Errs     []error
+}
+
+func (e *StatValidationError) Error() string {
+	msgs := []string{}
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%s stats of %s: %s", e.Provider, e.Record, strings.Join(msgs, "; "))
+}
+
+//Unwrap ... errors.Is finds ErrUnknownStat and ErrStatType
+func (e *StatValidationError) Unwrap() []error {
+	return e.Errs
+}
+
+//Validate normalizes the stats of a record, a stat the provider gives under two names is kept once.  The error
+//is a *StatValidationError for the stats that aren't valid, nil when all are
+func (v *StatVocabulary) Validate(provider Provider, record string, stats []*Stat) ([]*Stat, error) {
+	normalized, errs := v.NormalizeAll(provider, stats)
+	valid := []*Stat{}
+	seen := map[string]bool{}
+	for _, st := range normalized {
+		if seen[st.LongKey] {
+			continue
+		}
+		seen[st.LongKey] = true
+		valid = append(valid, st)
+	}
+	if len(errs) > 0 {
+		return valid, &StatValidationError{Provider: provider, Record: record, Errs: errs}
+	}
+	return valid, nil
+}
+
+//Aggregate combines a stat over games given the normalized stats of each game, following the stat's Aggregation
+func (v *StatVocabulary) Aggregate(name string, games ...[]*Stat) (float64, error) {
+	def := v.Def(name)
+	if def == nil {
+		return 0, fmt.Errorf("stat %s: %w", name, ErrUnknownStat)
+	}
+	if def.IsText() {
+		return 0, fmt.Errorf("stat %s: %w, text can't be aggregated", name, ErrStatType)
+	}
+	total := func(name string) (float64, int) {
+		sum, n := 0.0, 0
+		for _, stats := range games {
+			if st := findStat(stats, name); st != nil {
+				if f, ok := statNumber(st.Value); ok {
+					sum += f
+					n++
+				}
+			}
+		}
+		return sum, n
+	}
+	switch def.Aggregation {
+	case AggregateRate:
+		made, _ := total(def.Numerator)
+		attempted, _ := total(def.Denominator)
+		if attempted == 0 {
+			return 0, nil
+		}
+		if def.Unit == UnitPercent {
+			return 100 * made / attempted, nil
+		}
+		return made / attempted, nil
+	case AggregateAvg:
+		sum, n := total(def.Name)
+		if n == 0 {
+			return 0, nil
+		}
+		return sum / float64(n), nil
+	case AggregateLatest:
+		for i := len(games) - 1; i >= 0; i-- {
+			if st := findStat(games[i], def.Name); st != nil {
+				f, _ := statNumber(st.Value)
+				return f, nil
+			}
+		}
+		return 0, nil
+	}
+	sum, _ := total(def.Name)
+	return sum, nil
+}
+
+var (
+	defaultStatVocabulary     *StatVocabulary
+	defaultStatVocabularyOnce sync.Once
+)
+
+//DefaultStatVocabulary returns the shared vocabulary of box score and standings stats with the ESPN and NBA
+//tables
+func DefaultStatVocabulary() *StatVocabulary {
+	defaultStatVocabularyOnce.Do(func() {
+		v := NewStatVocabulary()
+		for _, def := range defaultStatDefs {
+			if err := v.Register(def); err != nil {
+				panic(err)
+			}
+		}
+		for provider, names := range providerStatNames {
+			for providerName, name := range names {
+				if err := v.Map(provider, providerName, name); err != nil {
+					panic(err)
+				}
+			}
+		}
+		defaultStatVocabulary = v
+	})
+	return defaultStatVocabulary
+}
+
+var defaultStatDefs = []StatDef{
+	{"PTS", "points", "Points", UnitCount, AggregateSum, "", ""},
+	{"FGM", "fieldGoalsMade", "Field Goals Made", UnitCount, AggregateSum, "", ""},
+	{"FGA", "fieldGoalsAttempted", "Field Goals Attempted", UnitCount, AggregateSum, "", ""},
+	{"3PM", "threePointersMade", "3-Pointers Made", UnitCount, AggregateSum, "", ""},
+	{"3PA", "threePointersAttempted", "3-Pointers Attempted", UnitCount, AggregateSum, "", ""},
+	{"FTM", "freeThrowsMade", "Free Throws Made", UnitCount, AggregateSum, "", ""},
+	{"FTA", "freeThrowsAttempted", "Free Throws Attempted", UnitCount, AggregateSum, "", ""},
+	{"FG%", "fieldGoalPct", "Field Goal %", UnitPercent, AggregateRate, "fieldGoalsMade", "fieldGoalsAttempted"},
+	{"3P%", "threePointPct", "3-Point %", UnitPercent, AggregateRate, "threePointersMade", "threePointersAttempted"},
+	{"FT%", "freeThrowPct", "Free Throw %", UnitPercent, AggregateRate, "freeThrowsMade", "freeThrowsAttempted"},
+	{"OREB", "offensiveRebounds", "Offensive Rebounds", UnitCount, AggregateSum, "", ""},
+	{"DREB", "defensiveRebounds", "Defensive Rebounds", UnitCount, AggregateSum, "", ""},
+	{"REB", "rebounds", "Rebounds", UnitCount, AggregateSum, "", ""},
+	{"TREB", "teamRebounds", "Team Rebounds", UnitCount, AggregateSum, "", ""},
+	{"AST", "assists", "Assists", UnitCount, AggregateSum, "", ""},
+	{"STL", "steals", "Steals", UnitCount, AggregateSum, "", ""},
+	{"BLK", "blocks", "Blocks", UnitCount, AggregateSum, "", ""},
+	{"TO", "turnovers", "Turnovers", UnitCount, AggregateSum, "", ""},
+	{"TTO", "teamTurnovers", "Team Turnovers", UnitCount, AggregateSum, "", ""},
+	{"PF", "personalFouls", "Personal Fouls", UnitCount, AggregateSum, "", ""},
+	{"TF", "technicalFouls", "Technical Fouls", UnitCount, AggregateSum, "", ""},
+	{"FF", "flagrantFouls", "Flagrant Fouls", UnitCount, AggregateSum, "", ""},
+	{"FBP", "fastBreakPoints", "Fast Break Points", UnitCount, AggregateSum, "", ""},
+	{"PIP", "pointsInPaint", "Points in Paint", UnitCount, AggregateSum, "", ""},
+	{"SCP", "secondChancePoints", "Second Chance Points", UnitCount, AggregateSum, "", ""},
+	{"POT", "pointsOffTurnovers", "Points off Turnovers", UnitCount, AggregateSum, "", ""},
+	{"LEAD", "biggestLead", "Biggest Lead", UnitCount, AggregateAvg, "", ""},
+	{"RUN", "longestRun", "Longest Run", UnitCount, AggregateAvg, "", ""},
+	{"TIED", "timesTied", "Times Tied", UnitCount, AggregateSum, "", ""},
+	{"LC", "leadChanges", "Lead Changes", UnitCount, AggregateSum, "", ""},
+	{"MIN", "minutes", "Minutes", UnitMinutes, AggregateSum, "", ""},
+	{"+/-", "plusMinus", "Plus/Minus", UnitCount, AggregateSum, "", ""},
+	{"PPG", "pointsPerGame", "Points Per Game", UnitCount, AggregateAvg, "", ""},
+	{"RPG", "reboundsPerGame", "Rebounds Per Game", UnitCount, AggregateAvg, "", ""},
+	{"APG", "assistsPerGame", "Assists Per Game", UnitCount, AggregateAvg, "", ""},
+	{"GP", "gamesPlayed", "Games Played", UnitCount, AggregateSum, "", ""},
+	{"W", "wins", "Wins", UnitCount, AggregateSum, "", ""},
+	{"L", "losses", "Losses", UnitCount, AggregateSum, "", ""},
+	{"PCT", "winPercent", "Win %", UnitPercent, AggregateRate, "wins", "gamesPlayed"},
+	{"GB", "gamesBehind", "Games Behind", UnitCount, AggregateLatest, "", ""},
+	{"OPPG", "oppPointsPerGame", "Opponent Points Per Game", UnitCount, AggregateAvg, "", ""},
+	{"PFOR", "pointsFor", "Points For", UnitCount, AggregateSum, "", ""},
+	{"PA", "pointsAgainst", "Points Against", UnitCount, AggregateSum, "", ""},
+	{"DIFF", "differential", "Point Differential", UnitCount, AggregateLatest, "", ""},
+	{"OTW", "OTWins", "Overtime Wins", UnitCount, AggregateSum, "", ""},
+	{"OTL", "OTLosses", "Overtime Losses", UnitCount, AggregateSum, "", ""},
+	{"T", "ties", "Ties", UnitCount, AggregateSum, "", ""},
+	{"DPCT", "divisionWinPercent", "Division Win %", UnitPercent, AggregateLatest, "", ""},
+	{"LPCT", "leagueWinPercent", "League Win %", UnitPercent, AggregateLatest, "", ""},
+	{"STRK", "streak", "Streak", UnitCount, AggregateLatest, "", ""},
+	{"SEED", "playoffSeed", "Playoff Seed", UnitCount, AggregateLatest, "", ""},
+	{"HOME", "home", "Home", UnitRecord, AggregateLatest, "", ""},
+	{"AWAY", "road", "Road", UnitRecord, AggregateLatest, "", ""},
+	{"CONF", "vsConference", "vs. Conference", UnitRecord, AggregateLatest, "", ""},
+	{"DIV", "vsDivision", "vs. Division", UnitRecord, AggregateLatest, "", ""},
+	{"L10", "lastTenGames", "Last Ten Games", UnitRecord, AggregateLatest, "", ""},
+}
+
+//providerStatNames ... the providers' names for stats whose names differ from the canonical ones
+var providerStatNames = map[Provider]map[string]string{
+	ProviderESPN: {
+		"threePointFieldGoalsMade":      "threePointersMade",
+		"threePointFieldGoalsAttempted": "threePointersAttempted",
+		"threePointFieldGoalPct":        "threePointPct",
+		"totalRebounds":                 "rebounds",
+		"fouls":                         "personalFouls",
+		"totalTechnicalFouls":           "technicalFouls",
+		"turnoverPoints":                "pointsOffTurnovers",
+		"largestLead":                   "biggestLead",
+		"avgPoints":                     "pointsPerGame",
+		"avgRebounds":                   "reboundsPerGame",
+		"avgAssists":                    "assistsPerGame",
+		"avgPointsFor":                  "pointsPerGame",
+		"avgPointsAgainst":              "oppPointsPerGame",
+		"winPercent":                    "winPercent",
+		"gamesBehind":                   "gamesBehind",
+		"vsConf":                        "vsConference",
+		"vsDiv":                         "vsDivision",
+		"lasttengames":                  "lastTenGames",
+	},
+	ProviderNBA: {
+		"fgm":                      "fieldGoalsMade",
+		"fga":                      "fieldGoalsAttempted",
+		"fgp":                      "fieldGoalPct",
+		"tpm":                      "threePointersMade",
+		"tpa":                      "threePointersAttempted",
+		"tpp":                      "threePointPct",
+		"ftm":                      "freeThrowsMade",
+		"fta":                      "freeThrowsAttempted",
+		"ftp":                      "freeThrowPct",
+		"FG3M":                     "threePointersMade",
+		"FG3A":                     "threePointersAttempted",
+		"offReb":                   "offensiveRebounds",
+		"defReb":                   "defensiveRebounds",
+		"totReb":                   "rebounds",
+		"pFouls":                   "personalFouls",
+		"fouls":                    "personalFouls",
+		"min":                      "minutes",
+		"field_goals_made":         "fieldGoalsMade",
+		"field_goals_attempted":    "fieldGoalsAttempted",
+		"three_pointers_made":      "threePointersMade",
+		"three_pointers_attempted": "threePointersAttempted",
+		"free_throws_made":         "freeThrowsMade",
+		"free_throws_attempted":    "freeThrowsAttempted",
+		"rebounds_offensive":       "offensiveRebounds",
+		"rebounds_defensive":       "defensiveRebounds",
+		"team_rebounds":            "teamRebounds",
+		"team_turnovers":           "teamTurnovers",
+		"technical_fouls":          "technicalFouls",
+	},
+}
+
+//statNumber reads a number from a provider value, numeric strings included
+func statNumber(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func statFloat(v interface{}) float64 {
+	f, _ := statNumber(v)
+	return f
+}
+
+func statString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case nil:
+		return ""
+	}
+	if f, ok := statNumber(v); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+//statJSON ... the persisted form of a Stat
+type statJSON struct {
+	Key     string   `json:"key"`
+	LongKey string   `json:"longKey"`
+	Value   *float64 `json:"value,omitempty"` // numbers
+	Text    string   `json:"text,omitempty"`  // e.g. a "15-1" record
+}
+
+//MarshalJSON writes numbers to "value" and anything else to "text" so each column has a single type
+func (st Stat) MarshalJSON() ([]byte, error) {
+	sj := statJSON{Key: st.Key, LongKey: st.LongKey}
+	if _, isText := st.Value.(string); !isText {
+		if f, ok := statNumber(st.Value); ok {
+			sj.Value = &f
+			return json.Marshal(sj)
+		}
+	}
+	if st.Value != nil {
+		sj.Text = statString(st.Value)
+	}
+	return json.Marshal(sj)
+}
+
+//UnmarshalJSON reads the persisted form, and the earlier form with any json type in "value"
+func (st *Stat) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Key     string      `json:"key"`
+		LongKey string      `json:"longKey"`
+		Value   interface{} `json:"value"`
+		Text    *string     `json:"text"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	st.Key, st.LongKey, st.Value = raw.Key, raw.LongKey, raw.Value
+	if raw.Text != nil {
+		st.Value = *raw.Text
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatVocabularyRegister(t *testing.T) {
+	v := NewStatVocabulary()
+	assert.Nil(t, v.Register(StatDef{Key: "FGM", Name: "fieldGoalsMade", Unit: UnitCount, Aggregation: AggregateSum}))
+	assert.NotNil(t, v.Register(StatDef{Key: "FGM", Name: "fgm"}), "the key is taken")
+	assert.NotNil(t, v.Register(StatDef{Key: "FG%", Name: "fieldGoalPct", Unit: UnitPercent, Aggregation: AggregateRate,
+		Numerator: "fieldGoalsMade", Denominator: "fieldGoalsAttempted"}), "attempts aren't registered")
+	assert.True(t, errors.Is(v.Map(ProviderNBA, "fgp", "fieldGoalPct"), ErrUnknownStat))
+	assert.Nil(t, v.Map(ProviderNBA, "fgm", "fieldGoalsMade"))
+	assert.Equal(t, "FGM", v.Resolve(ProviderNBA, "fgm").Key)
+	assert.Nil(t, v.Resolve(ProviderESPN, "fgm"), "the mapping is the NBA's")
+	assert.Equal(t, []StatDef{{Key: "FGM", Name: "fieldGoalsMade", Unit: UnitCount, Aggregation: AggregateSum}}, v.Definitions())
+}
+
+func TestStatVocabularyNormalize(t *testing.T) {
+	v := DefaultStatVocabulary()
+	stats, err := v.Normalize(ProviderNBA, &Stat{Key: "tpm", Value: "6"})
+	assert.Nil(t, err)
+	assert.Equal(t, []*Stat{{Key: "3PM", LongKey: "threePointersMade", Value: 6.0}}, stats)
+
+	stats, err = v.Normalize(ProviderESPN, &Stat{Key: "3PT", LongKey: "threePointFieldGoalsMade-threePointFieldGoalsAttempted", Value: "12-37"})
+	assert.Nil(t, err)
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, 37.0, stats[1].Value)
+		assert.Equal(t, "threePointersAttempted", stats[1].LongKey)
+	}
+
+	stats, err = v.Normalize(ProviderESPN, &Stat{Key: "HOME", LongKey: "home", Value: "15-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "15-1", stats[0].Value)
+
+	_, err = v.Normalize(ProviderESPN, &Stat{LongKey: "playoffSeeding", Value: 1.0})
+	assert.True(t, errors.Is(err, ErrUnknownStat))
+	_, err = v.Normalize(ProviderNBA, &Stat{Key: "fgm", Value: "DNP"})
+	assert.True(t, errors.Is(err, ErrStatType))
+	_, err = v.Normalize(ProviderESPN, &Stat{LongKey: "home", Value: 15.0})
+	assert.True(t, errors.Is(err, ErrStatType), "a record is text")
+
+	normalized, errs := v.NormalizeAll(ProviderESPN, []*Stat{{LongKey: "avgPoints", Value: 111.9}, {LongKey: "bogus", Value: 1}})
+	assert.Equal(t, []*Stat{{Key: "PPG", LongKey: "pointsPerGame", Value: 111.9}}, normalized)
+	assert.Len(t, errs, 1)
+}
+
+func TestStatVocabularyValidate(t *testing.T) {
+	v := DefaultStatVocabulary()
+	stats, err := v.Validate(ProviderESPN, "competitor 28", []*Stat{
+		{Key: "3P%", LongKey: "threePointFieldGoalPct", Value: 37.8},
+		{Key: "3P%", LongKey: "threePointPct", Value: 37.8},
+		{Key: "REB", LongKey: "avgRebounds", Value: 42.1},
+		{LongKey: "avgPointsAgainst", Value: 104.2},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []*Stat{{Key: "3P%", LongKey: "threePointPct", Value: 37.8}, {Key: "RPG", LongKey: "reboundsPerGame", Value: 42.1},
+		{Key: "OPPG", LongKey: "oppPointsPerGame", Value: 104.2}}, stats, "given twice is kept once")
+
+	stats, err = v.Validate(ProviderESPN, "team 28 total record", []*Stat{{LongKey: "wins", Value: 22.0}, {LongKey: "bogus", Value: 1.0},
+		{LongKey: "home", Value: 15.0}})
+	assert.Len(t, stats, 1)
+	var invalid *StatValidationError
+	if assert.True(t, errors.As(err, &invalid)) {
+		assert.Equal(t, "team 28 total record", invalid.Record)
+		assert.Len(t, invalid.Errs, 2)
+	}
+	assert.True(t, errors.Is(err, ErrUnknownStat))
+	assert.True(t, errors.Is(err, ErrStatType))
+}
+
+func TestStatVocabularyAggregate(t *testing.T) {
+	v := DefaultStatVocabulary()
+	games := [][]*Stat{
+		{{Key: "FGM", LongKey: "fieldGoalsMade", Value: 40.0}, {Key: "FGA", LongKey: "fieldGoalsAttempted", Value: 80.0},
+			{Key: "LEAD", LongKey: "biggestLead", Value: 10.0}, {Key: "STRK", LongKey: "streak", Value: 2.0}},
+		{{Key: "FGM", LongKey: "fieldGoalsMade", Value: 50.0}, {Key: "FGA", LongKey: "fieldGoalsAttempted", Value: 120.0},
+			{Key: "LEAD", LongKey: "biggestLead", Value: 20.0}, {Key: "STRK", LongKey: "streak", Value: -1.0}},
+	}
+	sum, _ := v.Aggregate("FGM", games...)
+	assert.Equal(t, 90.0, sum)
+	rate, _ := v.Aggregate("fieldGoalPct", games...)
+	assert.Equal(t, 45.0, rate, "made over attempted, not the mean of the percentages")
+	avg, _ := v.Aggregate("biggestLead", games...)
+	assert.Equal(t, 15.0, avg)
+	latest, _ := v.Aggregate("streak", games...)
+	assert.Equal(t, -1.0, latest)
+	_, err := v.Aggregate("home", games...)
+	assert.True(t, errors.Is(err, ErrStatType))
+	_, err = v.Aggregate("bogus")
+	assert.True(t, errors.Is(err, ErrUnknownStat))
+}
+
+func TestStatJSON(t *testing.T) {
+	b, err := json.Marshal([]*Stat{{Key: "W", LongKey: "wins", Value: 56}, {Key: "HOME", LongKey: "home", Value: "30-11"},
+		{Key: "FG", Value: "42"}})
+	assert.Nil(t, err)
+	assert.Equal(t, `[{"key":"W","longKey":"wins","value":56},{"key":"HOME","longKey":"home","text":"30-11"},`+
+		`{"key":"FG","longKey":"","text":"42"}]`, string(b))
+
+	stats := []*Stat{}
+	assert.Nil(t, json.Unmarshal(b, &stats))
+	assert.Equal(t, 56.0, stats[0].Value)
+	assert.Equal(t, "30-11", stats[1].Value)
+
+	old := Stat{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"key":"STRK","longKey":"streak","value":"W2"}`), &old))
+	assert.Equal(t, "W2", old.Value, "the earlier form kept text in value")
+}
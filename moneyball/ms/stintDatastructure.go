@@ -0,0 +1,300 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// a Stint is an uninterrupted stretch of a game with the same ten players on court, stints are
+// reconstructed by replaying the substitutions in a PlayEvent stream (see BuildStints).  Feeds rarely
+// list the players on court at the start of a period so the starters of each period are inferred from
+// who is seen in the period before being subbed in.
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	//playersOnCourt per team
+	playersOnCourt = 5
+	//freeThrowPossession is the usual weight of a free throw attempt in a possession estimate
+	freeThrowPossession = 0.44
+)
+
+//Stint ... a five-man unit for each team and what happened while it was on court
+type Stint struct {
+	EntityID
+	GameID           GameID   `json:"gameId"`
+	Sequence         int      `json:"sequence"`         // 1..n ordering of stints in the game
+	Period           int      `json:"period"`           // 1..4 quarters, 5+ overtime
+	StartClock       float64  `json:"startClock"`       // seconds remaining in the period when the stint started
+	EndClock         float64  `json:"endClock"`         // seconds remaining in the period when the stint ended
+	Duration         float64  `json:"duration"`         // seconds played
+	HomeTeamID       string   `json:"homeTeamId"`       // provider team id
+	VisitTeamID      string   `json:"visitTeamId"`      // provider team id
+	HomePlayers      []string `json:"homePlayers"`      // provider player ids, sorted
+	VisitPlayers     []string `json:"visitPlayers"`     // provider player ids, sorted
+	HomePoints       int      `json:"homePoints"`       // points scored by home during the stint
+	VisitPoints      int      `json:"visitPoints"`      // points scored by visitor during the stint
+	HomePossessions  float64  `json:"homePossessions"`  // estimated FGA + 0.44*FTA - OREB + TOV
+	VisitPossessions float64  `json:"visitPossessions"` // estimated FGA + 0.44*FTA - OREB + TOV
+}
+
+//StintTable ... the stints of one game, exported alongside the box scores
+type StintTable struct {
+	GameID GameID   `json:"gameId"`
+	League League   `json:"league"`
+	Stints []*Stint `json:"stints"`
+}
+
+//stintBuilder holds the replay state for BuildStints
+type stintBuilder struct {
+	gameID    GameID
+	home      string
+	visit     string
+	teamOf    map[string]string // player id -> team id
+	lineup    map[string][]string
+	stints    []*Stint
+	current   *Stint
+	lastHome  int
+	lastVisit int
+	lastMiss  string // team of the last missed shot or free throw, for offensive rebounds
+}
+
+//BuildStints replays the substitutions in a game's PlayEvents (ordered by Sequence) and returns the stints,
+//homeTeamID and visitTeamID are the provider team ids used in the events
+func BuildStints(events []*PlayEvent, homeTeamID string, visitTeamID string) ([]*Stint, error) {
+	if homeTeamID == "" || visitTeamID == "" {
+		return nil, fmt.Errorf("stints need both home and visit team ids")
+	}
+	b := stintBuilder{
+		home:   homeTeamID,
+		visit:  visitTeamID,
+		teamOf: playerTeams(events),
+		lineup: map[string][]string{homeTeamID: {}, visitTeamID: {}},
+	}
+	if len(events) > 0 {
+		b.gameID = events[0].GameID
+	}
+
+	// events grouped by period, in order of play
+	periods := []int{}
+	byPeriod := map[int][]*PlayEvent{}
+	for _, ev := range events {
+		if _, ok := byPeriod[ev.Period]; !ok {
+			periods = append(periods, ev.Period)
+		}
+		byPeriod[ev.Period] = append(byPeriod[ev.Period], ev)
+	}
+
+	for _, period := range periods {
+		pe := byPeriod[period]
+		for _, team := range []string{b.home, b.visit} {
+			b.lineup[team] = b.inferStarters(pe, team)
+		}
+		b.open(period, pe[0].ClockSeconds)
+		substituting := false
+		for _, ev := range pe {
+			if ev.Type == PlaySubstitution {
+				if !substituting {
+					b.close(ev.ClockSeconds)
+					substituting = true
+				}
+				b.substitute(ev)
+				continue
+			}
+			if substituting {
+				b.open(period, b.stints[len(b.stints)-1].EndClock)
+				substituting = false
+			}
+			b.tally(ev)
+		}
+		if substituting {
+			b.open(period, b.stints[len(b.stints)-1].EndClock)
+		}
+		b.close(pe[len(pe)-1].ClockSeconds)
+	}
+	return b.stints, nil
+}
+
+//playerTeams maps each player to the team they are credited with anywhere in the game, substitutions
+//credit both players to the event team
+func playerTeams(events []*PlayEvent) map[string]string {
+	teams := map[string]string{}
+	for _, ev := range events {
+		for _, p := range ev.Players {
+			switch {
+			case p.TeamID != "":
+				teams[p.PlayerID] = p.TeamID
+			case ev.Type == PlaySubstitution && ev.TeamID != "":
+				if _, ok := teams[p.PlayerID]; !ok {
+					teams[p.PlayerID] = ev.TeamID
+				}
+			}
+		}
+	}
+	return teams
+}
+
+//inferStarters finds the players of team on court at the start of a period: anyone seen in the period
+//before being subbed in.  Short lineups (a starter that never shows up in the feed) are filled from the
+//lineup that ended the previous period
+func (b *stintBuilder) inferStarters(events []*PlayEvent, team string) []string {
+	starters := []string{}
+	subbedIn := map[string]bool{}
+	for _, ev := range events {
+		for _, p := range ev.Players {
+			if b.teamOf[p.PlayerID] != team || subbedIn[p.PlayerID] || sliceContains(starters, p.PlayerID) {
+				continue
+			}
+			if ev.Type == PlaySubstitution && p.Role == RoleSubIn {
+				subbedIn[p.PlayerID] = true
+				continue
+			}
+			starters = append(starters, p.PlayerID)
+		}
+	}
+	for _, id := range b.lineup[team] {
+		if len(starters) >= playersOnCourt {
+			break
+		}
+		if !subbedIn[id] && !sliceContains(starters, id) {
+			starters = append(starters, id)
+		}
+	}
+	if len(starters) > playersOnCourt {
+		starters = starters[:playersOnCourt]
+	}
+	return starters
+}
+
+func (b *stintBuilder) open(period int, clock float64) {
+	b.current = &Stint{
+		GameID:       b.gameID,
+		Period:       period,
+		StartClock:   clock,
+		HomeTeamID:   b.home,
+		VisitTeamID:  b.visit,
+		HomePlayers:  sortedCopy(b.lineup[b.home]),
+		VisitPlayers: sortedCopy(b.lineup[b.visit]),
+	}
+}
+
+//close ends the current stint, empty stints (subs at the start of a period) are dropped
+func (b *stintBuilder) close(clock float64) {
+	s := b.current
+	if s == nil {
+		return
+	}
+	b.current = nil
+	s.EndClock = clock
+	s.Duration = s.StartClock - s.EndClock
+	if s.Duration <= 0 && s.HomePoints == 0 && s.VisitPoints == 0 && s.HomePossessions == 0 &&
+		s.VisitPossessions == 0 {
+		return
+	}
+	s.Sequence = len(b.stints) + 1
+	s.ID = fmt.Sprintf("%s.%d", b.gameID, s.Sequence)
+	b.stints = append(b.stints, s)
+}
+
+func (b *stintBuilder) substitute(ev *PlayEvent) {
+	in, out := ev.Player(RoleSubIn), ev.Player(RoleSubOut)
+	team := ev.TeamID
+	if team == "" && out != nil {
+		team = b.teamOf[out.PlayerID]
+	}
+	if team == "" && in != nil {
+		team = b.teamOf[in.PlayerID]
+	}
+	lineup, ok := b.lineup[team]
+	if !ok {
+		return
+	}
+	if out != nil {
+		for i, id := range lineup {
+			if id == out.PlayerID {
+				lineup = append(lineup[:i:i], lineup[i+1:]...)
+				break
+			}
+		}
+	}
+	if in != nil && !sliceContains(lineup, in.PlayerID) {
+		lineup = append(lineup, in.PlayerID)
+	}
+	b.lineup[team] = lineup
+}
+
+//tally credits points (from the running score) and possession estimates to the current stint
+func (b *stintBuilder) tally(ev *PlayEvent) {
+	s := b.current
+	if s == nil {
+		return
+	}
+	if ev.HomeScore > b.lastHome {
+		s.HomePoints += ev.HomeScore - b.lastHome
+	}
+	if ev.VisitScore > b.lastVisit {
+		s.VisitPoints += ev.VisitScore - b.lastVisit
+	}
+	if ev.HomeScore > 0 || ev.VisitScore > 0 {
+		b.lastHome, b.lastVisit = ev.HomeScore, ev.VisitScore
+	}
+
+	possessions := map[string]*float64{b.home: &s.HomePossessions, b.visit: &s.VisitPossessions}
+	p, ok := possessions[ev.TeamID]
+	if !ok {
+		return
+	}
+	switch ev.Type {
+	case PlayShot:
+		*p++
+		if ev.ShotResult == ShotMissed {
+			b.lastMiss = ev.TeamID
+		}
+	case PlayFreeThrow:
+		*p += freeThrowPossession
+		if ev.ShotResult == ShotMissed {
+			b.lastMiss = ev.TeamID
+		}
+	case PlayRebound:
+		if b.lastMiss == ev.TeamID {
+			*p--
+		}
+		b.lastMiss = ""
+	case PlayTurnover:
+		*p++
+	}
+}
+
+func sortedCopy(ids []string) []string {
+	c := append([]string{}, ids...)
+	sort.Strings(c)
+	return c
+}
@@ -0,0 +1,94 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tPlay(seq int, clock float64, t PlayEventType, team string, home int, visit int, players ...*PlayEventPlayer) *PlayEvent {
+	return &PlayEvent{GameID: "g1", Sequence: seq, Period: 1, ClockSeconds: clock, Type: t, TeamID: team,
+		HomeScore: home, VisitScore: visit, Players: players}
+}
+
+func tPlayer(id string, team string, role string) *PlayEventPlayer {
+	return &PlayEventPlayer{PlayerID: id, TeamID: team, Role: role}
+}
+
+func TestBuildStints(t *testing.T) {
+	made := tPlay(2, 700, PlayShot, "H", 2, 0, tPlayer("h1", "H", RoleShooter), tPlayer("h2", "H", RoleAssist))
+	made.ShotResult = ShotMade
+	miss := tPlay(3, 680, PlayShot, "V", 2, 0, tPlayer("v1", "V", RoleShooter))
+	miss.ShotResult = ShotMissed
+	late := tPlay(10, 590, PlayShot, "H", 4, 0, tPlayer("h6", "H", RoleShooter))
+	late.ShotResult = ShotMade
+	events := []*PlayEvent{
+		tPlay(1, 720, PlayPeriodStart, "", 0, 0),
+		made,
+		miss,
+		tPlay(4, 678, PlayRebound, "H", 2, 0, tPlayer("h3", "H", RoleRebounder)),
+		tPlay(5, 670, PlayFoul, "H", 2, 0, tPlayer("h4", "H", RoleFouler), tPlayer("v2", "V", RoleFouled)),
+		tPlay(6, 650, PlayFoul, "V", 2, 0, tPlayer("v3", "V", RoleFouler), tPlayer("h1", "H", RoleFouled)),
+		tPlay(7, 640, PlayViolation, "V", 2, 0, tPlayer("v4", "V", RolePrimary)),
+		tPlay(8, 630, PlayViolation, "V", 2, 0, tPlayer("v5", "V", RolePrimary)),
+		// h6 comes in for h5, who was never seen before leaving so is inferred as a starter
+		tPlay(9, 600, PlaySubstitution, "H", 2, 0, tPlayer("h5", "H", RoleSubOut), tPlayer("h6", "H", RoleSubIn)),
+		late,
+		tPlay(11, 0, PlayPeriodEnd, "", 4, 0),
+	}
+
+	stints, err := BuildStints(events, "H", "V")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(stints))
+
+	first := stints[0]
+	assert.Equal(t, []string{"h1", "h2", "h3", "h4", "h5"}, first.HomePlayers)
+	assert.Equal(t, []string{"v1", "v2", "v3", "v4", "v5"}, first.VisitPlayers)
+	assert.Equal(t, 720.0, first.StartClock)
+	assert.Equal(t, 600.0, first.EndClock)
+	assert.Equal(t, 120.0, first.Duration)
+	assert.Equal(t, 2, first.HomePoints)
+	assert.Equal(t, 1.0, first.HomePossessions)
+	assert.Equal(t, 1.0, first.VisitPossessions, "defensive rebound does not take a possession back")
+
+	second := stints[1]
+	assert.Equal(t, []string{"h1", "h2", "h3", "h4", "h6"}, second.HomePlayers)
+	assert.Equal(t, 600.0, second.StartClock)
+	assert.Equal(t, 0.0, second.EndClock)
+	assert.Equal(t, 2, second.HomePoints)
+	assert.Equal(t, 0, second.VisitPoints)
+	assert.Equal(t, "g1.2", second.ID)
+
+	_, err = BuildStints(events, "", "V")
+	assert.NotNil(t, err)
+}
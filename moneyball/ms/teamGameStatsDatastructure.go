@@ -0,0 +1,283 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// TeamGameStats are a team's box score totals for a game in one vocabulary whatever the provider, GameTeamStats
+// keep the provider's stats as given.  The fields are stats of the StatVocabulary, provider names are resolved
+// with the table of the Source, ESPN's "fieldGoalsMade-fieldGoalsAttempted" style names carry two stats in one
+// "42-88" value.  Percentages are derived from made and attempted, not stored
+
+import (
+	"fmt"
+)
+
+//TeamGameStats ... a team's totals for a game, one row per team per game
+type TeamGameStats struct {
+	GameID                 GameID   `json:"gameId"`
+	League                 League   `json:"league"`
+	TeamID                 string   `json:"teamId"`                 // provider team id "1610612761", "28"
+	Abbreviation           string   `json:"abbreviation,omitempty"` // "TOR"
+	HomeAway               string   `json:"homeAway,omitempty"`     // "home" or "away"
+	Source                 Provider `json:"source"`                 // provider the totals come from
+	Points                 int      `json:"points"`
+	FieldGoalsMade         int      `json:"fieldGoalsMade"`
+	FieldGoalsAttempted    int      `json:"fieldGoalsAttempted"`
+	ThreePointersMade      int      `json:"threePointersMade"`
+	ThreePointersAttempted int      `json:"threePointersAttempted"`
+	FreeThrowsMade         int      `json:"freeThrowsMade"`
+	FreeThrowsAttempted    int      `json:"freeThrowsAttempted"`
+	OffensiveRebounds      int      `json:"offensiveRebounds"`
+	DefensiveRebounds      int      `json:"defensiveRebounds"`
+	Rebounds               int      `json:"rebounds"`     // offensive and defensive rebounds of the players
+	TeamRebounds           int      `json:"teamRebounds"` // rebounds credited to the team, not in Rebounds
+	Assists                int      `json:"assists"`
+	Steals                 int      `json:"steals"`
+	Blocks                 int      `json:"blocks"`
+	Turnovers              int      `json:"turnovers"`     // turnovers of the players
+	TeamTurnovers          int      `json:"teamTurnovers"` // e.g. shot clock violations, not in Turnovers
+	PersonalFouls          int      `json:"personalFouls"`
+	TechnicalFouls         int      `json:"technicalFouls"`
+	FlagrantFouls          int      `json:"flagrantFouls"`
+	FastBreakPoints        int      `json:"fastBreakPoints"`
+	PointsInPaint          int      `json:"pointsInPaint"`
+	SecondChancePoints     int      `json:"secondChancePoints"`
+	PointsOffTurnovers     int      `json:"pointsOffTurnovers"`
+	BiggestLead            int      `json:"biggestLead"`
+	LongestRun             int      `json:"longestRun"`      // most unanswered points
+	TimesTied              int      `json:"timesTied"`       // of the game, the same for both teams
+	LeadChanges            int      `json:"leadChanges"`     // of the game, the same for both teams
+	Other                  []*Stat  `json:"other,omitempty"` // provider stats outside the vocabulary
+}
+
+//teamStatFields ... the field of each stat of the vocabulary TeamGameStats holds, in column order
+var teamStatFields = []struct {
+	name  string
+	field func(ts *TeamGameStats) *int
+}{
+	{"points", func(ts *TeamGameStats) *int { return &ts.Points }},
+	{"fieldGoalsMade", func(ts *TeamGameStats) *int { return &ts.FieldGoalsMade }},
+	{"fieldGoalsAttempted", func(ts *TeamGameStats) *int { return &ts.FieldGoalsAttempted }},
+	{"threePointersMade", func(ts *TeamGameStats) *int { return &ts.ThreePointersMade }},
+	{"threePointersAttempted", func(ts *TeamGameStats) *int { return &ts.ThreePointersAttempted }},
+	{"freeThrowsMade", func(ts *TeamGameStats) *int { return &ts.FreeThrowsMade }},
+	{"freeThrowsAttempted", func(ts *TeamGameStats) *int { return &ts.FreeThrowsAttempted }},
+	{"offensiveRebounds", func(ts *TeamGameStats) *int { return &ts.OffensiveRebounds }},
+	{"defensiveRebounds", func(ts *TeamGameStats) *int { return &ts.DefensiveRebounds }},
+	{"rebounds", func(ts *TeamGameStats) *int { return &ts.Rebounds }},
+	{"teamRebounds", func(ts *TeamGameStats) *int { return &ts.TeamRebounds }},
+	{"assists", func(ts *TeamGameStats) *int { return &ts.Assists }},
+	{"steals", func(ts *TeamGameStats) *int { return &ts.Steals }},
+	{"blocks", func(ts *TeamGameStats) *int { return &ts.Blocks }},
+	{"turnovers", func(ts *TeamGameStats) *int { return &ts.Turnovers }},
+	{"teamTurnovers", func(ts *TeamGameStats) *int { return &ts.TeamTurnovers }},
+	{"personalFouls", func(ts *TeamGameStats) *int { return &ts.PersonalFouls }},
+	{"technicalFouls", func(ts *TeamGameStats) *int { return &ts.TechnicalFouls }},
+	{"flagrantFouls", func(ts *TeamGameStats) *int { return &ts.FlagrantFouls }},
+	{"fastBreakPoints", func(ts *TeamGameStats) *int { return &ts.FastBreakPoints }},
+	{"pointsInPaint", func(ts *TeamGameStats) *int { return &ts.PointsInPaint }},
+	{"secondChancePoints", func(ts *TeamGameStats) *int { return &ts.SecondChancePoints }},
+	{"pointsOffTurnovers", func(ts *TeamGameStats) *int { return &ts.PointsOffTurnovers }},
+	{"biggestLead", func(ts *TeamGameStats) *int { return &ts.BiggestLead }},
+	{"longestRun", func(ts *TeamGameStats) *int { return &ts.LongestRun }},
+	{"timesTied", func(ts *TeamGameStats) *int { return &ts.TimesTied }},
+	{"leadChanges", func(ts *TeamGameStats) *int { return &ts.LeadChanges }},
+}
+
+//teamStatField returns the field of a canonical stat, nil when TeamGameStats doesn't hold it
+func teamStatField(ts *TeamGameStats, name string) *int {
+	for _, st := range teamStatFields {
+		if st.name == name {
+			return st.field(ts)
+		}
+	}
+	return nil
+}
+
+//NewTeamGameStats ...
+func NewTeamGameStats(league League, gameID GameID, teamID string, source Provider) *TeamGameStats {
+	return &TeamGameStats{GameID: gameID, League: league, TeamID: teamID, Source: source}
+}
+
+//Set sets the stat with a name the vocabulary knows for the Source, a "made-attempted" name sets both from
+//a "42-88" value, false when the name is unknown, the value of the wrong type or the stat derived e.g. a percentage
+func (ts *TeamGameStats) Set(name string, value interface{}) bool {
+	stats, err := DefaultStatVocabulary().Normalize(ts.Source, &Stat{LongKey: name, Value: value})
+	if err != nil {
+		return false
+	}
+	for _, st := range stats {
+		if teamStatField(ts, st.LongKey) == nil {
+			return false
+		}
+	}
+	for _, st := range stats {
+		*teamStatField(ts, st.LongKey) = int(statFloat(st.Value))
+	}
+	return true
+}
+
+//SetAll sets the provider stats as Set does, the error is a *StatValidationError for the stats that aren't set
+func (ts *TeamGameStats) SetAll(record string, stats []*Stat) error {
+	errs := []error{}
+	for _, st := range stats {
+		normalized, err := DefaultStatVocabulary().Normalize(ts.Source, st)
+		for _, ns := range normalized {
+			if err == nil && teamStatField(ts, ns.LongKey) == nil {
+				err = fmt.Errorf("stat %s: not held by TeamGameStats", ns.LongKey)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, ns := range normalized {
+			*teamStatField(ts, ns.LongKey) = int(statFloat(ns.Value))
+		}
+	}
+	if len(errs) > 0 {
+		return &StatValidationError{Provider: ts.Source, Record: record, Errs: errs}
+	}
+	return nil
+}
+
+//Get returns the value of the stat with a canonical name or key, false when TeamGameStats doesn't hold it
+func (ts *TeamGameStats) Get(name string) (int, bool) {
+	def := DefaultStatVocabulary().Def(name)
+	if def == nil || teamStatField(ts, def.Name) == nil {
+		return 0, false
+	}
+	return *teamStatField(ts, def.Name), true
+}
+
+//Add normalizes a provider stat, stats unknown to the vocabulary are kept in Other and derived ones dropped
+func (ts *TeamGameStats) Add(st *Stat) {
+	stats, err := DefaultStatVocabulary().Normalize(ts.Source, st)
+	if err != nil {
+		ts.Other = append(ts.Other, st)
+		return
+	}
+	for _, ns := range stats {
+		if field := teamStatField(ts, ns.LongKey); field != nil {
+			*field = int(statFloat(ns.Value))
+		}
+	}
+}
+
+func pct(made int, attempted int) float64 {
+	if attempted == 0 {
+		return 0
+	}
+	return float64(made) / float64(attempted)
+}
+
+//FieldGoalPct ... .477
+func (ts *TeamGameStats) FieldGoalPct() float64 {
+	return pct(ts.FieldGoalsMade, ts.FieldGoalsAttempted)
+}
+
+//ThreePointPct ...
+func (ts *TeamGameStats) ThreePointPct() float64 {
+	return pct(ts.ThreePointersMade, ts.ThreePointersAttempted)
+}
+
+//FreeThrowPct ...
+func (ts *TeamGameStats) FreeThrowPct() float64 {
+	return pct(ts.FreeThrowsMade, ts.FreeThrowsAttempted)
+}
+
+//Stats renders the totals as Stats of the vocabulary e.g. {Key: "FGM", LongKey: "fieldGoalsMade", Value: 42.0}
+func (ts *TeamGameStats) Stats() []*Stat {
+	stats := []*Stat{}
+	for _, st := range teamStatFields {
+		def := DefaultStatVocabulary().Def(st.name)
+		stats = append(stats, &Stat{Key: def.Key, LongKey: def.Name, Value: float64(*st.field(ts))})
+	}
+	return stats
+}
+
+//TeamGameStats normalizes the provider's team totals to the vocabulary
+func (gts *GameTeamStats) TeamGameStats(league League, source Provider) *TeamGameStats {
+	ts := NewTeamGameStats(league, GameID(gts.GameID), gts.TeamID, source)
+	ts.HomeAway = gts.HomeAway
+	for _, st := range gts.Stats {
+		ts.Add(st)
+	}
+	return ts
+}
+
+//TeamGameStats normalizes the team totals of the summary
+func (gs *GameSummary) TeamGameStats(source Provider) []*TeamGameStats {
+	stats := []*TeamGameStats{}
+	for _, gts := range gs.Teams {
+		ts := gts.TeamGameStats(gs.League, source)
+		ts.GameID = gs.GameID
+		stats = append(stats, ts)
+	}
+	return stats
+}
+
+//AttachTeamGameStats sets the GameStats of the competitors from totals marked home or away
+func (e *Event) AttachTeamGameStats(stats ...*TeamGameStats) {
+	for _, ts := range stats {
+		switch {
+		case ts == nil:
+		case ts.HomeAway == "home" && e.HomeTeam != nil:
+			e.HomeTeam.GameStats = ts
+		case ts.HomeAway == "away" && e.VisitTeam != nil:
+			e.VisitTeam.GameStats = ts
+		}
+	}
+}
+
+//TeamGameStatsTable ... team totals of a league's games, persisted as their own table
+type TeamGameStatsTable struct {
+	League League
+	Stats  []*TeamGameStats
+}
+
+//Add appends the totals, replacing earlier totals of the same team and game
+func (t *TeamGameStatsTable) Add(stats ...*TeamGameStats) {
+	for _, ts := range stats {
+		if ts == nil {
+			continue
+		}
+		replaced := false
+		for i, old := range t.Stats {
+			if old.GameID == ts.GameID && old.TeamID == ts.TeamID {
+				t.Stats[i] = ts
+				replaced = true
+			}
+		}
+		if !replaced {
+			t.Stats = append(t.Stats, ts)
+		}
+	}
+}
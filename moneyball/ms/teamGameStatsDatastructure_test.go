@@ -0,0 +1,115 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamGameStatsVocabulary(t *testing.T) {
+	ts := NewTeamGameStats(LeagueNBA, "401161559", "28", ProviderESPN)
+	assert.True(t, ts.Set("FGM", 40.0))
+	assert.True(t, ts.Set("threePointFieldGoalsMade-threePointFieldGoalsAttempted", "12-37"))
+	assert.True(t, ts.Set("fouls", "21"))
+	assert.False(t, ts.Set("pFouls", "21"), "an NBA name")
+	assert.False(t, ts.Set("fieldGoalsMade-fieldGoalsAttempted", "40"), "a made-attempted value needs both")
+	assert.False(t, ts.Set("playoffSeed", 1))
+	assert.Equal(t, 40, ts.FieldGoalsMade)
+	assert.Equal(t, 37, ts.ThreePointersAttempted)
+	fouls, ok := ts.Get("personalFouls")
+	assert.True(t, ok)
+	assert.Equal(t, 21, fouls)
+	_, ok = ts.Get("playoffSeed")
+	assert.False(t, ok)
+
+	ts.Add(&Stat{Key: "FT%", LongKey: "freeThrowPct", Value: 80.0})
+	ts.Add(&Stat{Key: "Largest Lead", LongKey: "largestLead", Value: 15.0})
+	ts.Add(&Stat{Key: "Flagrant Fouls", LongKey: "flagrantFoulsCommitted", Value: 1.0})
+	assert.Equal(t, 15, ts.BiggestLead)
+	if assert.Len(t, ts.Other, 1) {
+		assert.Equal(t, "flagrantFoulsCommitted", ts.Other[0].LongKey)
+	}
+
+	ts.FieldGoalsAttempted = 80
+	assert.Equal(t, 0.5, ts.FieldGoalPct())
+	assert.Equal(t, 0.0, ts.FreeThrowPct(), "no attempts")
+	stats := ts.Stats()
+	assert.Equal(t, len(teamStatFields), len(stats))
+	assert.Equal(t, &Stat{Key: "FGM", LongKey: "fieldGoalsMade", Value: 40.0}, findStat(stats, "FGM"))
+}
+
+func TestTeamGameStatsSetAll(t *testing.T) {
+	ts := NewTeamGameStats(LeagueNBA, "0021900001", "1610612761", ProviderNBA)
+	err := ts.SetAll("game 0021900001 team 1610612761", []*Stat{{LongKey: "fgm", Value: 42}, {LongKey: "totReb", Value: "51"},
+		{LongKey: "fgp", Value: 47.7}, {LongKey: "short_timeout_remaining", Value: 0}})
+	assert.Equal(t, 42, ts.FieldGoalsMade)
+	assert.Equal(t, 51, ts.Rebounds)
+	var invalid *StatValidationError
+	if assert.True(t, errors.As(err, &invalid)) {
+		assert.Len(t, invalid.Errs, 2, "a derived stat and an unknown one")
+	}
+	assert.Nil(t, ts.SetAll("game 0021900001 team 1610612761", []*Stat{{LongKey: "pFouls", Value: 21}}))
+	assert.Equal(t, 21, ts.PersonalFouls)
+}
+
+func TestGameSummaryTeamGameStats(t *testing.T) {
+	gs := GameSummary{GameID: "401161559", League: LeagueNBA, Teams: []*GameTeamStats{
+		{TeamID: "13", GameID: "401161559", HomeAway: "away", Stats: []*Stat{
+			{Key: "FG", LongKey: "fieldGoalsMade-fieldGoalsAttempted", Value: "42-88"},
+			{Key: "Rebounds", LongKey: "totalRebounds", Value: 45.0}}}}}
+	stats := gs.TeamGameStats(ProviderESPN)
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, GameID("401161559"), stats[0].GameID)
+		assert.Equal(t, "away", stats[0].HomeAway)
+		assert.Equal(t, 88, stats[0].FieldGoalsAttempted)
+		assert.Equal(t, 45, stats[0].Rebounds)
+	}
+
+	e := Event{HomeTeam: &Competitor{}, VisitTeam: &Competitor{}}
+	e.AttachTeamGameStats(stats...)
+	assert.True(t, stats[0] == e.VisitTeam.GameStats)
+	assert.Nil(t, e.HomeTeam.GameStats)
+
+	table := TeamGameStatsTable{League: LeagueNBA}
+	table.Add(stats...)
+	table.Add(NewTeamGameStats(LeagueNBA, "401161559", "13", ProviderNBA), nil)
+	if assert.Len(t, table.Stats, 1, "the later totals replace the earlier ones") {
+		assert.Equal(t, ProviderNBA, table.Stats[0].Source)
+	}
+	b := bytes.Buffer{}
+	assert.Nil(t, table.marshalNBJSON(&b))
+	assert.Contains(t, b.String(), `"fieldGoalsAttempted":0`)
+	assert.Equal(t, "teamgamestatsNBA", table.tableName())
+}
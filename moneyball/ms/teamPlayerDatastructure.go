@@ -47,9 +47,12 @@ type Team struct {
 	TeamIDESPN   string  `json:"teamIdESPN,omitempty"`
 	Abbreviation string  `json:"abbreviation"`
 	Name         string  `json:"name"`
-	Location     string  `json:"teamLocation"` // e.g. "Atlanta" Hawks
+	Location     string  `json:"teamLocation"`         // e.g. "Atlanta" Hawks
+	Conference   string  `json:"conference,omitempty"` // "East"
+	Division     string  `json:"division,omitempty"`   // "Southeast"
 	Logos        []*Link `json:"logos,omitempty"`
 	Links        []*Link `json:"links,omitempty"`
+	Venue        *Venue  `json:"venue,omitempty"` // home arena
 	//TODO: how to treat historic record?
 	Records []*TeamSeasonRecords `json:"records,omitempty"`
 	Rosters []*TeamSeasonRoster  `json:"rosters,omitempty"` // roster is copied to Competitor for a given game
@@ -57,17 +60,36 @@ type Team struct {
 
 //TeamSeasonRecords ...
 type TeamSeasonRecords struct {
-	Season  *Season `json:"season,omitempty"`
-	Summary string  `json:"summary,omitempty"`
-	Stats   []*Stat `json:"teamStat,omitempty"`
+	Season      *Season `json:"season,omitempty"`
+	Type        string  `json:"type,omitempty"`        // "total", "home", "road"
+	Description string  `json:"description,omitempty"` // "Overall Record"
+	Summary     string  `json:"summary,omitempty"`
+	Stats       []*Stat `json:"teamStat,omitempty"`
 }
 
 //GamePlayersStats ... may be used in boxScores to do game stats associated with Player on a Team
 type GamePlayersStats struct {
-	PlayerID string  `json:"gamePlayerID"`   // for a player
-	TeamID   string  `json:"teamID"`         // on a team
-	GameID   string  `json:"gameID"`         // during a game
-	Stats    []*Stat `json:"gamePlayerStat"` // here is a slice of pointers to stats the stats
+	PlayerID   string  `json:"gamePlayerID"`         // for a player
+	TeamID     string  `json:"teamID"`               // on a team
+	GameID     string  `json:"gameID"`               // during a game
+	Stats      []*Stat `json:"gamePlayerStat"`       // here is a slice of pointers to stats the stats
+	Starter    bool    `json:"starter,omitempty"`    // in the starting lineup
+	DidNotPlay bool    `json:"didNotPlay,omitempty"` // dressed but didn't play, see Reason
+	Reason     string  `json:"reason,omitempty"`     // e.g. "COACH'S DECISION"
+}
+
+//Stat returns the stat with key or long key k, or nil
+func (ps *GamePlayersStats) Stat(k string) *Stat {
+	return findStat(ps.Stats, k)
+}
+
+func findStat(stats []*Stat, k string) *Stat {
+	for _, st := range stats {
+		if st.Key == k || st.LongKey == k {
+			return st
+		}
+	}
+	return nil
 }
 
 //Stat .. a well known stat both short/long verions if exists
@@ -79,8 +101,17 @@ type Stat struct {
 
 //TeamSeasonRoster ...
 type TeamSeasonRoster struct {
-	Season Season    `json:"season"`
-	Roster []*Player `json:"roster"`
+	Season           Season     `json:"season"`
+	TeamAbbreviation string     `json:"team,omitempty"` // canonical abbreviation of the team the roster is for e.g. "TOR"
+	AsOf             *time.Time `json:"asOf,omitempty"` // when the roster was fetched
+	Roster           []*Player  `json:"roster"`
+}
+
+//Copy returns a copy of the roster with its own player list, e.g. to freeze the game-day roster of a Competitor
+func (r *TeamSeasonRoster) Copy() *TeamSeasonRoster {
+	c := *r
+	c.Roster = append([]*Player{}, r.Roster...)
+	return &c
 }
 
 //Player ...
@@ -88,6 +119,7 @@ type Player struct {
 	EntityID    EntityID
 	IDESPN      string             `json:"idESPN,omitempty"` // e.g. "id":"3012",
 	IDNBA       string             `json:"idNBA,omitempty"`
+	League      League             `json:"league,omitempty"`      // the league whose crosswalk keys the player
 	FullName    string             `json:"fullName,omitempty"`    // e.g. "fullName":"Kyle Lowry",
 	DisplayName string             `json:"displayName,omitempty"` // e.g. "displayName":"Kyle Lowry",
 	ShortName   string             `json:"shortName,omitempty"`   // e.g."K. Lowry",
@@ -98,6 +130,39 @@ type Player struct {
 	Team        *Team              `json:"team" binding:"required"`
 	Active      bool               `json:"active"`
 	Career      *PlayerTeamsCareer `json:"career,omitempty"`
+	Height      int                `json:"height,omitempty"`    // inches e.g. 72
+	Weight      int                `json:"weight,omitempty"`    // pounds e.g. 196
+	BirthDate   *time.Time         `json:"birthDate,omitempty"` // e.g. 1986-03-25
+	Experience  int                `json:"experience"`          // years in the league, 0 for rookies
+	College     string             `json:"college,omitempty"`   // e.g. "Villanova"
+	Draft       *PlayerDraft       `json:"draft,omitempty"`
+	Injuries    []*PlayerInjury    `json:"injuries,omitempty"`
+	Splits      []*PlayerSplit     `json:"splits,omitempty"` // season stat lines e.g. "2019-20 Regular Season"
+}
+
+//PlayerDraft ... where a player was drafted, nil for undrafted players
+type PlayerDraft struct {
+	Year             int    `json:"year"`                  // 2006
+	Round            int    `json:"round"`                 // 1
+	Pick             int    `json:"pick"`                  // 24
+	TeamAbbreviation string `json:"team,omitempty"`        // "MEM", the drafting team
+	Description      string `json:"description,omitempty"` // "2006: Rd 1, Pk 24 (MEM)"
+}
+
+//PlayerInjury ... a reported injury and the player's availability
+type PlayerInjury struct {
+	Status     string     `json:"status"`               // "Out", "Day-To-Day"
+	Date       *time.Time `json:"date,omitempty"`       // when reported
+	Type       string     `json:"type,omitempty"`       // "Knee"
+	Side       string     `json:"side,omitempty"`       // "Left"
+	Detail     string     `json:"detail,omitempty"`     // "Soreness"
+	ReturnDate *time.Time `json:"returnDate,omitempty"` // expected return
+}
+
+//PlayerSplit ... a player's stats over a slice of games, e.g. a season or home games
+type PlayerSplit struct {
+	Name  string  `json:"name"` // "2019-20 Regular Season Stats"
+	Stats []*Stat `json:"stats"`
 }
 
 //PlayerAssignment is a record in the history of  a player inclusive of volunteer,
@@ -123,3 +188,30 @@ type Position struct {
 	Name         string `json:"name"`
 	Abbreviation string `json:"abbreviation"`
 }
+
+//AttachRoster freezes a copy of the team's roster on the competitor as the game-day roster
+func (c *Competitor) AttachRoster(r *TeamSeasonRoster) {
+	if r == nil {
+		return
+	}
+	c.Roster = r.Copy()
+}
+
+//AttachRosters attaches the game-day roster of each competitor of the event, rosters are matched on the
+//canonical team abbreviation, it returns the number of competitors that got a roster
+func (e *Event) AttachRosters(rosters ...*TeamSeasonRoster) int {
+	attached := 0
+	for _, c := range []*Competitor{e.HomeTeam, e.VisitTeam} {
+		if c == nil {
+			continue
+		}
+		for _, r := range rosters {
+			if r != nil && r.TeamAbbreviation != "" && r.TeamAbbreviation == c.Abbreviation {
+				c.AttachRoster(r)
+				attached++
+				break
+			}
+		}
+	}
+	return attached
+}
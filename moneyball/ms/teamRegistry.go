@@ -0,0 +1,305 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// the TeamRegistry is the canonical directory of teams for a league, it resolves every provider id
+// (NBA teamId, ESPN id and uid), tricode/abbreviation and historical franchise name to one Team so that
+// both the nba and espn MarshalMS paths key teams the same way.  The registry starts from an offline
+// snapshot (see teamRegistrySnapshot.go) and is refreshed from the NBA teams.json and ESPN /teams feeds.
+
+import (
+	"strings"
+	"sync"
+)
+
+//TeamAlias ... a historical identity of a franchise e.g. the New Jersey Nets (NJN) for the Brooklyn Nets
+type TeamAlias struct {
+	Abbreviation string `json:"abbreviation"`          // "NJN"
+	Location     string `json:"location,omitempty"`    // "New Jersey"
+	Name         string `json:"name,omitempty"`        // "Nets"
+	FirstSeason  int    `json:"firstSeason,omitempty"` // 1977, season year the alias was first used
+	LastSeason   int    `json:"lastSeason,omitempty"`  // 2011, season year the alias was last used
+}
+
+//FullName is the location and name e.g. "New Jersey Nets"
+func (a *TeamAlias) FullName() string {
+	return strings.TrimSpace(a.Location + " " + a.Name)
+}
+
+//TeamRegistry ... canonical teams of a league and the indexes that resolve them
+type TeamRegistry struct {
+	League  League
+	mu      sync.RWMutex
+	teams   []*Team
+	aliases map[*Team][]TeamAlias
+	index   map[string]*Team
+}
+
+const (
+	keyNBA     = "nba:"
+	keyESPN    = "espn:"
+	keyAbbr    = "abbr:"
+	keyName    = "name:"
+	uidTeamSep = "~t:"
+)
+
+//NewTeamRegistry returns an empty registry for league
+func NewTeamRegistry(league League) *TeamRegistry {
+	return &TeamRegistry{League: league, aliases: map[*Team][]TeamAlias{}, index: map[string]*Team{}}
+}
+
+var (
+	nbaTeamRegistry     *TeamRegistry
+	nbaTeamRegistryOnce sync.Once
+)
+
+//NBATeamRegistry returns the shared NBA registry, loaded from the offline snapshot on first use
+func NBATeamRegistry() *TeamRegistry {
+	nbaTeamRegistryOnce.Do(func() {
+		nbaTeamRegistry = NewTeamRegistry(LeagueNBA)
+		for _, entry := range nbaTeamSnapshot {
+			t := entry.team
+			nbaTeamRegistry.Register(&t, entry.aliases...)
+		}
+	})
+	return nbaTeamRegistry
+}
+
+var (
+	leagueRegistries   = map[League]*TeamRegistry{}
+	leagueRegistriesMu sync.Mutex
+)
+
+//TeamRegistryFor returns the shared registry of a league, the NBA registry is seeded from the offline snapshot
+//and other leagues start empty.  Provider ids are only unique within a league (ESPN team "9" is the Warriors
+//in the NBA and a different team in the WNBA) so lookups must use the registry of the event's league
+func TeamRegistryFor(league League) *TeamRegistry {
+	if league == LeagueNBA || league == "" {
+		return NBATeamRegistry()
+	}
+	leagueRegistriesMu.Lock()
+	defer leagueRegistriesMu.Unlock()
+	r, ok := leagueRegistries[league]
+	if !ok {
+		r = NewTeamRegistry(league)
+		leagueRegistries[league] = r
+	}
+	return r
+}
+
+//espnTeamID takes the team id from an ESPN uid "s:40~l:46~t:28", an id passes through
+func espnTeamID(id string) string {
+	if i := strings.LastIndex(id, uidTeamSep); i >= 0 {
+		return id[i+len(uidTeamSep):]
+	}
+	return id
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+//Register adds t to the registry, or merges it into the canonical team it matches (by NBA id, ESPN id,
+//abbreviation, then full name) and returns the canonical team.  Fields already set on the canonical team win,
+//so the first registration (the snapshot, then NBA) decides the canonical abbreviation; other abbreviations
+//become aliases
+func (r *TeamRegistry) Register(t *Team, aliases ...TeamAlias) *Team {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	canon := r.match(t)
+	if canon == nil {
+		canon = t
+		if canon.TeamIDESPN != "" {
+			canon.TeamIDESPN = espnTeamID(canon.TeamIDESPN)
+		}
+		if canon.ID == "" {
+			canon.ID = canon.Abbreviation
+		}
+		r.teams = append(r.teams, canon)
+	} else {
+		mergeTeam(canon, t)
+		if t.Abbreviation != "" && !strings.EqualFold(t.Abbreviation, canon.Abbreviation) {
+			aliases = append(aliases, TeamAlias{Abbreviation: t.Abbreviation, Location: t.Location, Name: t.Name})
+		}
+	}
+	r.aliases[canon] = append(r.aliases[canon], aliases...)
+	r.indexTeam(canon, t)
+	for _, a := range aliases {
+		if a.Abbreviation != "" {
+			if _, taken := r.index[keyAbbr+normalize(a.Abbreviation)]; !taken {
+				r.index[keyAbbr+normalize(a.Abbreviation)] = canon
+			}
+		}
+		if name := a.FullName(); name != "" {
+			r.index[keyName+normalize(name)] = canon
+		}
+	}
+	return canon
+}
+
+func (r *TeamRegistry) match(t *Team) *Team {
+	if t.TeamIDNBA != "" {
+		if canon, ok := r.index[keyNBA+t.TeamIDNBA]; ok {
+			return canon
+		}
+	}
+	if t.TeamIDESPN != "" {
+		if canon, ok := r.index[keyESPN+espnTeamID(t.TeamIDESPN)]; ok {
+			return canon
+		}
+	}
+	if t.Abbreviation != "" {
+		if canon, ok := r.index[keyAbbr+normalize(t.Abbreviation)]; ok {
+			return canon
+		}
+	}
+	if name := strings.TrimSpace(t.Location + " " + t.Name); name != "" {
+		if canon, ok := r.index[keyName+normalize(name)]; ok {
+			return canon
+		}
+	}
+	return nil
+}
+
+func (r *TeamRegistry) indexTeam(canon *Team, t *Team) {
+	for _, team := range []*Team{canon, t} {
+		if team.TeamIDNBA != "" {
+			r.index[keyNBA+team.TeamIDNBA] = canon
+		}
+		if team.TeamIDESPN != "" {
+			r.index[keyESPN+espnTeamID(team.TeamIDESPN)] = canon
+		}
+		if team.Abbreviation != "" {
+			r.index[keyAbbr+normalize(team.Abbreviation)] = canon
+		}
+		if name := strings.TrimSpace(team.Location + " " + team.Name); name != "" {
+			r.index[keyName+normalize(name)] = canon
+		}
+	}
+}
+
+//mergeTeam fills the fields of canon that t knows and canon doesn't
+func mergeTeam(canon *Team, t *Team) {
+	if canon.TeamIDNBA == "" {
+		canon.TeamIDNBA = t.TeamIDNBA
+	}
+	if canon.TeamIDESPN == "" && t.TeamIDESPN != "" {
+		canon.TeamIDESPN = espnTeamID(t.TeamIDESPN)
+	}
+	if canon.Name == "" {
+		canon.Name = t.Name
+	}
+	if canon.Location == "" {
+		canon.Location = t.Location
+	}
+	if canon.Conference == "" {
+		canon.Conference = t.Conference
+	}
+	if canon.Division == "" {
+		canon.Division = t.Division
+	}
+	if len(canon.Logos) == 0 {
+		canon.Logos = t.Logos
+	}
+	if len(canon.Links) == 0 {
+		canon.Links = t.Links
+	}
+}
+
+func (r *TeamRegistry) lookup(key string) *Team {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.index[key]
+}
+
+//ByNBAID resolves a NBA teamId e.g. "1610612761"
+func (r *TeamRegistry) ByNBAID(id string) *Team {
+	return r.lookup(keyNBA + id)
+}
+
+//ByESPNID resolves an ESPN team id "28" or uid "s:40~l:46~t:28"
+func (r *TeamRegistry) ByESPNID(id string) *Team {
+	return r.lookup(keyESPN + espnTeamID(id))
+}
+
+//ByAbbreviation resolves a tricode or provider abbreviation, current or historical e.g. "GSW", "GS", "NJN"
+func (r *TeamRegistry) ByAbbreviation(abbreviation string) *Team {
+	return r.lookup(keyAbbr + normalize(abbreviation))
+}
+
+//ByName resolves a full name, current or historical e.g. "Seattle SuperSonics"
+func (r *TeamRegistry) ByName(name string) *Team {
+	return r.lookup(keyName + normalize(name))
+}
+
+//Resolve returns the canonical team of a provider's team by NBA id, ESPN id, abbreviation then full name, or nil
+func (r *TeamRegistry) Resolve(t *Team) *Team {
+	var canon *Team
+	if t.TeamIDNBA != "" {
+		canon = r.ByNBAID(t.TeamIDNBA)
+	}
+	if canon == nil && t.TeamIDESPN != "" {
+		canon = r.ByESPNID(t.TeamIDESPN)
+	}
+	if canon == nil && t.Abbreviation != "" {
+		canon = r.ByAbbreviation(t.Abbreviation)
+	}
+	if name := strings.TrimSpace(t.Location + " " + t.Name); canon == nil && name != "" {
+		canon = r.ByName(name)
+	}
+	return canon
+}
+
+//Teams returns the canonical teams in registration order
+func (r *TeamRegistry) Teams() []*Team {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*Team{}, r.teams...)
+}
+
+//Aliases returns the historical and provider identities of a canonical team
+func (r *TeamRegistry) Aliases(t *Team) []TeamAlias {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]TeamAlias{}, r.aliases[t]...)
+}
+
+//InSeason returns how a canonical team was known in a season, e.g. the Seattle SuperSonics for the Thunder
+//in 2005, or its current identity when no dated alias covers the season
+func (r *TeamRegistry) InSeason(t *Team, season int) TeamAlias {
+	for _, a := range r.Aliases(t) {
+		if a.FirstSeason != 0 && a.FirstSeason <= season && (a.LastSeason == 0 || season <= a.LastSeason) {
+			return a
+		}
+	}
+	return TeamAlias{Abbreviation: t.Abbreviation, Location: t.Location, Name: t.Name}
+}
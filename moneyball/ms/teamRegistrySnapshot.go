@@ -0,0 +1,103 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// offline snapshot of the NBA teams, used to seed the NBATeamRegistry when neither feed is reachable.
+// NBA ids/tricodes from http://data.nba.net/prod/v2/2019/teams.json, ESPN ids/abbreviations from
+// https://site.api.espn.com/apis/site/v2/sports/basketball/nba/teams, franchise history from nba.com
+
+type teamSnapshot struct {
+	team    Team
+	aliases []TeamAlias
+}
+
+var nbaTeamSnapshot = []teamSnapshot{
+	{team: Team{TeamIDNBA: "1610612737", TeamIDESPN: "1", Abbreviation: "ATL", Location: "Atlanta", Name: "Hawks", Conference: "East", Division: "Southeast"}},
+	{team: Team{TeamIDNBA: "1610612738", TeamIDESPN: "2", Abbreviation: "BOS", Location: "Boston", Name: "Celtics", Conference: "East", Division: "Atlantic"}},
+	{team: Team{TeamIDNBA: "1610612751", TeamIDESPN: "17", Abbreviation: "BKN", Location: "Brooklyn", Name: "Nets", Conference: "East", Division: "Atlantic"}, aliases: []TeamAlias{
+		{Abbreviation: "NJN", Location: "New Jersey", Name: "Nets", FirstSeason: 1977, LastSeason: 2011},
+	}},
+	{team: Team{TeamIDNBA: "1610612766", TeamIDESPN: "30", Abbreviation: "CHA", Location: "Charlotte", Name: "Hornets", Conference: "East", Division: "Southeast"}, aliases: []TeamAlias{
+		{Abbreviation: "CHA", Location: "Charlotte", Name: "Bobcats", FirstSeason: 2004, LastSeason: 2013},
+		{Abbreviation: "CHH", Location: "Charlotte", Name: "Hornets", FirstSeason: 1988, LastSeason: 2001},
+	}},
+	{team: Team{TeamIDNBA: "1610612741", TeamIDESPN: "4", Abbreviation: "CHI", Location: "Chicago", Name: "Bulls", Conference: "East", Division: "Central"}},
+	{team: Team{TeamIDNBA: "1610612739", TeamIDESPN: "5", Abbreviation: "CLE", Location: "Cleveland", Name: "Cavaliers", Conference: "East", Division: "Central"}},
+	{team: Team{TeamIDNBA: "1610612742", TeamIDESPN: "6", Abbreviation: "DAL", Location: "Dallas", Name: "Mavericks", Conference: "West", Division: "Southwest"}},
+	{team: Team{TeamIDNBA: "1610612743", TeamIDESPN: "7", Abbreviation: "DEN", Location: "Denver", Name: "Nuggets", Conference: "West", Division: "Northwest"}},
+	{team: Team{TeamIDNBA: "1610612765", TeamIDESPN: "8", Abbreviation: "DET", Location: "Detroit", Name: "Pistons", Conference: "East", Division: "Central"}},
+	{team: Team{TeamIDNBA: "1610612744", TeamIDESPN: "9", Abbreviation: "GSW", Location: "Golden State", Name: "Warriors", Conference: "West", Division: "Pacific"}, aliases: []TeamAlias{
+		{Abbreviation: "GS", Location: "Golden State", Name: "Warriors"},
+	}},
+	{team: Team{TeamIDNBA: "1610612745", TeamIDESPN: "10", Abbreviation: "HOU", Location: "Houston", Name: "Rockets", Conference: "West", Division: "Southwest"}},
+	{team: Team{TeamIDNBA: "1610612754", TeamIDESPN: "11", Abbreviation: "IND", Location: "Indiana", Name: "Pacers", Conference: "East", Division: "Central"}},
+	{team: Team{TeamIDNBA: "1610612746", TeamIDESPN: "12", Abbreviation: "LAC", Location: "LA", Name: "Clippers", Conference: "West", Division: "Pacific"}, aliases: []TeamAlias{
+		{Abbreviation: "SDC", Location: "San Diego", Name: "Clippers", FirstSeason: 1978, LastSeason: 1983},
+		{Abbreviation: "LAC", Location: "Los Angeles", Name: "Clippers", FirstSeason: 1984, LastSeason: 2014},
+	}},
+	{team: Team{TeamIDNBA: "1610612747", TeamIDESPN: "13", Abbreviation: "LAL", Location: "Los Angeles", Name: "Lakers", Conference: "West", Division: "Pacific"}},
+	{team: Team{TeamIDNBA: "1610612763", TeamIDESPN: "29", Abbreviation: "MEM", Location: "Memphis", Name: "Grizzlies", Conference: "West", Division: "Southwest"}, aliases: []TeamAlias{
+		{Abbreviation: "VAN", Location: "Vancouver", Name: "Grizzlies", FirstSeason: 1995, LastSeason: 2000},
+	}},
+	{team: Team{TeamIDNBA: "1610612748", TeamIDESPN: "14", Abbreviation: "MIA", Location: "Miami", Name: "Heat", Conference: "East", Division: "Southeast"}},
+	{team: Team{TeamIDNBA: "1610612749", TeamIDESPN: "15", Abbreviation: "MIL", Location: "Milwaukee", Name: "Bucks", Conference: "East", Division: "Central"}},
+	{team: Team{TeamIDNBA: "1610612750", TeamIDESPN: "16", Abbreviation: "MIN", Location: "Minnesota", Name: "Timberwolves", Conference: "West", Division: "Northwest"}},
+	{team: Team{TeamIDNBA: "1610612740", TeamIDESPN: "3", Abbreviation: "NOP", Location: "New Orleans", Name: "Pelicans", Conference: "West", Division: "Southwest"}, aliases: []TeamAlias{
+		{Abbreviation: "NO", Location: "New Orleans", Name: "Pelicans"},
+		{Abbreviation: "NOH", Location: "New Orleans", Name: "Hornets", FirstSeason: 2002, LastSeason: 2004},
+		{Abbreviation: "NOK", Location: "New Orleans/Oklahoma City", Name: "Hornets", FirstSeason: 2005, LastSeason: 2006},
+		{Abbreviation: "NOH", Location: "New Orleans", Name: "Hornets", FirstSeason: 2007, LastSeason: 2012},
+	}},
+	{team: Team{TeamIDNBA: "1610612752", TeamIDESPN: "18", Abbreviation: "NYK", Location: "New York", Name: "Knicks", Conference: "East", Division: "Atlantic"}, aliases: []TeamAlias{
+		{Abbreviation: "NY", Location: "New York", Name: "Knicks"},
+	}},
+	{team: Team{TeamIDNBA: "1610612760", TeamIDESPN: "25", Abbreviation: "OKC", Location: "Oklahoma City", Name: "Thunder", Conference: "West", Division: "Northwest"}, aliases: []TeamAlias{
+		{Abbreviation: "SEA", Location: "Seattle", Name: "SuperSonics", FirstSeason: 1967, LastSeason: 2007},
+	}},
+	{team: Team{TeamIDNBA: "1610612753", TeamIDESPN: "19", Abbreviation: "ORL", Location: "Orlando", Name: "Magic", Conference: "East", Division: "Southeast"}},
+	{team: Team{TeamIDNBA: "1610612755", TeamIDESPN: "20", Abbreviation: "PHI", Location: "Philadelphia", Name: "76ers", Conference: "East", Division: "Atlantic"}},
+	{team: Team{TeamIDNBA: "1610612756", TeamIDESPN: "21", Abbreviation: "PHX", Location: "Phoenix", Name: "Suns", Conference: "West", Division: "Pacific"}},
+	{team: Team{TeamIDNBA: "1610612757", TeamIDESPN: "22", Abbreviation: "POR", Location: "Portland", Name: "Trail Blazers", Conference: "West", Division: "Northwest"}},
+	{team: Team{TeamIDNBA: "1610612758", TeamIDESPN: "23", Abbreviation: "SAC", Location: "Sacramento", Name: "Kings", Conference: "West", Division: "Pacific"}, aliases: []TeamAlias{
+		{Abbreviation: "KCK", Location: "Kansas City", Name: "Kings", FirstSeason: 1975, LastSeason: 1984},
+	}},
+	{team: Team{TeamIDNBA: "1610612759", TeamIDESPN: "24", Abbreviation: "SAS", Location: "San Antonio", Name: "Spurs", Conference: "West", Division: "Southwest"}, aliases: []TeamAlias{
+		{Abbreviation: "SA", Location: "San Antonio", Name: "Spurs"},
+	}},
+	{team: Team{TeamIDNBA: "1610612761", TeamIDESPN: "28", Abbreviation: "TOR", Location: "Toronto", Name: "Raptors", Conference: "East", Division: "Atlantic"}},
+	{team: Team{TeamIDNBA: "1610612762", TeamIDESPN: "26", Abbreviation: "UTA", Location: "Utah", Name: "Jazz", Conference: "West", Division: "Northwest"}, aliases: []TeamAlias{
+		{Abbreviation: "UTAH", Location: "Utah", Name: "Jazz"},
+	}},
+	{team: Team{TeamIDNBA: "1610612764", TeamIDESPN: "27", Abbreviation: "WAS", Location: "Washington", Name: "Wizards", Conference: "East", Division: "Southeast"}, aliases: []TeamAlias{
+		{Abbreviation: "WSH", Location: "Washington", Name: "Wizards"},
+		{Abbreviation: "WSB", Location: "Washington", Name: "Bullets", FirstSeason: 1974, LastSeason: 1996},
+	}},
+}
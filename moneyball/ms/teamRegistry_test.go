@@ -0,0 +1,74 @@
+package ms
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNBATeamRegistry(t *testing.T) {
+	r := NBATeamRegistry()
+	assert.Equal(t, 30, len(r.Teams()))
+
+	gsw := r.ByNBAID("1610612744")
+	assert.NotNil(t, gsw)
+	assert.Equal(t, "GSW", gsw.Abbreviation)
+	assert.Equal(t, gsw, r.ByESPNID("9"))
+	assert.Equal(t, gsw, r.ByESPNID("s:40~l:46~t:9"))
+	assert.Equal(t, gsw, r.ByAbbreviation("GS"))
+	assert.Equal(t, gsw, r.ByName("Golden State Warriors"))
+
+	okc := r.ByAbbreviation("SEA")
+	assert.NotNil(t, okc)
+	assert.Equal(t, "OKC", okc.Abbreviation)
+	assert.Equal(t, okc, r.ByName("Seattle SuperSonics"))
+	assert.Equal(t, "SuperSonics", r.InSeason(okc, 2005).Name)
+	assert.Equal(t, "Thunder", r.InSeason(okc, 2019).Name)
+	assert.Equal(t, "Bobcats", r.InSeason(r.ByAbbreviation("CHA"), 2010).Name)
+	assert.Nil(t, r.ByAbbreviation("XYZ"))
+}
+
+func TestTeamRegistryRegister(t *testing.T) {
+	r := NewTeamRegistry(League("NBA"))
+	nba := r.Register(&Team{TeamIDNBA: "1610612744", Abbreviation: "GSW", Location: "Golden State", Name: "Warriors"})
+	espn := r.Register(&Team{TeamIDESPN: "s:40~l:46~t:9", Abbreviation: "GS", Location: "Golden State", Name: "Warriors",
+		Logos: []*Link{{HRef: "https://a.espncdn.com/i/teamlogos/nba/500/gs.png", IsLogo: true}}})
+
+	assert.Equal(t, nba, espn, "espn registration merges into the nba team")
+	assert.Equal(t, 1, len(r.Teams()))
+	assert.Equal(t, "GSW", espn.Abbreviation)
+	assert.Equal(t, "9", espn.TeamIDESPN)
+	assert.Equal(t, 1, len(espn.Logos))
+	assert.Equal(t, nba, r.ByAbbreviation("GS"))
+	assert.Equal(t, "GS", r.Aliases(nba)[0].Abbreviation)
+}
@@ -42,6 +42,7 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 
 import (
 	"encoding/json"
+	"go-moneyball/moneyball/ms"
 	"log"
 	"strconv"
 )
@@ -74,6 +75,18 @@ type SeasonMeta struct {
 	LeagueID            FlexInt `json:"league_id,omitempty"`   //"league_id":"00"
 }
 
+//MSSeason is the season of the meta, from season_year and season_stage or else stats_season_id
+func (m *SeasonMeta) MSSeason() ms.Season {
+	if m.SeasonYear != 0 && m.SeasonStage != 0 {
+		return ms.Season{SeasonYear: int(m.SeasonYear), SeasonStage: int(ms.StageFromNBA(int(m.SeasonStage)))}
+	}
+	season, err := ms.SeasonFromNBAStatsID(strconv.Itoa(int(m.StatsSeasonID)))
+	if err != nil {
+		return ms.Season{SeasonYear: int(m.SeasonYear)}
+	}
+	return season
+}
+
 //SportsSchedule ...
 type SportsSchedule struct {
 	Games []ScheduledGame `json:"game"`
@@ -150,6 +163,19 @@ type Bio struct {
 //OfficialPerson ... is a person that is a game official
 type OfficialPerson Person
 
+//MarshalMSOfficials returns the crew of the game in listed order, the first official is the crew chief
+func (g *SportsGame) MarshalMSOfficials() []*ms.GameOfficial {
+	officials := []*ms.GameOfficial{}
+	for i, o := range g.Officials {
+		official := ms.GameOfficial{Name: o.FirstName + " " + o.LastName, IDNBA: o.PersonID, Order: i + 1}
+		if o.Jersey != 0 {
+			official.Jersey = strconv.Itoa(int(o.Jersey))
+		}
+		officials = append(officials, &official)
+	}
+	return officials
+}
+
 //Player is some basic information on player
 type Player struct {
 	PlayerID    string `json:"id"`                    //"id":"201935",
@@ -238,16 +264,70 @@ type TeamStatistic struct {
 type TeamStats struct {
 	//			"vTeam":{"fastBreakPoints":"10","pointsInPaint":"40","biggestLead":"0",
 	// "secondChancePoints":"10","pointsOffTurnovers":"4","longestRun":"13","totals":{"points":"71","fgm":"27","fga":"81","fgp":"33.3","ftm":"11","fta":"19","ftp":"57.9","tpm":"6","tpa":"22","tpp":"27.3","offReb":"7","defReb":"27","totReb":"34","assists":"20","pFouls":"16","steals":"9","turnovers":"21","blocks":"2","plusMinus":"-69","min":"240:00","short_timeout_remaining":"0","full_timeout_remaining":"2","team_fouls":"14"},"leaders":{"points":{"value":"27","players":[{"personId":"202700","firstName":"Donatas","lastName":"Motiejunas"}]},"rebounds":{"value":"11","players":[{"personId":"202700","firstName":"Donatas","lastName":"Motiejunas"}]},"assists":{"value":"3","players":[{"personId":"27013","firstName":"Mingxin","lastName":"Ju"},{"personId":"202700","firstName":"Donatas","lastName":"Motiejunas"},{"personId":"203263","firstName":"James","lastName":"Nunnally"},{"personId":"64097","firstName":"Xudong","lastName":"Luo"},{"personId":"64091","firstName":"Liang","lastName":"Cai"}]}}},
-	FastBreakPoints    FlexInt `json:"fastBreakPoints"`
-	PointsInPaint      FlexInt `json:"pointsInPaint"`
-	BiggestLead        FlexInt `json:"biggestLead"`
-	SecondChancePoints FlexInt `json:"secondChancePoints"`
-	PointsOffTurnovers FlexInt `json:"pointsOffTurnovers"`
-	LongestRun         FlexInt `json:"longestRun"`
-	//TODO: points
+	FastBreakPoints    FlexInt     `json:"fastBreakPoints"`
+	PointsInPaint      FlexInt     `json:"pointsInPaint"`
+	BiggestLead        FlexInt     `json:"biggestLead"`
+	SecondChancePoints FlexInt     `json:"secondChancePoints"`
+	PointsOffTurnovers FlexInt     `json:"pointsOffTurnovers"`
+	LongestRun         FlexInt     `json:"longestRun"`
+	Totals             *TeamTotals `json:"totals,omitempty"`
 	//TODO: leaders
 }
 
+//TeamTotals ... box score totals of a team, "totals":{"points":"71","fgm":"27","fga":"81",...,"team_fouls":"14"}
+type TeamTotals struct {
+	Points               FlexInt `json:"points"`
+	FieldGoalsMade       FlexInt `json:"fgm"`
+	FieldGoalsAttempted  FlexInt `json:"fga"`
+	FreeThrowsMade       FlexInt `json:"ftm"`
+	FreeThrowsAttempted  FlexInt `json:"fta"`
+	ThreePointsMade      FlexInt `json:"tpm"`
+	ThreePointsAttempted FlexInt `json:"tpa"`
+	ReboundsOffensive    FlexInt `json:"offReb"`
+	ReboundsDefensive    FlexInt `json:"defReb"`
+	ReboundsTotal        FlexInt `json:"totReb"`
+	Assists              FlexInt `json:"assists"`
+	PersonalFouls        FlexInt `json:"pFouls"`
+	Steals               FlexInt `json:"steals"`
+	Turnovers            FlexInt `json:"turnovers"`
+	Blocks               FlexInt `json:"blocks"`
+	TeamFouls            FlexInt `json:"team_fouls"`
+}
+
+//marshalMSTeamGameStats normalizes the box score of a team through the stat vocabulary, totals of the game
+//(times tied, lead changes) are set by the caller.  The error is a *ms.StatValidationError for stats not set
+func (s *TeamStats) marshalMSTeamGameStats(gameID string, teamID string) (*ms.TeamGameStats, error) {
+	ts := ms.NewTeamGameStats(ms.LeagueNBA, ms.GameID(gameID), teamID, ms.ProviderNBA)
+	stats := []*ms.Stat{
+		{LongKey: "fastBreakPoints", Value: int(s.FastBreakPoints)},
+		{LongKey: "pointsInPaint", Value: int(s.PointsInPaint)},
+		{LongKey: "biggestLead", Value: int(s.BiggestLead)},
+		{LongKey: "secondChancePoints", Value: int(s.SecondChancePoints)},
+		{LongKey: "pointsOffTurnovers", Value: int(s.PointsOffTurnovers)},
+		{LongKey: "longestRun", Value: int(s.LongestRun)},
+	}
+	if t := s.Totals; t != nil {
+		stats = append(stats, []*ms.Stat{
+			{LongKey: "points", Value: int(t.Points)},
+			{LongKey: "fgm", Value: int(t.FieldGoalsMade)},
+			{LongKey: "fga", Value: int(t.FieldGoalsAttempted)},
+			{LongKey: "ftm", Value: int(t.FreeThrowsMade)},
+			{LongKey: "fta", Value: int(t.FreeThrowsAttempted)},
+			{LongKey: "tpm", Value: int(t.ThreePointsMade)},
+			{LongKey: "tpa", Value: int(t.ThreePointsAttempted)},
+			{LongKey: "offReb", Value: int(t.ReboundsOffensive)},
+			{LongKey: "defReb", Value: int(t.ReboundsDefensive)},
+			{LongKey: "totReb", Value: int(t.ReboundsTotal)},
+			{LongKey: "assists", Value: int(t.Assists)},
+			{LongKey: "pFouls", Value: int(t.PersonalFouls)},
+			{LongKey: "steals", Value: int(t.Steals)},
+			{LongKey: "turnovers", Value: int(t.Turnovers)},
+			{LongKey: "blocks", Value: int(t.Blocks)},
+		}...)
+	}
+	return ts, ts.SetAll("game "+gameID+" team "+teamID, stats)
+}
+
 //BoxStats ... grabbing the stats
 type BoxStats struct {
 	GameTimesTied      FlexInt        `json:"timesTied"`   //"timesTied":"0",
@@ -329,6 +409,39 @@ type TeamPointStats struct {
 	FullTimeoutRemaining    FlexInt     `json:"full_timeout_remaining"`              //"full_timeout_remaining":"0"}, //TODO unmarshal to int
 }
 
+//MarshalMSTeamGameStats normalizes the box score totals of the team through the stat vocabulary, the error is
+//a *ms.StatValidationError for stats not set
+func (t *WorkingTeam) MarshalMSTeamGameStats(gameID string) (*ms.TeamGameStats, error) {
+	s := t.TeamStats
+	ts := ms.NewTeamGameStats(ms.LeagueNBA, ms.GameID(gameID), t.TeamID, ms.ProviderNBA)
+	ts.Abbreviation = t.Abbreviation
+	if ts.Abbreviation == "" {
+		ts.Abbreviation = t.TeamKey
+	}
+	// the feed has no total rebounds, and misspells the rebound tags, see TeamPointStats
+	stats := []*ms.Stat{
+		{LongKey: "points", Value: int(s.Points)},
+		{LongKey: "field_goals_made", Value: int(s.FieldGoalsMade)},
+		{LongKey: "field_goals_attempted", Value: int(s.FieldGoalsAttempted)},
+		{LongKey: "free_throws_made", Value: int(s.FreeThrowsMade)},
+		{LongKey: "free_throws_attempted", Value: int(s.FreeThrowsAttempted)},
+		{LongKey: "three_pointers_made", Value: int(s.ThreePointersMade)},
+		{LongKey: "three_pointers_attempted", Value: int(s.ThreePointersAttempted)},
+		{LongKey: "rebounds_offensive", Value: int(s.ReboundsOffensive)},
+		{LongKey: "rebounds_defensive", Value: int(s.ReboundsDefensive)},
+		{LongKey: "rebounds", Value: int(s.ReboundsOffensive) + int(s.ReboundsDefensive)},
+		{LongKey: "team_rebounds", Value: int(s.TeamRebounds)},
+		{LongKey: "assists", Value: int(s.Assists)},
+		{LongKey: "fouls", Value: int(s.Fouls)},
+		{LongKey: "technical_fouls", Value: int(s.TechnicalFouls)},
+		{LongKey: "steals", Value: int(s.Steals)},
+		{LongKey: "turnovers", Value: int(s.Turnovers)},
+		{LongKey: "team_turnovers", Value: int(s.TeamTurnovers)},
+		{LongKey: "blocks", Value: int(s.Blocks)},
+	}
+	return ts, ts.SetAll("game "+gameID+" team "+t.TeamID, stats)
+}
+
 //WorkingTeam ... visitor/home team info with status...
 type WorkingTeam struct {
 	TeamID       string         `json:"id"`                     //"id":"1610612761", // convert string to int?
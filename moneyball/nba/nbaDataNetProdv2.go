@@ -39,6 +39,8 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 //REF: http://nbasense.com/nba-api/Data/Cms/Game/Boxscore
 
 import (
+	"errors"
+	"fmt"
 	"go-moneyball/moneyball/ms"
 	"log"
 	"strconv"
@@ -105,13 +107,25 @@ type ScheduledGamev2 struct {
 	//"hasGameBookPdf":true,
 	Period GamePeriodv2 `json:"period"` // "period": {}
 	//"nugget": {"text":""},
-	Attendance   string        `json:"attendance,omitempty"`   //"attendance":"18624",
-	GameDuration *GameDuration `json:"gameDuration,omitempty"` //"gameDuration":{"hours":"2","minutes":"33"},
-	HomeTeam     GameTeamv2    `json:"hTeam"`                  //"hTeam":{"teamId":"1610612745","score":"140","win":"1","loss":"0"},
-	VisitingTeam GameTeamv2    `json:"vTeam"`                  //"vTeam":{"teamId":"12329","score":"71","win":"0","loss":"1"},
+	Attendance   string                 `json:"attendance,omitempty"`   //"attendance":"18624",
+	GameDuration *GameDuration          `json:"gameDuration,omitempty"` //"gameDuration":{"hours":"2","minutes":"33"},
+	HomeTeam     GameTeamv2             `json:"hTeam"`                  //"hTeam":{"teamId":"1610612745","score":"140","win":"1","loss":"0"},
+	VisitingTeam GameTeamv2             `json:"vTeam"`                  //"vTeam":{"teamId":"12329","score":"71","win":"0","loss":"1"},
+	Officials    *Officialsv2           `json:"officials,omitempty"`    //"officials":{"formatted":[{"firstNameLastName":"Tony Brothers"},...]},
+	Rosters      []*ms.TeamSeasonRoster `json:"-"`                      // the teams' rosters, set from TeamRoster.MarshalMS
 	//Watch        json.RawMessage `json:"watch"` //"watch":{"broadcast":{"video":{"regionalBlackoutCodes":"","isLeaguePass":true,"isNationalBlackout":false,"isTNTOT":false,"canPurchase":false,"isVR":false,"isNextVR":false,"isNBAOnTNTVR":false,"isMagicLeap":false,"isOculusVenues":false,"national":{"broadcasters":[{"shortName":"NBA TV","longName":"NBA TV"}]},"canadian":[{"shortName":"NBAC","longName":"NBA TV Canada"}],"spanish_national":[]}}}},
 }
 
+//Officialsv2 ... the crew of a box score, schedules leave it out
+type Officialsv2 struct {
+	Formatted []OfficialNamev2 `json:"formatted"`
+}
+
+//OfficialNamev2 ...
+type OfficialNamev2 struct {
+	Name string `json:"firstNameLastName"` //"firstNameLastName":"Tony Brothers"
+}
+
 //GameDuration ...
 type GameDuration struct {
 	Hours   FlexInt `json:"hours"`
@@ -190,18 +204,48 @@ func (e *ScheduledGamev2) MarshalMSEvent() (*ms.Event, error) {
 	//eID.ExtractedSrc = e.ExtractedSrc
 	bs.EntityID = eID
 	bs.GameID = ms.GameID(e.GameID)
-	bs.League = ms.League("NBA")
-	bs.Season = ms.Season{SeasonYear: int(((*e).SeasonYear)), SeasonStage: (*e).SeasonStageID}
+	bs.Provider = ms.ProviderNBA
+	bs.League = ms.LeagueNBA
+	bs.Season = ms.Season{SeasonYear: int(((*e).SeasonYear)), SeasonStage: int(ms.StageFromNBA((*e).SeasonStageID))}
+	if season, err := ms.SeasonFromNBAGameID(e.GameID); err == nil && bs.Season.SeasonStage == int(ms.StageUnknown) {
+		bs.Season = season
+	}
 	bs.HomeTeam, _ = (*e).HomeTeam.marshalMSCompetitor()
 	bs.VisitTeam, _ = (*e).VisitingTeam.marshalMSCompetitor()
 	bs.Venue, _ = (*e).Arena.marshalMSVenue()
-	bs.GameDetail = e.marshalMSGameDetail()
+	bs.GameDetail = e.marshalMSGameDetail(ms.VenueTimeZone(bs.Venue))
+	bs.Status = e.marshalMSGameStatus()
+	bs.AttachRosters(e.Rosters...)
+	if err := bs.AttachOfficials(e.marshalMSOfficials()...); err != nil {
+		log.Printf("officials of game %s not registered: %s\n", e.GameID, err)
+	}
+	if _, err := ms.IdentityResolverFor(bs.League).ResolveGame(bs.Provider, &bs); err != nil {
+		log.Printf("game %s not in the crosswalk: %s\n", e.GameID, err)
+	}
 
 	ms.MasterIdentity(&bs)
 	return &bs, nil
 }
 
-func (e *ScheduledGamev2) marshalMSGameDetail() *ms.GameDetail {
+//marshalMSGameStatus maps statusNum 1 scheduled, 2 live and 3 final to the ESPN states "pre", "in" and "post"
+func (e *ScheduledGamev2) marshalMSGameStatus() *ms.GameStatus {
+	states := map[int]string{1: "pre", 2: "in", 3: "post"}
+	return &ms.GameStatus{Period: e.Period.Current, State: states[e.StatusNum]}
+}
+
+//marshalMSOfficials returns the crew in listed order, the first official is the crew chief
+func (e *ScheduledGamev2) marshalMSOfficials() []*ms.GameOfficial {
+	officials := []*ms.GameOfficial{}
+	if e.Officials == nil {
+		return officials
+	}
+	for i, o := range e.Officials.Formatted {
+		officials = append(officials, &ms.GameOfficial{Name: o.Name, Order: i + 1})
+	}
+	return officials
+}
+
+func (e *ScheduledGamev2) marshalMSGameDetail(timeZone string) *ms.GameDetail {
 	/*
 		type GameDetail struct {
 		StartTime           *time.Time  `json:"startTimeUTC,omitempty"`     //"startTimeUTC":"2019-10-01T00:00:00.000Z",
@@ -212,21 +256,11 @@ func (e *ScheduledGamev2) marshalMSGameDetail() *ms.GameDetail {
 		GameDurationMinutes int         `json:"gameDuration,omitempty"`
 		}*/
 	gd := ms.GameDetail{}
-	refTime := time.Time((*e).StartTime)
-	//fmt.Printf("timeRef %s\n", refTime)
-	gd.StartTime = &refTime
-	location, err := time.LoadLocation("America/New_York")
+	// the feed's startDateEastern is "20190930" and startTimeEastern "8:00 PM ET", both are taken from the UTC start
+	err := gd.SetStartTime(time.Time((*e).StartTime), timeZone)
 	if err != nil {
-		// set error
 		log.Printf("error: timezone conversion %#v\n", err)
 	}
-	est := refTime.In(location)
-	//fmt.Printf("time: UTC %s, EST %s\n", refTime, est)
-
-	gd.StartDateEastern = est.Format("2006-01-02")
-	gd.StartTimeEastern = est.Format("15:04:05")
-	//gd.StartDateEastern = (*e).StartDateEastern  // ignore initial - wrong formatting
-	//gd.StartTimeEastern = (*e).StartTimeEastern  // ignore initial - wrong formatting
 	//TODO: Period
 	if ((*e).Attendance == "") {
 		gd.Attendance = 0 
@@ -241,15 +275,54 @@ func (e *ScheduledGamev2) marshalMSGameDetail() *ms.GameDetail {
 	return &gd
 }
 
+//MarshalMSTeamGameStats normalizes the team totals of the box score, visiting team first.  Stats the vocabulary
+//rejects are returned as *ms.StatValidationError with the totals of both teams
+func (b *CMSProdv1BoxScore) MarshalMSTeamGameStats() ([]*ms.TeamGameStats, error) {
+	if b.Game == nil || b.BoxStats == nil {
+		return nil, fmt.Errorf("box score without game or stats")
+	}
+	stats := []*ms.TeamGameStats{}
+	invalid := []error{}
+	sides := []struct {
+		homeAway string
+		team     GameTeamv2
+		stats    *TeamStats
+	}{{"away", b.Game.VisitingTeam, b.BoxStats.GameTeamStatsVisit}, {"home", b.Game.HomeTeam, b.BoxStats.GameTeamStatsHome}}
+	for _, side := range sides {
+		if side.stats == nil {
+			return stats, fmt.Errorf("game %s: no %s team stats", b.Game.GameID, side.homeAway)
+		}
+		ts, err := side.stats.marshalMSTeamGameStats(b.Game.GameID, side.team.TeamID)
+		if err != nil {
+			invalid = append(invalid, err)
+		}
+		ts.Abbreviation = side.team.TriCode
+		ts.HomeAway = side.homeAway
+		ts.TimesTied = int(b.BoxStats.GameTimesTied)
+		ts.LeadChanges = int(b.BoxStats.GameLeadChanges)
+		stats = append(stats, ts)
+	}
+	return stats, errors.Join(invalid...)
+}
+
 func (t *GameTeamv2) marshalMSCompetitor() (*ms.Competitor, error) {
 	c := ms.Competitor{}
 	c.ID = t.TeamID
 	c.Abbreviation = t.TriCode
+	// v2 schedules carry the teamId with an empty triCode, the registry fills in the canonical team
+	if team := ms.NBATeamRegistry().ByNBAID(t.TeamID); team != nil {
+		tm := *team
+		c.Team = &tm
+		c.Abbreviation = team.Abbreviation
+		c.Name = team.Name
+		c.Location = team.Location
+	}
 	//c.Record = t.
-	linescores := []ms.Score{}
+	values := []float32{}
 	for _, lsc := range t.Linescore {
-		linescores = append(linescores, ms.Score{Score: float32(lsc.Score)})
+		values = append(values, float32(lsc.Score))
 	}
+	linescores := ms.LeagueNBA.PeriodFormat().LineScore(values)
 	c.LineScore = &linescores
 	c.Score = int(t.Score)
 	return &c, nil
@@ -259,6 +332,7 @@ func (a *Arena) marshalMSVenue() (*ms.Venue, error) {
 	v := ms.Venue{}
 	v.FullName = a.Name
 	v.Address = &ms.Address{Street: "", City: a.City, State: a.State, Country: a.Country}
+	v.TimeZone = ms.VenueTimeZone(&v)
 	_, err := ms.GetGeoCodeAddress(&v)
 	//TODO: Setup EntityID..
 	return &v, err
@@ -0,0 +1,117 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go-moneyball/moneyball/ms"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tGameOfficials = `{"id":"0021600732","officials":[
+{"person_id":"1146","first_name":"Tony","last_name":"Brothers","jersey_number":"25"},
+{"person_id":"203591","first_name":"Mitchell","last_name":"Ervin","jersey_number":"27"},
+{"person_id":"1363","first_name":"Bennie","last_name":"Adams","jersey_number":"47"}]}`
+
+func TestSportsGameMarshalMSOfficials(t *testing.T) {
+	g := SportsGame{}
+	assert.Nil(t, json.Unmarshal([]byte(tGameOfficials), &g))
+
+	officials := g.MarshalMSOfficials()
+	if assert.Len(t, officials, 3) {
+		assert.Equal(t, &ms.GameOfficial{Name: "Tony Brothers", Order: 1, Jersey: "25", IDNBA: "1146"}, officials[0])
+		assert.Equal(t, "203591", officials[1].IDNBA)
+		assert.Equal(t, 3, officials[2].Order)
+	}
+}
+
+const tBoxScoreOfficials = `{"basicGameData":{"gameId":"0011800001","seasonStageId":1,"statusNum":3,"seasonYear":"2018",
+"startTimeUTC":"2018-10-03T23:00:00.000Z","gameDuration":{"hours":"2","minutes":"14"},"hTeam":{"teamId":"1610612761","triCode":"TOR"},"vTeam":{"teamId":"1610612738","triCode":"BOS"},
+"officials":{"formatted":[{"firstNameLastName":"Tony Brothers"},{"firstNameLastName":"Mitchell Ervin"},{"firstNameLastName":"Bennie Adams"}]}}}`
+
+func TestBoxScoreMarshalMSEventOfficials(t *testing.T) {
+	b := CMSProdv1BoxScore{}
+	assert.Nil(t, json.Unmarshal([]byte(tBoxScoreOfficials), &b))
+	ev, err := b.Game.MarshalMSEvent()
+	assert.Nil(t, err)
+	if assert.Len(t, ev.Officials, 3) {
+		assert.Equal(t, "Tony Brothers", ev.Officials[0].Name)
+		assert.Equal(t, 1, ev.Officials[0].Order)
+		assert.Equal(t, "tony-brothers", ev.Officials[0].OfficialID)
+		assert.Equal(t, "bennie-adams", ev.Officials[2].OfficialID)
+	}
+	assert.Equal(t, ms.ProviderNBA, ev.Provider)
+	assert.Equal(t, "post", ev.Status.State, "statusNum 3 is final")
+	assert.Equal(t, "2018-10-03:BOS:TOR", ms.IdentityResolverFor(ms.LeagueNBA).Lookup(ms.KindGame, ms.ProviderNBA, "0011800001"),
+		"the game is in the crosswalk")
+}
+
+const tBoxStats = `{"basicGameData":{"gameId":"0011900001","hTeam":{"teamId":"1610612745","triCode":"HOU"},
+"vTeam":{"teamId":"12329","triCode":"SDS"}},"stats":{"timesTied":"2","leadChanges":"3",
+"vTeam":{"fastBreakPoints":"10","pointsInPaint":"40","biggestLead":"0","secondChancePoints":"10","pointsOffTurnovers":"4","longestRun":"13",
+ "totals":{"points":"71","fgm":"27","fga":"81","fgp":"33.3","ftm":"11","fta":"19","tpm":"6","tpa":"22","offReb":"7","defReb":"27","totReb":"34",
+ "assists":"20","pFouls":"16","steals":"9","turnovers":"21","blocks":"2","team_fouls":"14"}},
+"hTeam":{"fastBreakPoints":"31","pointsInPaint":"68","biggestLead":"71","longestRun":"15","totals":{"points":"140"}}}}`
+
+func TestBoxScoreMarshalMSTeamGameStats(t *testing.T) {
+	b := CMSProdv1BoxScore{}
+	assert.Nil(t, json.Unmarshal([]byte(tBoxStats), &b))
+	stats, err := b.MarshalMSTeamGameStats()
+	assert.Nil(t, err)
+	if assert.Len(t, stats, 2) {
+		v := stats[0]
+		assert.Equal(t, "away", v.HomeAway)
+		assert.Equal(t, "SDS", v.Abbreviation)
+		assert.Equal(t, ms.ProviderNBA, v.Source)
+		assert.Equal(t, 71, v.Points)
+		assert.Equal(t, 81, v.FieldGoalsAttempted)
+		assert.Equal(t, 34, v.Rebounds)
+		assert.Equal(t, 13, v.LongestRun)
+		assert.Equal(t, 3, v.LeadChanges)
+		assert.Equal(t, 140, stats[1].Points)
+		assert.Equal(t, 71, stats[1].BiggestLead)
+		assert.Equal(t, 2, stats[1].TimesTied)
+	}
+	_, err = (&CMSProdv1BoxScore{}).MarshalMSTeamGameStats()
+	assert.NotNil(t, err)
+}
+
+func TestSeasonMetaMSSeason(t *testing.T) {
+	meta := SeasonMeta{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"calendar_date":"20160908","season_year":"2016","stats_season_year":"2015",
+"stats_season_id":"42015","stats_season_stage":"4","season_id":"22016","display_year":"2016-17","season_stage":"2"}`), &meta))
+	assert.Equal(t, ms.Season{SeasonYear: 2016, SeasonStage: int(ms.StageRegular)}, meta.MSSeason())
+	meta.SeasonStage = 0
+	assert.Equal(t, ms.Season{SeasonYear: 2015, SeasonStage: int(ms.StagePlayoffs)}, meta.MSSeason())
+}
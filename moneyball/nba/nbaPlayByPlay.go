@@ -0,0 +1,275 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+//navigation link with php source: http://nbasense.com/nba-api/Stats/Stats/Game/PlayByPlay
+// https://stats.nba.com/stats/playbyplayv2?GameID=0021900001&StartPeriod=0&EndPeriod=10
+// EVENTMSGTYPE codes:
+//	1 made field goal, 2 missed field goal, 3 free throw, 4 rebound, 5 turnover, 6 foul, 7 violation,
+//	8 substitution, 9 timeout, 10 jump ball, 11 ejection, 12 start of period, 13 end of period, 18 instant replay
+
+import (
+	"fmt"
+	"go-moneyball/moneyball/ms"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	eventMadeShot     = 1
+	eventMissedShot   = 2
+	eventFreeThrow    = 3
+	eventRebound      = 4
+	eventTurnover     = 5
+	eventFoul         = 6
+	eventViolation    = 7
+	eventSubstitution = 8
+	eventTimeout      = 9
+	eventJumpBall     = 10
+	eventEjection     = 11
+	eventPeriodStart  = 12
+	eventPeriodEnd    = 13
+)
+
+var freeThrowOf = regexp.MustCompile(`(\d) of (\d)`)
+
+//PlayByPlay ... the playbyplayv2 rows for one game
+type PlayByPlay struct {
+	GameID   string          `json:"gameId"`   //"0021900001"
+	GameDate string          `json:"gameDate"` //"20191022" eastern date, used to anchor the wall clock
+	Plays    []PlayByPlayRow `json:"plays"`
+}
+
+//MarshalMS marshals the nba play-by-play rows to the canonical ms.PlayEvent stream
+func (pbp *PlayByPlay) MarshalMS() ([]*ms.PlayEvent, error) {
+	events := []*ms.PlayEvent{}
+	homeScore, visitScore := 0, 0
+	start := pbp.startClock()
+	for i := range pbp.Plays {
+		row := &pbp.Plays[i]
+		ev, err := row.marshalMSPlayEvent()
+		if err != nil {
+			return events, err
+		}
+		if row.Score != "" {
+			// SCORE is "VISITOR - HOME" and only set when the score changes
+			parts := strings.Split(row.Score, "-")
+			if len(parts) == 2 {
+				visitScore, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+				homeScore, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+			}
+		}
+		ev.HomeScore = homeScore
+		ev.VisitScore = visitScore
+		if pbp.GameID != "" {
+			ev.GameID = ms.GameID(pbp.GameID)
+		}
+		ev.WallClock = pbp.wallClock(row.WallClock, start)
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+//Teams returns the home and visit team ids as seen in the HOMEDESCRIPTION and VISITORDESCRIPTION columns
+func (pbp *PlayByPlay) Teams() (string, string) {
+	home, visit := "", ""
+	for _, row := range pbp.Plays {
+		if row.Player1TeamID == 0 {
+			continue
+		}
+		if home == "" && row.HomeDescription != "" && row.VisitDescription == "" {
+			home = strconv.Itoa(row.Player1TeamID)
+		}
+		if visit == "" && row.VisitDescription != "" && row.HomeDescription == "" {
+			visit = strconv.Itoa(row.Player1TeamID)
+		}
+		if home != "" && visit != "" {
+			break
+		}
+	}
+	return home, visit
+}
+
+//startClock is the wall clock of the first play, the game start the later plays are anchored to
+func (pbp *PlayByPlay) startClock() *time.Time {
+	for _, row := range pbp.Plays {
+		if t := pbp.wallClock(row.WallClock, nil); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+//wallClock anchors the "7:11 PM" wall clock (eastern) to the game date, nil if either is unknown.  A wall
+//clock before the game start is past midnight, on the day after the game date
+func (pbp *PlayByPlay) wallClock(wc string, start *time.Time) *time.Time {
+	if pbp.GameDate == "" || wc == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation("20060102 3:04 PM", pbp.GameDate+" "+wc, ms.Eastern())
+	if err != nil {
+		return nil
+	}
+	if start != nil && t.Before(*start) {
+		t = t.AddDate(0, 0, 1)
+	}
+	t = t.UTC()
+	return &t
+}
+
+func (row *PlayByPlayRow) marshalMSPlayEvent() (*ms.PlayEvent, error) {
+	ev := ms.PlayEvent{}
+	ev.GameID = ms.GameID(row.GameID)
+	ev.Sequence = row.EventNum
+	ev.Period = row.Period
+	ev.Clock = row.GameClock
+	seconds, err := ms.ParseGameClock(row.GameClock)
+	if err != nil {
+		return nil, fmt.Errorf("event %d: %s", row.EventNum, err)
+	}
+	ev.ClockSeconds = seconds
+	ev.SubType = strconv.Itoa(row.EventMsgActionType)
+	descriptions := []string{}
+	for _, d := range []string{row.HomeDescription, row.NeutralDescription, row.VisitDescription} {
+		if d != "" {
+			descriptions = append(descriptions, d)
+		}
+	}
+	ev.Description = strings.Join(descriptions, " ")
+	if row.Player1TeamID != 0 {
+		ev.TeamID = strconv.Itoa(row.Player1TeamID)
+	}
+
+	switch row.EventMsgType {
+	case eventMadeShot, eventMissedShot:
+		ev.Type = ms.PlayShot
+		ev.ShotValue = 2
+		if strings.Contains(ev.Description, "3PT") {
+			ev.ShotValue = 3
+		}
+		addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RoleShooter)
+		if row.EventMsgType == eventMadeShot {
+			ev.ShotResult = ms.ShotMade
+			addPlayEventPlayer(&ev, row.Player2ID, row.Player2Name, row.Player2TeamID, ms.RoleAssist)
+		} else {
+			ev.ShotResult = ms.ShotMissed
+			addPlayEventPlayer(&ev, row.Player3ID, row.Player3Name, row.Player3TeamID, ms.RoleBlock)
+		}
+	case eventFreeThrow:
+		ev.Type = ms.PlayFreeThrow
+		ev.ShotValue = 1
+		ev.ShotResult = ms.ShotMade
+		if strings.Contains(ev.Description, "MISS") {
+			ev.ShotResult = ms.ShotMissed
+		}
+		ev.FreeThrow = &ms.FreeThrowAttempt{Number: 1, Of: 1}
+		if m := freeThrowOf.FindStringSubmatch(ev.Description); m != nil {
+			ev.FreeThrow.Number, _ = strconv.Atoi(m[1])
+			ev.FreeThrow.Of, _ = strconv.Atoi(m[2])
+		}
+		addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RoleShooter)
+	case eventRebound:
+		ev.Type = ms.PlayRebound
+		if row.Player1TeamID == 0 && row.Player1ID != 0 {
+			// team rebound, PLAYER1_ID carries the team id
+			ev.TeamID = strconv.Itoa(row.Player1ID)
+		} else {
+			addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RoleRebounder)
+		}
+	case eventTurnover:
+		ev.Type = ms.PlayTurnover
+		if row.Player1TeamID == 0 && row.Player1ID != 0 {
+			ev.TeamID = strconv.Itoa(row.Player1ID)
+		} else {
+			addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RoleTurnover)
+		}
+		addPlayEventPlayer(&ev, row.Player2ID, row.Player2Name, row.Player2TeamID, ms.RoleSteal)
+	case eventFoul:
+		ev.Type = ms.PlayFoul
+		addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RoleFouler)
+		addPlayEventPlayer(&ev, row.Player2ID, row.Player2Name, row.Player2TeamID, ms.RoleFouled)
+	case eventViolation:
+		ev.Type = ms.PlayViolation
+		addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RolePrimary)
+	case eventSubstitution:
+		ev.Type = ms.PlaySubstitution
+		addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RoleSubOut)
+		addPlayEventPlayer(&ev, row.Player2ID, row.Player2Name, row.Player2TeamID, ms.RoleSubIn)
+	case eventTimeout:
+		ev.Type = ms.PlayTimeout
+		if row.Player1TeamID == 0 && row.Player1ID != 0 {
+			ev.TeamID = strconv.Itoa(row.Player1ID)
+		}
+	case eventJumpBall:
+		ev.Type = ms.PlayJumpBall
+		addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RoleJumper)
+		addPlayEventPlayer(&ev, row.Player2ID, row.Player2Name, row.Player2TeamID, ms.RoleJumper)
+		addPlayEventPlayer(&ev, row.Player3ID, row.Player3Name, row.Player3TeamID, ms.RolePossession)
+	case eventEjection:
+		ev.Type = ms.PlayEjection
+		addPlayEventPlayer(&ev, row.Player1ID, row.Player1Name, row.Player1TeamID, ms.RolePrimary)
+	case eventPeriodStart:
+		ev.Type = ms.PlayPeriodStart
+	case eventPeriodEnd:
+		ev.Type = ms.PlayPeriodEnd
+	default:
+		ev.Type = ms.PlayOther
+	}
+	return &ev, nil
+}
+
+//addPlayEventPlayer adds the player in role to the event, rows with no player (id 0) are skipped
+func addPlayEventPlayer(ev *ms.PlayEvent, playerID int, name string, teamID int, role string) {
+	if playerID == 0 {
+		return
+	}
+	p := &ms.PlayEventPlayer{PlayerID: strconv.Itoa(playerID), Name: name, Role: role}
+	if teamID != 0 {
+		p.TeamID = strconv.Itoa(teamID)
+	}
+	ev.Players = append(ev.Players, p)
+}
+
+//MarshalMSStints reconstructs the game's stints (see ms.BuildStints) from the play-by-play
+func (pbp *PlayByPlay) MarshalMSStints() (*ms.StintTable, error) {
+	events, err := pbp.MarshalMS()
+	if err != nil {
+		return nil, err
+	}
+	home, visit := pbp.Teams()
+	stints, err := ms.BuildStints(events, home, visit)
+	if err != nil {
+		return nil, err
+	}
+	return &ms.StintTable{GameID: ms.GameID(pbp.GameID), League: ms.LeagueNBA, Stints: stints}, nil
+}
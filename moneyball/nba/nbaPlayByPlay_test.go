@@ -0,0 +1,111 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-moneyball/moneyball/ms"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tPlayByPlay = `{"resource":"playbyplay","parameters":{"GameID":"0021900001"},"resultSets":[{"name":"PlayByPlay",
+"headers":["GAME_ID","EVENTNUM","EVENTMSGTYPE","EVENTMSGACTIONTYPE","PERIOD","WCTIMESTRING","PCTIMESTRING",
+"HOMEDESCRIPTION","NEUTRALDESCRIPTION","VISITORDESCRIPTION","SCORE","SCOREMARGIN",
+"PLAYER1_ID","PLAYER1_NAME","PLAYER1_TEAM_ID","PLAYER2_ID","PLAYER2_NAME","PLAYER2_TEAM_ID",
+"PLAYER3_ID","PLAYER3_NAME","PLAYER3_TEAM_ID"],
+"rowSet":[
+["0021900001",2,12,0,1,"8:11 PM","12:00",null,null,null,null,null,0,null,null,0,null,null,0,null,null],
+["0021900001",4,10,0,1,"8:11 PM","12:00","Jump Ball Gasol vs. Davis: Tip to Lowry",null,null,null,null,201188,"Marc Gasol",1610612761,203076,"Anthony Davis",1610612747,200768,"Kyle Lowry",1610612761],
+["0021900001",7,1,1,1,"8:12 PM","11:44","Siakam 3PT Jump Shot (3 PTS) (Lowry 1 AST)",null,null,"0 - 3","3",1627783,"Pascal Siakam",1610612761,200768,"Kyle Lowry",1610612761,0,null,null],
+["0021900001",9,3,11,1,"8:13 PM","11:20",null,null,"MISS James Free Throw 1 of 2",null,null,2544,"LeBron James",1610612747,0,null,null,0,null,null],
+["0021900001",10,4,0,1,"8:13 PM","11:20",null,null,null,null,null,1610612747,null,null,0,null,null,0,null,null],
+["0021900001",12,8,0,1,"8:14 PM","10:58",null,null,"SUB: Green FOR Bradley",null,null,1628396,"Tony Bradley",1610612747,201980,"Danny Green",1610612747,0,null,null]]}]}`
+
+func TestPlayByPlayMarshalMS(t *testing.T) {
+	rs := StatsResultSets{}
+	assert.Nil(t, json.Unmarshal([]byte(tPlayByPlay), &rs))
+	pbp := PlayByPlay{GameID: "0021900001", GameDate: "20191022"}
+	assert.Nil(t, rs.Decode("PlayByPlay", &pbp.Plays))
+
+	events, err := pbp.MarshalMS()
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(events))
+
+	assert.Equal(t, ms.PlayPeriodStart, events[0].Type)
+	assert.Equal(t, 720.0, events[0].ClockSeconds)
+	assert.Equal(t, "2019-10-23T00:11:00Z", events[0].WallClock.Format("2006-01-02T15:04:05Z07:00"))
+
+	jump := events[1]
+	assert.Equal(t, ms.PlayJumpBall, jump.Type)
+	assert.Equal(t, "200768", jump.Player(ms.RolePossession).PlayerID)
+
+	shot := events[2]
+	assert.Equal(t, ms.PlayShot, shot.Type)
+	assert.Equal(t, ms.ShotMade, shot.ShotResult)
+	assert.Equal(t, 3, shot.ShotValue)
+	assert.Equal(t, "1627783", shot.Player(ms.RoleShooter).PlayerID)
+	assert.Equal(t, "200768", shot.Player(ms.RoleAssist).PlayerID)
+	assert.Equal(t, 3, shot.HomeScore)
+	assert.Equal(t, 0, shot.VisitScore)
+
+	ft := events[3]
+	assert.Equal(t, ms.PlayFreeThrow, ft.Type)
+	assert.Equal(t, ms.ShotMissed, ft.ShotResult)
+	assert.Equal(t, &ms.FreeThrowAttempt{Number: 1, Of: 2}, ft.FreeThrow)
+	assert.Equal(t, 3, ft.HomeScore, "score is carried forward")
+
+	reb := events[4]
+	assert.Equal(t, ms.PlayRebound, reb.Type)
+	assert.Equal(t, "1610612747", reb.TeamID, "team rebound credited to the team")
+	assert.Equal(t, 0, len(reb.Players))
+
+	sub := events[5]
+	assert.Equal(t, ms.PlaySubstitution, sub.Type)
+	assert.Equal(t, "1628396", sub.Player(ms.RoleSubOut).PlayerID)
+	assert.Equal(t, "201980", sub.Player(ms.RoleSubIn).PlayerID)
+}
+
+func TestPlayByPlayWallClockMidnight(t *testing.T) {
+	pbp := PlayByPlay{GameID: "0021900001", GameDate: "20191022", Plays: []PlayByPlayRow{
+		{GameID: "0021900001", EventNum: 2, EventMsgType: 12, Period: 1, WallClock: "10:41 PM", GameClock: "12:00"},
+		{GameID: "0021900001", EventNum: 610, EventMsgType: 13, Period: 4, WallClock: "12:58 AM", GameClock: "0:00"},
+	}}
+	events, err := pbp.MarshalMS()
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "2019-10-23T02:41:00Z", events[0].WallClock.Format(time.RFC3339))
+		assert.Equal(t, "2019-10-23T04:58:00Z", events[1].WallClock.Format(time.RFC3339), "past midnight is the next day")
+	}
+}
@@ -0,0 +1,136 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+//navigation link with php source: http://nbasense.com/nba-api/Stats/Stats/Team/CommonTeamRoster
+// https://stats.nba.com/stats/commonteamroster?TeamID=1610612761&Season=2019-20&LeagueID=00
+
+import (
+	"fmt"
+	"go-moneyball/moneyball/ms"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//NBAHeadshotURL is the nba.com player headshot for a personId
+	NBAHeadshotURL = "https://ak-static.cms.nba.com/wp-content/uploads/headshots/nba/latest/260x190/%d.png"
+)
+
+//TeamRoster ... the CommonTeamRoster rows of a team for a season
+type TeamRoster struct {
+	TeamID  string                `json:"teamId"` //"1610612761"
+	Season  string                `json:"season"` //"2019-20"
+	Players []CommonTeamRosterRow `json:"players"`
+}
+
+//MarshalMS marshals the roster rows to a ms.TeamSeasonRoster of ms.Player, the regular season roster.  Rosters
+//are matched to competitors on the team abbreviation, so a team not in the registry is an error
+func (r *TeamRoster) MarshalMS() (*ms.TeamSeasonRoster, error) {
+	roster := ms.TeamSeasonRoster{Roster: []*ms.Player{}}
+	now := time.Now().UTC()
+	roster.AsOf = &now
+	year, _ := strconv.Atoi(strings.SplitN(r.Season, "-", 2)[0])
+	roster.Season = ms.Season{SeasonYear: year, SeasonStage: int(ms.StageRegular)}
+
+	canon := ms.NBATeamRegistry().ByNBAID(r.TeamID)
+	if canon == nil {
+		return &roster, fmt.Errorf("team %s of the roster is not in the NBA team registry", r.TeamID)
+	}
+	team := *canon
+	roster.TeamAbbreviation = canon.Abbreviation
+	for _, row := range r.Players {
+		p, err := row.marshalMSPlayer()
+		if err != nil {
+			return &roster, err
+		}
+		p.Team = &team
+		if roster.Season.SeasonYear == 0 {
+			roster.Season.SeasonYear, _ = strconv.Atoi(row.Season)
+		}
+		roster.Roster = append(roster.Roster, p)
+	}
+	return &roster, nil
+}
+
+func (row *CommonTeamRosterRow) marshalMSPlayer() (*ms.Player, error) {
+	p := ms.Player{
+		IDNBA:       strconv.Itoa(row.PlayerID),
+		League:      ms.LeagueNBA,
+		FullName:    row.PlayerName,
+		DisplayName: row.PlayerName,
+		Jersey:      row.Jersey,
+		Position:    &ms.Position{Name: row.Position, Abbreviation: row.Position},
+		Active:      true,
+		Headshot: &ms.Link{
+			HRef:   fmt.Sprintf(NBAHeadshotURL, row.PlayerID),
+			Rel:    []string{"headshot"},
+			IsLogo: false,
+		},
+	}
+	p.EntityID.ID = p.IDNBA
+	height, err := parseFeetInches(row.Height)
+	if err != nil {
+		return nil, fmt.Errorf("player %d: %s", row.PlayerID, err)
+	}
+	p.Height = height
+	if row.Weight != "" {
+		if p.Weight, err = strconv.Atoi(row.Weight); err != nil {
+			return nil, fmt.Errorf("player %d: invalid weight %s", row.PlayerID, row.Weight)
+		}
+	}
+	if row.BirthDate != "" {
+		bd, err := time.Parse("Jan 2, 2006", strings.Title(strings.ToLower(row.BirthDate)))
+		if err != nil {
+			return nil, fmt.Errorf("player %d: invalid birth date %s", row.PlayerID, row.BirthDate)
+		}
+		p.BirthDate = &bd
+	}
+	if row.Experience != "" && row.Experience != "R" {
+		if p.Experience, err = strconv.Atoi(row.Experience); err != nil {
+			return nil, fmt.Errorf("player %d: invalid experience %s", row.PlayerID, row.Experience)
+		}
+	}
+	return &p, nil
+}
+
+//parseFeetInches converts "6-1" to 73 inches, empty is 0
+func parseFeetInches(h string) (int, error) {
+	if h == "" {
+		return 0, nil
+	}
+	var feet, inches int
+	if _, err := fmt.Sscanf(h, "%d-%d", &feet, &inches); err != nil {
+		return 0, fmt.Errorf("invalid height %s", h)
+	}
+	return feet*12 + inches, nil
+}
@@ -0,0 +1,99 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go-moneyball/moneyball/ms"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tRoster = `{"resource":"commonteamroster","resultSets":[{"name":"CommonTeamRoster",
+"headers":["TeamID","SEASON","LeagueID","PLAYER","NUM","POSITION","HEIGHT","WEIGHT","BIRTH_DATE","AGE","EXP","SCHOOL","PLAYER_ID"],
+"rowSet":[[1610612761,"2019","00","Kyle Lowry","7","G","6-0","196","MAR 25, 1986",33.0,"13","Villanova",200768],
+["1610612761","2019","00","Terence Davis","0","G","6-4","201","MAY 16, 1997",22.0,"R","Mississippi",1629056]]}]}`
+
+func TestTeamRosterMarshalMS(t *testing.T) {
+	rs := StatsResultSets{}
+	assert.Nil(t, json.Unmarshal([]byte(tRoster), &rs))
+	r := TeamRoster{TeamID: "1610612761", Season: "2019-20"}
+	assert.Nil(t, rs.Decode("CommonTeamRoster", &r.Players))
+
+	roster, err := r.MarshalMS()
+	assert.Nil(t, err)
+	assert.Equal(t, "TOR", roster.TeamAbbreviation)
+	assert.Equal(t, 2019, roster.Season.SeasonYear)
+	assert.Equal(t, int(ms.StageRegular), roster.Season.SeasonStage)
+	assert.Equal(t, 2, len(roster.Roster))
+
+	lowry := roster.Roster[0]
+	assert.Equal(t, "200768", lowry.IDNBA)
+	assert.Equal(t, 72, lowry.Height)
+	assert.Equal(t, 196, lowry.Weight)
+	assert.Equal(t, 13, lowry.Experience)
+	assert.Equal(t, "1986-03-25", lowry.BirthDate.Format("2006-01-02"))
+	assert.Equal(t, "TOR", lowry.Team.Abbreviation)
+	assert.Equal(t, 0, roster.Roster[1].Experience, "rookies have no experience")
+
+	event := ms.Event{HomeTeam: &ms.Competitor{Abbreviation: "TOR"}, VisitTeam: &ms.Competitor{Abbreviation: "LAL"}}
+	assert.Equal(t, 1, event.AttachRosters(roster))
+	assert.Equal(t, 2, len(event.HomeTeam.Roster.Roster))
+	assert.Nil(t, event.VisitTeam.Roster)
+}
+
+func TestTeamRosterMarshalMSUnknownTeam(t *testing.T) {
+	r := TeamRoster{TeamID: "12329", Season: "2019-20"}
+	roster, err := r.MarshalMS()
+	assert.NotNil(t, err, "a roster without an abbreviation can't be matched")
+	assert.Equal(t, "", roster.TeamAbbreviation)
+}
+
+func TestBoxScoreMarshalMSEventRosters(t *testing.T) {
+	b := CMSProdv1BoxScore{}
+	assert.Nil(t, json.Unmarshal([]byte(tBoxScoreOfficials), &b))
+	rs := StatsResultSets{}
+	assert.Nil(t, json.Unmarshal([]byte(tRoster), &rs))
+	r := TeamRoster{TeamID: "1610612761", Season: "2019-20"}
+	assert.Nil(t, rs.Decode("CommonTeamRoster", &r.Players))
+	roster, err := r.MarshalMS()
+	assert.Nil(t, err)
+
+	b.Game.Rosters = append(b.Game.Rosters, roster)
+	ev, err := b.Game.MarshalMSEvent()
+	assert.Nil(t, err)
+	if assert.NotNil(t, ev.HomeTeam.Roster) {
+		assert.Equal(t, "Kyle Lowry", ev.HomeTeam.Roster.Roster[0].FullName)
+	}
+	assert.Nil(t, ev.VisitTeam.Roster)
+}
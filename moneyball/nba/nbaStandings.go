@@ -0,0 +1,185 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+//navigation link with php source: http://nbasense.com/nba-api/Data/Prod/Current/StandingsConference
+//http://data.nba.net/prod/v1/current/standings_conference.json
+// "league":{"standard":{"seasonYear":2019,"seasonStageId":2,"conference":{"east":[{"teamId":"1610612749","win":"28",
+//	"loss":"5","winPct":".848","gamesBehind":"0","confRank":"1","confWin":"17","confLoss":"3","divWin":"5","divLoss":"1",
+//	"homeWin":"15","homeLoss":"1","awayWin":"13","awayLoss":"4","lastTenWin":"8","lastTenLoss":"2","streak":"2",
+//	"divRank":"1","isWinStreak":true,"clinchedPlayoffsCode":"","teamSitesOnly":{"teamTricode":"MIL",...}},...],"west":[...]}}}
+
+import (
+	"go-moneyball/moneyball/ms"
+	"sort"
+	"strings"
+	"time"
+)
+
+//CMSProdv1Standings ... based upon this structure http://data.nba.net/prod/v1/current/standings_conference.json
+type CMSProdv1Standings struct {
+	InternalStuff InternalProdv2   `json:"_internal"` //"_internal":{}
+	League        StandingsLeagues `json:"league"`    //"league":{}
+}
+
+//StandingsLeagues ...
+type StandingsLeagues struct {
+	Standard StandingsConferences `json:"standard"` //"standard":{}
+}
+
+//StandingsConferences ...
+type StandingsConferences struct {
+	SeasonYear    FlexInt             `json:"seasonYear"`    //"seasonYear":2019,
+	SeasonStageID FlexInt             `json:"seasonStageId"` //"seasonStageId":2,
+	Conference    ConferenceStandings `json:"conference"`
+}
+
+//ConferenceStandings ...
+type ConferenceStandings struct {
+	East []TeamStanding `json:"east"`
+	West []TeamStanding `json:"west"`
+}
+
+//TeamStanding ... one row of the conference table
+type TeamStanding struct {
+	TeamID                 string           `json:"teamId"`                 //"teamId":"1610612749",
+	Win                    FlexInt          `json:"win"`                    //"win":"28",
+	Loss                   FlexInt          `json:"loss"`                   //"loss":"5",
+	WinPct                 FlexFloat64      `json:"winPct"`                 //"winPct":".848",
+	GamesBehind            FlexFloat64      `json:"gamesBehind"`            //"gamesBehind":"0",
+	ConfRank               FlexInt          `json:"confRank"`               //"confRank":"1",
+	ConfWin                FlexInt          `json:"confWin"`                //"confWin":"17",
+	ConfLoss               FlexInt          `json:"confLoss"`               //"confLoss":"3",
+	DivRank                FlexInt          `json:"divRank"`                //"divRank":"1",
+	DivWin                 FlexInt          `json:"divWin"`                 //"divWin":"5",
+	DivLoss                FlexInt          `json:"divLoss"`                //"divLoss":"1",
+	HomeWin                FlexInt          `json:"homeWin"`                //"homeWin":"15",
+	HomeLoss               FlexInt          `json:"homeLoss"`               //"homeLoss":"1",
+	AwayWin                FlexInt          `json:"awayWin"`                //"awayWin":"13",
+	AwayLoss               FlexInt          `json:"awayLoss"`               //"awayLoss":"4",
+	LastTenWin             FlexInt          `json:"lastTenWin"`             //"lastTenWin":"8",
+	LastTenLoss            FlexInt          `json:"lastTenLoss"`            //"lastTenLoss":"2",
+	Streak                 FlexInt          `json:"streak"`                 //"streak":"2",
+	IsWinStreak            bool             `json:"isWinStreak"`            //"isWinStreak":true,
+	ClinchedPlayoffsCode   string           `json:"clinchedPlayoffsCode"`   //"clinchedPlayoffsCode":"" or "x", "o" when eliminated
+	ClinchedPlayoffsCodeV2 string           `json:"clinchedPlayoffsCodeV2"` //"clinchedPlayoffsCodeV2":"",
+	TeamSitesOnly          StandingSiteTeam `json:"teamSitesOnly"`
+}
+
+//StandingSiteTeam ... team naming and clinch flags carried in "teamSitesOnly"
+type StandingSiteTeam struct {
+	TeamKey            string  `json:"teamKey"`            //"teamKey":"Milwaukee",
+	TeamName           string  `json:"teamName"`           //"teamName":"Milwaukee",
+	TeamNickname       string  `json:"teamNickname"`       //"teamNickname":"Bucks",
+	TeamTricode        string  `json:"teamTricode"`        //"teamTricode":"MIL",
+	ClinchedConference FlexInt `json:"clinchedConference"` //"clinchedConference":"0",
+	ClinchedDivision   FlexInt `json:"clinchedDivision"`   //"clinchedDivision":"0",
+	ClinchedPlayoffs   FlexInt `json:"clinchedPlayoffs"`   //"clinchedPlayoffs":"0",
+	StreakText         string  `json:"streakText"`         //"streakText":"W 2"
+}
+
+//MarshalMS marshals the conference standings to a ms.StandingsSnapshot, asOf dates the snapshot and when
+//zero the date the feed was published is used
+func (s *CMSProdv1Standings) MarshalMS(asOf time.Time) (*ms.StandingsSnapshot, error) {
+	if asOf.IsZero() {
+		asOf = publishedDate(s.InternalStuff.PubDateTime)
+	}
+	asOf = ms.StandingDate(asOf)
+	ss := ms.StandingsSnapshot{League: ms.LeagueNBA, AsOf: asOf}
+	season := ms.Season{SeasonYear: int(s.League.Standard.SeasonYear), SeasonStage: int(s.League.Standard.SeasonStageID)}
+	for conference, teams := range map[string][]TeamStanding{
+		"East": s.League.Standard.Conference.East,
+		"West": s.League.Standard.Conference.West} {
+		for i := range teams {
+			st := teams[i].marshalMSStanding(asOf, season, conference)
+			ss.Standings = append(ss.Standings, st)
+		}
+	}
+	sortStandings(ss.Standings)
+	return &ss, nil
+}
+
+func (t *TeamStanding) marshalMSStanding(asOf time.Time, season ms.Season, conference string) *ms.Standing {
+	st := ms.Standing{
+		TeamIDNBA:          t.TeamID,
+		Abbreviation:       t.TeamSitesOnly.TeamTricode,
+		League:             ms.LeagueNBA,
+		Season:             season,
+		AsOf:               asOf,
+		Conference:         conference,
+		Wins:               int(t.Win),
+		Losses:             int(t.Loss),
+		WinPct:             float64(t.WinPct),
+		GamesBack:          float64(t.GamesBehind),
+		ConferenceRank:     int(t.ConfRank),
+		DivisionRank:       int(t.DivRank),
+		Streak:             int(t.Streak),
+		Home:               ms.StandingSplit{Wins: int(t.HomeWin), Losses: int(t.HomeLoss)},
+		Away:               ms.StandingSplit{Wins: int(t.AwayWin), Losses: int(t.AwayLoss)},
+		ConferenceRecord:   ms.StandingSplit{Wins: int(t.ConfWin), Losses: int(t.ConfLoss)},
+		DivisionRecord:     ms.StandingSplit{Wins: int(t.DivWin), Losses: int(t.DivLoss)},
+		LastTen:            ms.StandingSplit{Wins: int(t.LastTenWin), Losses: int(t.LastTenLoss)},
+		ClinchCode:         t.ClinchedPlayoffsCode,
+		ClinchedPlayoffs:   t.TeamSitesOnly.ClinchedPlayoffs == 1,
+		ClinchedConference: t.TeamSitesOnly.ClinchedConference == 1,
+		ClinchedDivision:   t.TeamSitesOnly.ClinchedDivision == 1,
+		Eliminated:         strings.EqualFold(t.ClinchedPlayoffsCode, "o"),
+	}
+	if !t.IsWinStreak {
+		st.Streak = -st.Streak
+	}
+	// the conference feed has no division, the registry knows the team's
+	if team := ms.NBATeamRegistry().ByNBAID(t.TeamID); team != nil {
+		st.Division = team.Division
+	}
+	st.ID = ms.StandingID(asOf, st.Abbreviation)
+	return &st
+}
+
+//publishedDate reads the date from "2020-01-06 10:20:39.221 EST", now when it can't be read
+func publishedDate(pub string) time.Time {
+	if len(pub) >= 10 {
+		if t, err := time.Parse("2006-01-02", pub[:10]); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+//sortStandings orders by conference then rank so snapshots are stable
+func sortStandings(standings []*ms.Standing) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Conference != standings[j].Conference {
+			return standings[i].Conference < standings[j].Conference
+		}
+		return standings[i].ConferenceRank < standings[j].ConferenceRank
+	})
+}
@@ -0,0 +1,77 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tStandings = `{"_internal":{"pubDateTime":"2020-01-06 10:20:39.221 EST"},"league":{"standard":{"seasonYear":2019,"seasonStageId":2,
+"conference":{"east":[{"teamId":"1610612749","win":"28","loss":"5","winPct":".848","gamesBehind":"0","confRank":"1","confWin":"17",
+"confLoss":"3","divRank":"1","divWin":"5","divLoss":"1","homeWin":"15","homeLoss":"1","awayWin":"13","awayLoss":"4","lastTenWin":"8",
+"lastTenLoss":"2","streak":"2","isWinStreak":true,"clinchedPlayoffsCode":"","teamSitesOnly":{"teamTricode":"MIL","clinchedConference":"0",
+"clinchedDivision":"0","clinchedPlayoffs":"0","streakText":"W 2"}}],
+"west":[{"teamId":"1610612744","win":"9","loss":"29","winPct":".237","gamesBehind":"21.5","confRank":"15","confWin":"4","confLoss":"18",
+"divRank":"5","divWin":"1","divLoss":"6","homeWin":"5","homeLoss":"14","awayWin":"4","awayLoss":"15","lastTenWin":"3","lastTenLoss":"7",
+"streak":"3","isWinStreak":false,"clinchedPlayoffsCode":"o","teamSitesOnly":{"teamTricode":"GSW","clinchedConference":"0",
+"clinchedDivision":"0","clinchedPlayoffs":"0","streakText":"L 3"}}]}}}}`
+
+func TestStandingsMarshalMS(t *testing.T) {
+	s := CMSProdv1Standings{}
+	assert.Nil(t, json.Unmarshal([]byte(tStandings), &s))
+	ss, err := s.MarshalMS(time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, "2020-01-06", ss.AsOf.Format("2006-01-02"))
+	assert.Equal(t, 2, len(ss.Standings))
+
+	mil := ss.Team("MIL")
+	assert.NotNil(t, mil)
+	assert.Equal(t, "2020-01-06.MIL", mil.ID)
+	assert.Equal(t, "East", mil.Conference)
+	assert.Equal(t, "Central", mil.Division, "from the team registry")
+	assert.Equal(t, 28, mil.Wins)
+	assert.Equal(t, 0.848, mil.WinPct)
+	assert.Equal(t, 2, mil.Streak)
+	assert.Equal(t, "15-1", mil.Home.Summary())
+	assert.Equal(t, "17-3", mil.ConferenceRecord.Summary())
+	assert.Equal(t, 2019, mil.Season.SeasonYear)
+
+	gsw := ss.Team("GSW")
+	assert.Equal(t, -3, gsw.Streak)
+	assert.Equal(t, 21.5, gsw.GamesBack)
+	assert.True(t, gsw.Eliminated)
+	assert.Equal(t, "L3", gsw.StreakText())
+	assert.Equal(t, "Pacific", gsw.Division)
+}
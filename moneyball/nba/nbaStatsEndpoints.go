@@ -0,0 +1,320 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+//stats.nba.com rejects requests that omit parameters, even when they are blank, so each endpoint
+//carries the full parameter list with defaults.  Parameter names are case sensitive.
+// e.g. https://stats.nba.com/stats/playbyplayv2?GameID=0021900001&StartPeriod=0&EndPeriod=10
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+const (
+	//NBAStatsAPIPrefix is the URL filepath prefix for the stats.nba.com resultSets endpoints
+	NBAStatsAPIPrefix = "stats/"
+
+	//LeagueDashPlayerStatsEndpoint ...
+	LeagueDashPlayerStatsEndpoint = "leaguedashplayerstats"
+	//BoxScoreTraditionalEndpoint ...
+	BoxScoreTraditionalEndpoint = "boxscoretraditionalv2"
+	//PlayByPlayEndpoint ...
+	PlayByPlayEndpoint = "playbyplayv2"
+	//CommonTeamRosterEndpoint ...
+	CommonTeamRosterEndpoint = "commonteamroster"
+	//LeagueGameLogEndpoint ...
+	LeagueGameLogEndpoint = "leaguegamelog"
+)
+
+//StatsEndpoint describes a stats.nba.com endpoint, the result sets it returns and the parameters it requires
+type StatsEndpoint struct {
+	Name       string            // path element e.g. "playbyplayv2"
+	ResultSets []string          // result set names in the response e.g. ["PlayByPlay","AvailableVideo"]
+	Required   []string          // parameters the caller must supply
+	Defaults   map[string]string // parameters sent with a default (possibly blank) value when not supplied
+}
+
+//StatsEndpoints is the catalog of supported stats.nba.com endpoints keyed by name
+var StatsEndpoints = map[string]*StatsEndpoint{
+	LeagueDashPlayerStatsEndpoint: &StatsEndpoint{
+		Name:       LeagueDashPlayerStatsEndpoint,
+		ResultSets: []string{"LeagueDashPlayerStats"},
+		Required:   []string{"Season"},
+		Defaults: map[string]string{
+			"SeasonType": "Regular Season", "MeasureType": "Base", "PerMode": "Totals", "PlusMinus": "N",
+			"PaceAdjust": "N", "Rank": "N", "LeagueID": "00", "LastNGames": "0", "Month": "0",
+			"OpponentTeamID": "0", "Period": "0", "DateFrom": "", "DateTo": "", "GameSegment": "",
+			"Location": "", "Outcome": "", "SeasonSegment": "", "VsConference": "", "VsDivision": "",
+		},
+	},
+	BoxScoreTraditionalEndpoint: &StatsEndpoint{
+		Name:       BoxScoreTraditionalEndpoint,
+		ResultSets: []string{"PlayerStats", "TeamStats", "TeamStarterBenchStats"},
+		Required:   []string{"GameID"},
+		Defaults: map[string]string{
+			"StartPeriod": "0", "EndPeriod": "10", "StartRange": "0", "EndRange": "28800", "RangeType": "0",
+		},
+	},
+	PlayByPlayEndpoint: &StatsEndpoint{
+		Name:       PlayByPlayEndpoint,
+		ResultSets: []string{"PlayByPlay", "AvailableVideo"},
+		Required:   []string{"GameID"},
+		Defaults:   map[string]string{"StartPeriod": "0", "EndPeriod": "10"},
+	},
+	CommonTeamRosterEndpoint: &StatsEndpoint{
+		Name:       CommonTeamRosterEndpoint,
+		ResultSets: []string{"CommonTeamRoster", "Coaches"},
+		Required:   []string{"TeamID", "Season"},
+		Defaults:   map[string]string{"LeagueID": "00"},
+	},
+	LeagueGameLogEndpoint: &StatsEndpoint{
+		Name:       LeagueGameLogEndpoint,
+		ResultSets: []string{"LeagueGameLog"},
+		Required:   []string{"Season"},
+		Defaults: map[string]string{
+			"SeasonType": "Regular Season", "PlayerOrTeam": "T", "Direction": "ASC", "Sorter": "DATE",
+			"Counter": "0", "LeagueID": "00", "DateFrom": "", "DateTo": "",
+		},
+	},
+}
+
+//LookupStatsEndpoint returns the catalog entry for the named endpoint
+func LookupStatsEndpoint(name string) (*StatsEndpoint, error) {
+	ep, ok := StatsEndpoints[name]
+	if !ok {
+		return nil, fmt.Errorf("stats endpoint %s is not in the catalog", name)
+	}
+	return ep, nil
+}
+
+//Path returns the relative URL path of the endpoint e.g. "stats/playbyplayv2"
+func (ep *StatsEndpoint) Path() string {
+	return NBAStatsAPIPrefix + ep.Name
+}
+
+//Query merges the supplied parameters over the endpoint defaults and verifies that every
+//required parameter is present
+func (ep *StatsEndpoint) Query(params map[string]string) (url.Values, error) {
+	q := url.Values{}
+	for k, v := range ep.Defaults {
+		q.Set(k, v)
+	}
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	missing := []string{}
+	for _, r := range ep.Required {
+		if q.Get(r) == "" {
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return q, fmt.Errorf("stats endpoint %s missing required parameters %v", ep.Name, missing)
+	}
+	return q, nil
+}
+
+//LeagueDashPlayerStatsRow ... row of the leaguedashplayerstats LeagueDashPlayerStats result set
+type LeagueDashPlayerStatsRow struct {
+	PlayerID         int     `stats:"PLAYER_ID"`         // 201935
+	PlayerName       string  `stats:"PLAYER_NAME"`       // "James Harden"
+	TeamID           int     `stats:"TEAM_ID"`           // 1610612745
+	TeamAbbreviation string  `stats:"TEAM_ABBREVIATION"` // "HOU"
+	Age              float64 `stats:"AGE"`
+	GamesPlayed      int     `stats:"GP"`
+	Wins             int     `stats:"W"`
+	Losses           int     `stats:"L"`
+	Minutes          float64 `stats:"MIN"`
+	FieldGoalsMade   int     `stats:"FGM"`
+	FieldGoalsAtt    int     `stats:"FGA"`
+	FieldGoalsPct    float64 `stats:"FG_PCT"`
+	ThreePointsMade  int     `stats:"FG3M"`
+	ThreePointsAtt   int     `stats:"FG3A"`
+	ThreePointsPct   float64 `stats:"FG3_PCT"`
+	FreeThrowsMade   int     `stats:"FTM"`
+	FreeThrowsAtt    int     `stats:"FTA"`
+	FreeThrowsPct    float64 `stats:"FT_PCT"`
+	ReboundsOff      int     `stats:"OREB"`
+	ReboundsDef      int     `stats:"DREB"`
+	Rebounds         int     `stats:"REB"`
+	Assists          int     `stats:"AST"`
+	Turnovers        int     `stats:"TOV"`
+	Steals           int     `stats:"STL"`
+	Blocks           int     `stats:"BLK"`
+	PersonalFouls    int     `stats:"PF"`
+	Points           int     `stats:"PTS"`
+	PlusMinus        float64 `stats:"PLUS_MINUS"`
+}
+
+//BoxScorePlayerRow ... row of the boxscoretraditionalv2 PlayerStats result set
+type BoxScorePlayerRow struct {
+	GameID           string  `stats:"GAME_ID"`
+	TeamID           int     `stats:"TEAM_ID"`
+	TeamAbbreviation string  `stats:"TEAM_ABBREVIATION"`
+	TeamCity         string  `stats:"TEAM_CITY"`
+	PlayerID         int     `stats:"PLAYER_ID"`
+	PlayerName       string  `stats:"PLAYER_NAME"`
+	StartPosition    string  `stats:"START_POSITION"` // "F","C","G" or "" for bench
+	Comment          string  `stats:"COMMENT"`        // e.g. "DNP - Coach's Decision"
+	Minutes          string  `stats:"MIN"`            // "34:12"
+	FieldGoalsMade   int     `stats:"FGM"`
+	FieldGoalsAtt    int     `stats:"FGA"`
+	FieldGoalsPct    float64 `stats:"FG_PCT"`
+	ThreePointsMade  int     `stats:"FG3M"`
+	ThreePointsAtt   int     `stats:"FG3A"`
+	ThreePointsPct   float64 `stats:"FG3_PCT"`
+	FreeThrowsMade   int     `stats:"FTM"`
+	FreeThrowsAtt    int     `stats:"FTA"`
+	FreeThrowsPct    float64 `stats:"FT_PCT"`
+	ReboundsOff      int     `stats:"OREB"`
+	ReboundsDef      int     `stats:"DREB"`
+	Rebounds         int     `stats:"REB"`
+	Assists          int     `stats:"AST"`
+	Steals           int     `stats:"STL"`
+	Blocks           int     `stats:"BLK"`
+	Turnovers        int     `stats:"TO"`
+	PersonalFouls    int     `stats:"PF"`
+	Points           int     `stats:"PTS"`
+	PlusMinus        float64 `stats:"PLUS_MINUS"`
+}
+
+//BoxScoreTeamRow ... row of the boxscoretraditionalv2 TeamStats result set
+type BoxScoreTeamRow struct {
+	GameID           string  `stats:"GAME_ID"`
+	TeamID           int     `stats:"TEAM_ID"`
+	TeamName         string  `stats:"TEAM_NAME"`
+	TeamAbbreviation string  `stats:"TEAM_ABBREVIATION"`
+	TeamCity         string  `stats:"TEAM_CITY"`
+	Minutes          string  `stats:"MIN"` // "240:00"
+	FieldGoalsMade   int     `stats:"FGM"`
+	FieldGoalsAtt    int     `stats:"FGA"`
+	FieldGoalsPct    float64 `stats:"FG_PCT"`
+	ThreePointsMade  int     `stats:"FG3M"`
+	ThreePointsAtt   int     `stats:"FG3A"`
+	ThreePointsPct   float64 `stats:"FG3_PCT"`
+	FreeThrowsMade   int     `stats:"FTM"`
+	FreeThrowsAtt    int     `stats:"FTA"`
+	FreeThrowsPct    float64 `stats:"FT_PCT"`
+	ReboundsOff      int     `stats:"OREB"`
+	ReboundsDef      int     `stats:"DREB"`
+	Rebounds         int     `stats:"REB"`
+	Assists          int     `stats:"AST"`
+	Steals           int     `stats:"STL"`
+	Blocks           int     `stats:"BLK"`
+	Turnovers        int     `stats:"TO"`
+	PersonalFouls    int     `stats:"PF"`
+	Points           int     `stats:"PTS"`
+	PlusMinus        float64 `stats:"PLUS_MINUS"`
+}
+
+//PlayByPlayRow ... row of the playbyplayv2 PlayByPlay result set
+// the SCORE column is "VISITOR - HOME" and is only populated on scoring events, SCOREMARGIN is home relative
+type PlayByPlayRow struct {
+	GameID             string `stats:"GAME_ID"`
+	EventNum           int    `stats:"EVENTNUM"`
+	EventMsgType       int    `stats:"EVENTMSGTYPE"`       // 1 made shot, 2 miss, 3 free throw, 4 rebound, 5 turnover...
+	EventMsgActionType int    `stats:"EVENTMSGACTIONTYPE"` // sub type e.g. 1 jump shot
+	Period             int    `stats:"PERIOD"`
+	WallClock          string `stats:"WCTIMESTRING"` // "7:11 PM"
+	GameClock          string `stats:"PCTIMESTRING"` // "11:44"
+	HomeDescription    string `stats:"HOMEDESCRIPTION"`
+	NeutralDescription string `stats:"NEUTRALDESCRIPTION"`
+	VisitDescription   string `stats:"VISITORDESCRIPTION"`
+	Score              string `stats:"SCORE"`       // "2 - 0"
+	ScoreMargin        string `stats:"SCOREMARGIN"` // "-2", "TIE"
+	Person1Type        int    `stats:"PERSON1TYPE"`
+	Player1ID          int    `stats:"PLAYER1_ID"`
+	Player1Name        string `stats:"PLAYER1_NAME"`
+	Player1TeamID      int    `stats:"PLAYER1_TEAM_ID"`
+	Player1TeamAbbr    string `stats:"PLAYER1_TEAM_ABBREVIATION"`
+	Person2Type        int    `stats:"PERSON2TYPE"`
+	Player2ID          int    `stats:"PLAYER2_ID"`
+	Player2Name        string `stats:"PLAYER2_NAME"`
+	Player2TeamID      int    `stats:"PLAYER2_TEAM_ID"`
+	Player2TeamAbbr    string `stats:"PLAYER2_TEAM_ABBREVIATION"`
+	Person3Type        int    `stats:"PERSON3TYPE"`
+	Player3ID          int    `stats:"PLAYER3_ID"`
+	Player3Name        string `stats:"PLAYER3_NAME"`
+	Player3TeamID      int    `stats:"PLAYER3_TEAM_ID"`
+	Player3TeamAbbr    string `stats:"PLAYER3_TEAM_ABBREVIATION"`
+}
+
+//CommonTeamRosterRow ... row of the commonteamroster CommonTeamRoster result set
+type CommonTeamRosterRow struct {
+	TeamID     int     `stats:"TeamID"`
+	Season     string  `stats:"SEASON"` // "2019"
+	LeagueID   string  `stats:"LeagueID"`
+	PlayerName string  `stats:"PLAYER"`     // "Trae Young"
+	Jersey     string  `stats:"NUM"`        // "11"
+	Position   string  `stats:"POSITION"`   // "G", "F-C"
+	Height     string  `stats:"HEIGHT"`     // "6-1"
+	Weight     string  `stats:"WEIGHT"`     // "180"
+	BirthDate  string  `stats:"BIRTH_DATE"` // "SEP 19, 1998"
+	Age        float64 `stats:"AGE"`
+	Experience string  `stats:"EXP"` // "R" for rookies, else years
+	School     string  `stats:"SCHOOL"`
+	PlayerID   int     `stats:"PLAYER_ID"`
+}
+
+//LeagueGameLogRow ... row of the leaguegamelog LeagueGameLog result set (PlayerOrTeam=T)
+type LeagueGameLogRow struct {
+	SeasonID         string  `stats:"SEASON_ID"` // "22019"
+	TeamID           int     `stats:"TEAM_ID"`
+	TeamAbbreviation string  `stats:"TEAM_ABBREVIATION"`
+	TeamName         string  `stats:"TEAM_NAME"`
+	GameID           string  `stats:"GAME_ID"`
+	GameDate         string  `stats:"GAME_DATE"` // "2019-10-22"
+	Matchup          string  `stats:"MATCHUP"`   // "TOR vs. NOP", "NOP @ TOR"
+	WinLoss          string  `stats:"WL"`
+	Minutes          int     `stats:"MIN"`
+	FieldGoalsMade   int     `stats:"FGM"`
+	FieldGoalsAtt    int     `stats:"FGA"`
+	FieldGoalsPct    float64 `stats:"FG_PCT"`
+	ThreePointsMade  int     `stats:"FG3M"`
+	ThreePointsAtt   int     `stats:"FG3A"`
+	ThreePointsPct   float64 `stats:"FG3_PCT"`
+	FreeThrowsMade   int     `stats:"FTM"`
+	FreeThrowsAtt    int     `stats:"FTA"`
+	FreeThrowsPct    float64 `stats:"FT_PCT"`
+	ReboundsOff      int     `stats:"OREB"`
+	ReboundsDef      int     `stats:"DREB"`
+	Rebounds         int     `stats:"REB"`
+	Assists          int     `stats:"AST"`
+	Steals           int     `stats:"STL"`
+	Blocks           int     `stats:"BLK"`
+	Turnovers        int     `stats:"TOV"`
+	PersonalFouls    int     `stats:"PF"`
+	Points           int     `stats:"PTS"`
+	PlusMinus        float64 `stats:"PLUS_MINUS"`
+}
@@ -0,0 +1,238 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+//navigation link with php source: http://nbasense.com/nba-api/Stats/Stats/
+// most stats.nba.com/stats/* endpoints return a table oriented envelope:
+//{"resource":"boxscore","parameters":{"GameID":"0021900001",...},
+// "resultSets":[{"name":"PlayerStats","headers":["GAME_ID","TEAM_ID",...],"rowSet":[["0021900001",1610612761,...],...]}]}
+// a few (e.g. leagueleaders) use a single "resultSet" object instead of the "resultSets" array
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+)
+
+const (
+	//statsTag is the struct tag used to bind a typed row field to a resultSet header
+	statsTag = "stats"
+)
+
+type _StatsResultSets StatsResultSets // preventing recursion
+
+//StatsResultSets is the envelope returned by the stats.nba.com/stats/ endpoints
+type StatsResultSets struct {
+	Resource   string                 `json:"resource"`             // e.g. "boxscore"
+	Parameters map[string]interface{} `json:"parameters,omitempty"` // echo of the request parameters
+	ResultSets []ResultSet            `json:"resultSets"`
+	ResultSet  *ResultSet             `json:"resultSet,omitempty"` // singular form used by some endpoints
+}
+
+//ResultSet is a single named table of headers and rows
+type ResultSet struct {
+	Name    string          `json:"name"`    // e.g. "PlayerStats"
+	Headers []string        `json:"headers"` // e.g. ["GAME_ID","TEAM_ID",...]
+	RowSet  [][]interface{} `json:"rowSet"`  // rows aligned to Headers
+}
+
+// UnmarshalJSON -- custom json Unmarshal, folds the singular "resultSet" form into ResultSets so that
+// callers only ever navigate one shape
+func (rs *StatsResultSets) UnmarshalJSON(bs []byte) error {
+	t := _StatsResultSets{}
+	if err := json.Unmarshal(bs, &t); err != nil {
+		return err
+	}
+	if t.ResultSet != nil {
+		t.ResultSets = append(t.ResultSets, *t.ResultSet)
+		t.ResultSet = nil
+	}
+	*rs = StatsResultSets(t)
+	return nil
+}
+
+//Set returns the named result set e.g. "PlayByPlay" or an error if the envelope does not include it
+func (rs *StatsResultSets) Set(name string) (*ResultSet, error) {
+	for i := range rs.ResultSets {
+		if rs.ResultSets[i].Name == name {
+			return &rs.ResultSets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("resultSet %s not found in %s response", name, rs.Resource)
+}
+
+//Decode copies the named result set into v, which must be a pointer to a slice of structs (or struct pointers)
+//whose fields are tagged with the header they bind to e.g. `stats:"PLAYER_ID"`
+func (rs *StatsResultSets) Decode(name string, v interface{}) error {
+	set, err := rs.Set(name)
+	if err != nil {
+		return err
+	}
+	return set.Decode(v)
+}
+
+//Decode turns the header/rowSet table into typed rows, see StatsResultSets.Decode.  Headers without
+//a tagged field are ignored, tagged fields without a header are left at their zero value
+func (r *ResultSet) Decode(v interface{}) error {
+	pv := reflect.ValueOf(v)
+	if pv.Kind() != reflect.Ptr || pv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("resultSet %s: decode target must be a pointer to a slice, got %T", r.Name, v)
+	}
+	slice := pv.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("resultSet %s: decode target must be a slice of structs, got %T", r.Name, v)
+	}
+
+	// map each header to the field index that binds it
+	columns := make(map[int]int)
+	for i := 0; i < structType.NumField(); i++ {
+		tag := structType.Field(i).Tag.Get(statsTag)
+		if tag == "" || tag == "-" {
+			continue
+		}
+		for c, header := range r.Headers {
+			if header == tag {
+				columns[c] = i
+			}
+		}
+	}
+
+	rows := reflect.MakeSlice(slice.Type(), 0, len(r.RowSet))
+	for n, row := range r.RowSet {
+		elem := reflect.New(structType).Elem()
+		for c, field := range columns {
+			if c >= len(row) {
+				continue
+			}
+			if err := assignStatsValue(elem.Field(field), row[c]); err != nil {
+				return fmt.Errorf("resultSet %s row %d column %s: %s", r.Name, n, r.Headers[c], err)
+			}
+		}
+		if isPtr {
+			rows = reflect.Append(rows, elem.Addr())
+		} else {
+			rows = reflect.Append(rows, elem)
+		}
+	}
+	slice.Set(rows)
+	return nil
+}
+
+//assignStatsValue converts a generic JSON cell (nil, float64, string, bool) into the kind of field
+func assignStatsValue(f reflect.Value, raw interface{}) error {
+	if raw == nil {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		switch val := raw.(type) {
+		case string:
+			f.SetString(val)
+		case float64:
+			f.SetString(strconv.FormatFloat(val, 'f', -1, 64))
+		case bool:
+			f.SetString(strconv.FormatBool(val))
+		default:
+			return fmt.Errorf("cannot assign %T to string", raw)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch val := raw.(type) {
+		case float64:
+			f.SetInt(int64(val))
+		case string:
+			if val == "" {
+				f.SetInt(0)
+				return nil
+			}
+			i, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				log.Printf("Error strconv.ParseInt stats value convert, %s", val)
+				return err
+			}
+			f.SetInt(i)
+		case bool:
+			if val {
+				f.SetInt(1)
+			} else {
+				f.SetInt(0)
+			}
+		default:
+			return fmt.Errorf("cannot assign %T to int", raw)
+		}
+	case reflect.Float32, reflect.Float64:
+		switch val := raw.(type) {
+		case float64:
+			f.SetFloat(val)
+		case string:
+			if val == "" {
+				f.SetFloat(0.0)
+				return nil
+			}
+			fl, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				log.Printf("Error strconv.ParseFloat stats value convert, %s", val)
+				return err
+			}
+			f.SetFloat(fl)
+		default:
+			return fmt.Errorf("cannot assign %T to float", raw)
+		}
+	case reflect.Bool:
+		switch val := raw.(type) {
+		case bool:
+			f.SetBool(val)
+		case float64:
+			f.SetBool(val != 0)
+		case string:
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return err
+			}
+			f.SetBool(b)
+		default:
+			return fmt.Errorf("cannot assign %T to bool", raw)
+		}
+	case reflect.Interface:
+		f.Set(reflect.ValueOf(raw))
+	default:
+		return fmt.Errorf("unsupported field kind %s", f.Kind())
+	}
+	return nil
+}
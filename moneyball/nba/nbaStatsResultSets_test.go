@@ -0,0 +1,101 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const boxScoreResultSets = `{"resource":"boxscore","parameters":{"GameID":"0021900001"},
+	"resultSets":[
+		{"name":"PlayerStats",
+		 "headers":["GAME_ID","TEAM_ID","TEAM_ABBREVIATION","PLAYER_ID","PLAYER_NAME","START_POSITION","MIN","FGM","FG_PCT","PTS","PLUS_MINUS"],
+		 "rowSet":[["0021900001",1610612761,"TOR",200768,"Kyle Lowry","G","36:34",4,0.308,22,8.0],
+		           ["0021900001",1610612761,"TOR",1627832,"Fred VanVleet","","38:00",11,0.5,34,null]]},
+		{"name":"TeamStats","headers":["GAME_ID","TEAM_ID","PTS"],"rowSet":[["0021900001",1610612761,130]]}]}`
+
+const leadersResultSet = `{"resource":"leagueleaders","parameters":{},
+	"resultSet":{"name":"LeagueLeaders","headers":["PLAYER_ID","PLAYER"],"rowSet":[[201935,"James Harden"]]}}`
+
+func TestResultSetsDecode(t *testing.T) {
+	rs := StatsResultSets{}
+	err := json.Unmarshal([]byte(boxScoreResultSets), &rs)
+	assert.Nil(t, err, err)
+	assert.Equal(t, 2, len(rs.ResultSets))
+
+	players := []BoxScorePlayerRow{}
+	err = rs.Decode("PlayerStats", &players)
+	assert.Nil(t, err, err)
+	assert.Equal(t, 2, len(players))
+	assert.Equal(t, 200768, players[0].PlayerID)
+	assert.Equal(t, "G", players[0].StartPosition)
+	assert.Equal(t, "36:34", players[0].Minutes)
+	assert.Equal(t, 0.308, players[0].FieldGoalsPct)
+	assert.Equal(t, 34, players[1].Points)
+	assert.Equal(t, 0.0, players[1].PlusMinus, "null cells decode to the zero value")
+
+	teams := []*BoxScoreTeamRow{}
+	err = rs.Decode("TeamStats", &teams)
+	assert.Nil(t, err, err)
+	assert.Equal(t, 130, teams[0].Points)
+
+	err = rs.Decode("TeamStarterBenchStats", &teams)
+	assert.NotNil(t, err, "missing result set should be an error")
+	err = rs.Decode("PlayerStats", players)
+	assert.NotNil(t, err, "non pointer target should be an error")
+}
+
+func TestResultSetSingular(t *testing.T) {
+	rs := StatsResultSets{}
+	err := json.Unmarshal([]byte(leadersResultSet), &rs)
+	assert.Nil(t, err, err)
+	set, err := rs.Set("LeagueLeaders")
+	assert.Nil(t, err, err)
+	assert.Equal(t, 1, len(set.RowSet))
+}
+
+func TestStatsEndpointQuery(t *testing.T) {
+	ep, err := LookupStatsEndpoint(PlayByPlayEndpoint)
+	assert.Nil(t, err, err)
+	_, err = ep.Query(map[string]string{})
+	assert.NotNil(t, err, "GameID is required")
+	q, err := ep.Query(map[string]string{"GameID": "0021900001", "EndPeriod": "4"})
+	assert.Nil(t, err, err)
+	assert.Equal(t, "0", q.Get("StartPeriod"))
+	assert.Equal(t, "4", q.Get("EndPeriod"))
+	assert.Equal(t, "stats/playbyplayv2", ep.Path())
+
+	_, err = LookupStatsEndpoint("nosuchendpoint")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,100 @@
+package nba
+
+/**
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+//navigation link with php source: http://nbasense.com/nba-api/Data/Prod/Teams
+//http://data.nba.net/prod/v2/{year}/teams.json e.g. http://data.nba.net/prod/v2/2019/teams.json
+// "league":{"standard":[{"isNBAFranchise":true,"isAllStar":false,"city":"Atlanta","altCityName":"Atlanta",
+//	"fullName":"Atlanta Hawks","tricode":"ATL","teamId":"1610612737","nickname":"Hawks","urlName":"hawks",
+//	"teamShortName":"Atlanta","confName":"East","divName":"Southeast"},...]}
+
+import (
+	"go-moneyball/moneyball/ms"
+)
+
+//CMSProdv2Teams ... based upon this structure http://data.nba.net/prod/v2/2019/teams.json
+type CMSProdv2Teams struct {
+	InternalStuff InternalProdv2 `json:"_internal"` //"_internal":{}
+	League        TeamsLeagues   `json:"league"`    //"league":{}
+}
+
+//TeamsLeagues ... "standard" is the NBA, other keys are summer leagues etc.
+type TeamsLeagues struct {
+	Standard []TeamDirectoryEntry `json:"standard"` //"standard":[]
+}
+
+//TeamDirectoryEntry ...
+type TeamDirectoryEntry struct {
+	IsNBAFranchise bool   `json:"isNBAFranchise"` //"isNBAFranchise":true,
+	IsAllStar      bool   `json:"isAllStar"`      //"isAllStar":false,
+	City           string `json:"city"`           //"city":"Atlanta",
+	AltCityName    string `json:"altCityName"`    //"altCityName":"Atlanta",
+	FullName       string `json:"fullName"`       //"fullName":"Atlanta Hawks",
+	Tricode        string `json:"tricode"`        //"tricode":"ATL",
+	TeamID         string `json:"teamId"`         //"teamId":"1610612737",
+	Nickname       string `json:"nickname"`       //"nickname":"Hawks",
+	URLName        string `json:"urlName"`        //"urlName":"hawks",
+	TeamShortName  string `json:"teamShortName"`  //"teamShortName":"Atlanta",
+	ConfName       string `json:"confName"`       //"confName":"East",
+	DivName        string `json:"divName"`        //"divName":"Southeast"
+}
+
+//MarshalMS marshals the NBA franchises in the directory to ms.Team, all-star and other non franchise teams are skipped
+func (d *CMSProdv2Teams) MarshalMS() ([]*ms.Team, error) {
+	teams := []*ms.Team{}
+	for _, entry := range d.League.Standard {
+		if !entry.IsNBAFranchise || entry.IsAllStar {
+			continue
+		}
+		teams = append(teams, &ms.Team{
+			TeamIDNBA:    entry.TeamID,
+			Abbreviation: entry.Tricode,
+			Name:         entry.Nickname,
+			Location:     entry.City,
+			Conference:   entry.ConfName,
+			Division:     entry.DivName,
+		})
+	}
+	return teams, nil
+}
+
+//RegisterTeams refreshes the registry from the directory and returns the canonical teams
+func (d *CMSProdv2Teams) RegisterTeams(r *ms.TeamRegistry) ([]*ms.Team, error) {
+	teams, err := d.MarshalMS()
+	if err != nil {
+		return nil, err
+	}
+	canon := []*ms.Team{}
+	for _, t := range teams {
+		canon = append(canon, r.Register(t))
+	}
+	return canon, nil
+}
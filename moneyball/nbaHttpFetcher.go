@@ -42,9 +42,12 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"go-moneyball/moneyball/ms"
 	"go-moneyball/moneyball/nba"
+	"net/http"
 	"net/url"
 	"os"
 	"strconv"
@@ -236,3 +239,160 @@ func (s *ScheduleService) NBAScheduleServicev2(ctx context.Context, modifier map
 	}
 	return &event.LeagueSchedule.Events, resp, err
 }
+
+//setNBAStatsHeaders adds the headers stats.nba.com expects from a browser, without them requests tend to hang
+func setNBAStatsHeaders(req *http.Request) {
+	// get useragent from OS Environment Variables -> often needed to prevent robot blocking or API access with lower DoS thresholds
+	agent, exists := os.LookupEnv("NBA_USERAGENT")
+	if exists {
+		req.Header.Set("User-Agent", agent)
+	}
+	req.Header.Set("Referer", nba.NBAStatsBaseURL)
+	req.Header.Set("x-nba-stats-origin", "stats")
+	req.Header.Set("x-nba-stats-token", "true")
+}
+
+//NBAStatsService will, for a http client, fetch one of the catalogued stats.nba.com endpoints (see nba.StatsEndpoints)
+//and return the generic resultSets envelope, use nba.StatsResultSets.Decode to get typed rows
+//		https://stats.nba.com/stats/{endpoint}?{parameters} e.g. https://stats.nba.com/stats/playbyplayv2?GameID=0021900001
+func (s *StatsService) NBAStatsService(ctx context.Context, endpoint string, modifier map[string]string) (*nba.StatsResultSets,
+	*Response, error) {
+
+	ep, err := nba.LookupStatsEndpoint(endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	query, err := ep.Query(modifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.client.BaseURL, _ = url.Parse(nba.NBAStatsBaseURL)
+	req, err := s.client.NewRequest("GET", ep.Path()+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	setNBAStatsHeaders(req)
+
+	rs := &nba.StatsResultSets{}
+	resp, err := s.client.Do(ctx, req, rs, true)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return rs, resp, err
+}
+
+//NBAPlayByPlayService will, for a http client, return the play-by-play for a game, gameDate ("20191022") anchors
+//the wall clock of each play and may be empty
+//		https://stats.nba.com/stats/playbyplayv2?GameID=0021900001&StartPeriod=0&EndPeriod=10
+func (s *StatsService) NBAPlayByPlayService(ctx context.Context, gameID string, gameDate string) (*nba.PlayByPlay,
+	*Response, error) {
+
+	rs, resp, err := s.NBAStatsService(ctx, nba.PlayByPlayEndpoint, map[string]string{"GameID": gameID})
+	if err != nil {
+		return nil, resp, err
+	}
+	pbp := &nba.PlayByPlay{GameID: gameID, GameDate: gameDate}
+	if err := rs.Decode("PlayByPlay", &pbp.Plays); err != nil {
+		log.Printf("Error decoding play-by-play: %s\n", err)
+		return nil, resp, err
+	}
+	return pbp, resp, nil
+}
+
+//NBAStandingsService will, for a http client, return the conference standings, modifier {"date":"20200106"}
+//selects a historic table and defaults to the current one
+//		http://data.nba.net/prod/v1/{date}/standings_conference.json e.g. http://data.nba.net/prod/v1/current/standings_conference.json
+func (s *StatsService) NBAStandingsService(ctx context.Context, modifier map[string]string) (*nba.CMSProdv1Standings,
+	*Response, error) {
+
+	s.client.BaseURL, _ = url.Parse(nba.DataNBABaseURLv2)
+	path := "prod/v1/{date}/standings_conference.json"
+	if _, ok := modifier["date"]; !ok {
+		modifier = map[string]string{"date": "current"}
+	}
+	suffix, err := nbaPathModifier(path, modifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", suffix, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	standings := &nba.CMSProdv1Standings{}
+	resp, err := s.client.Do(ctx, req, standings, true)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return standings, resp, err
+}
+
+//NBATeamsService will, for a http client, return the NBA teams directory for a season, modifier {"year":"2019"}
+//		http://data.nba.net/prod/v2/{year}/teams.json e.g. http://data.nba.net/prod/v2/2019/teams.json
+func (s *StatsService) NBATeamsService(ctx context.Context, modifier map[string]string) (*nba.CMSProdv2Teams,
+	*Response, error) {
+
+	s.client.BaseURL, _ = url.Parse(nba.DataNBABaseURLv2)
+	path := "prod/v2/{year}/teams.json"
+	suffix, err := nbaPathModifier(path, modifier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", suffix, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teams := &nba.CMSProdv2Teams{}
+	resp, err := s.client.Do(ctx, req, teams, true)
+	if err != nil {
+		log.Printf("Error on new request: %s\n", err)
+		return nil, resp, err
+	}
+	return teams, resp, err
+}
+
+//NBATeamRosterService will, for a http client, return a team's roster for a season e.g. ("1610612761", "2019-20")
+//		https://stats.nba.com/stats/commonteamroster?TeamID=1610612761&Season=2019-20&LeagueID=00
+func (s *StatsService) NBATeamRosterService(ctx context.Context, teamID string, season string) (*nba.TeamRoster,
+	*Response, error) {
+
+	rs, resp, err := s.NBAStatsService(ctx, nba.CommonTeamRosterEndpoint, map[string]string{"TeamID": teamID, "Season": season})
+	if err != nil {
+		return nil, resp, err
+	}
+	roster := &nba.TeamRoster{TeamID: teamID, Season: season}
+	if err := rs.Decode("CommonTeamRoster", &roster.Players); err != nil {
+		log.Printf("Error decoding roster: %s\n", err)
+		return nil, resp, err
+	}
+	return roster, resp, nil
+}
+
+//NBAGameRosters fetches the rosters of both teams of a game for its season and attaches them to the game, a team
+//whose roster can't be fetched plays without one and is reported in the error
+func (s *StatsService) NBAGameRosters(ctx context.Context, game *nba.ScheduledGamev2) ([]*ms.TeamSeasonRoster, error) {
+	season := ms.Season{SeasonYear: int(game.SeasonYear)}.DisplayYear(ms.LeagueNBA)
+	rosters := []*ms.TeamSeasonRoster{}
+	errs := []error{}
+	for _, teamID := range []string{game.HomeTeam.TeamID, game.VisitingTeam.TeamID} {
+		r, _, err := s.NBATeamRosterService(ctx, teamID, season)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("team %s roster: %s", teamID, err))
+			continue
+		}
+		roster, err := r.MarshalMS()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rosters = append(rosters, roster)
+	}
+	game.Rosters = append(game.Rosters, rosters...)
+	return rosters, errors.Join(errs...)
+}
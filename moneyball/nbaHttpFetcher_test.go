@@ -33,6 +33,7 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 import (
 	"context"
 	"fmt"
+	"go-moneyball/moneyball/nba"
 	"testing"
 	"time"
 
@@ -136,3 +137,17 @@ func TestPlayerMovementStatsService(t *testing.T) {
 	assert.NotZero(t, len(statstln.StatGroup) > 0, "StatGroup should not be nil")
 	//fmt.Printf("NBAPlayerMovementStatsService: %s StatName with values of %#v retrieved\n", statstln.StatGroupName, statstln.StatGroup)
 }
+
+func TestNBAStatsService(t *testing.T) {
+	client := NewClient(nil)
+	ctx := context.Background()
+	params := map[string]string{
+		"Season": "2019-20",
+	}
+	rs, _, err := client.Stats.NBAStatsService(ctx, nba.LeagueGameLogEndpoint, params)
+	assert.Nil(t, err, err)
+	games := []nba.LeagueGameLogRow{}
+	err = rs.Decode("LeagueGameLog", &games)
+	assert.Nil(t, err, err)
+	assert.NotZero(t, len(games), "leaguegamelog should return games")
+}
@@ -0,0 +1,70 @@
+package main
+
+/**
+Copyright (c) 2013 The go-github AUTHORS. All rights reserved.
+Copyright (c) 2020 DXC Technology - Dan Hushon. All rights reserved
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc., DXC Technology nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+*/
+
+// the team registry draws on both providers, see ms.TeamRegistry
+
+import (
+	"context"
+	"fmt"
+	"go-moneyball/moneyball/espn"
+	"go-moneyball/moneyball/ms"
+	"strings"
+)
+
+//RefreshNBATeamRegistry refreshes the shared NBA team registry from the NBA teams.json (for season year) and the
+//ESPN /teams feeds, a feed that can't be fetched leaves the registry on its offline snapshot and is reported
+func (s *StatsService) RefreshNBATeamRegistry(ctx context.Context, year string) (*ms.TeamRegistry, error) {
+	registry := ms.NBATeamRegistry()
+	var errs []string
+
+	directory, _, err := s.NBATeamsService(ctx, map[string]string{"year": year})
+	if err == nil {
+		_, err = directory.RegisterTeams(registry)
+	}
+	if err != nil {
+		errs = append(errs, "nba teams: "+err.Error())
+	}
+
+	teams, _, err := s.ESPNTeamsService(ctx, espn.NBA)
+	if err == nil {
+		_, err = teams.RegisterTeams(registry)
+	}
+	if err != nil {
+		errs = append(errs, "espn teams: "+err.Error())
+	}
+
+	if len(errs) > 0 {
+		return registry, fmt.Errorf("team registry refresh incomplete: %s", strings.Join(errs, "; "))
+	}
+	return registry, nil
+}